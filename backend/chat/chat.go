@@ -0,0 +1,298 @@
+package chat
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+	"coaching-backend/database"
+	"coaching-backend/events"
+	"coaching-backend/models"
+)
+
+const (
+	DefaultFeedbackTemplate   = "*New feedback for {{.Team}}*{{if .Author}} from {{.Author}}{{end}}\n{{quote .Content}}"
+	DefaultMembershipTemplate = "{{.Person}} {{.Action}} *{{.Team}}*"
+
+	DefaultLimit  = 20
+	DefaultWindow = time.Minute
+)
+
+var Default = NewPoster()
+
+var mrkdwnEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+var templateFuncs = template.FuncMap{
+	"quote": func(s string) string {
+		return ">" + strings.ReplaceAll(strings.TrimSpace(s), "\n", "\n>")
+	},
+}
+
+type TextObject struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type Block struct {
+	Type     string       `json:"type"`
+	Text     *TextObject  `json:"text,omitempty"`
+	Elements []TextObject `json:"elements,omitempty"`
+}
+
+type Message struct {
+	Text   string  `json:"text"`
+	Blocks []Block `json:"blocks"`
+}
+
+type FeedbackData struct {
+	Team     string
+	Author   string
+	Content  string
+	Category string
+	Rating   int
+}
+
+type MembershipData struct {
+	Team   string
+	Person string
+	Action string
+}
+
+type window struct {
+	start time.Time
+	count int
+}
+
+type Poster struct {
+	Client *http.Client
+	Limit  int
+	Window time.Duration
+
+	mu      sync.Mutex
+	windows map[uint]*window
+	wg      sync.WaitGroup
+}
+
+func NewPoster() *Poster {
+	return &Poster{
+		Client:  &http.Client{Timeout: 10 * time.Second},
+		Limit:   DefaultLimit,
+		Window:  DefaultWindow,
+		windows: map[uint]*window{},
+	}
+}
+
+func ValidateTemplates(feedbackTemplate, membershipTemplate string) error {
+	if _, err := RenderFeedback(feedbackTemplate, FeedbackData{Team: "Team", Author: "Author", Content: "Content", Category: "general", Rating: 5}); err != nil {
+		return fmt.Errorf("invalid feedback template: %v", err)
+	}
+	if _, err := RenderMembership(membershipTemplate, MembershipData{Team: "Team", Person: "Person", Action: "joined"}); err != nil {
+		return fmt.Errorf("invalid membership template: %v", err)
+	}
+	return nil
+}
+
+func RenderFeedback(tmpl string, data FeedbackData) (Message, error) {
+	if tmpl == "" {
+		tmpl = DefaultFeedbackTemplate
+	}
+	data.Team = mrkdwnEscaper.Replace(data.Team)
+	data.Author = mrkdwnEscaper.Replace(data.Author)
+	data.Content = mrkdwnEscaper.Replace(data.Content)
+	data.Category = mrkdwnEscaper.Replace(data.Category)
+
+	text, err := render(tmpl, data)
+	if err != nil {
+		return Message{}, err
+	}
+
+	msg := section(text)
+	var context []string
+	if data.Category != "" {
+		context = append(context, "Category: "+data.Category)
+	}
+	if data.Rating > 0 {
+		context = append(context, fmt.Sprintf("Rating: %d/5", data.Rating))
+	}
+	if len(context) > 0 {
+		msg.Blocks = append(msg.Blocks, Block{
+			Type:     "context",
+			Elements: []TextObject{{Type: "mrkdwn", Text: strings.Join(context, " · ")}},
+		})
+	}
+	return msg, nil
+}
+
+func RenderMembership(tmpl string, data MembershipData) (Message, error) {
+	if tmpl == "" {
+		tmpl = DefaultMembershipTemplate
+	}
+	data.Team = mrkdwnEscaper.Replace(data.Team)
+	data.Person = mrkdwnEscaper.Replace(data.Person)
+
+	text, err := render(tmpl, data)
+	if err != nil {
+		return Message{}, err
+	}
+	return section(text), nil
+}
+
+func Text(text string) Message {
+	return section(mrkdwnEscaper.Replace(text))
+}
+
+func (p *Poster) Handle(event events.Event) {
+	switch event.Type {
+	case events.FeedbackCreated:
+		var feedback models.Feedback
+		if !decode(event, &feedback) || feedback.TargetType != "team" {
+			return
+		}
+		team, ok := loadTeam(feedback.TargetID)
+		if !ok || (feedback.Private && !team.Chat.IncludePrivate) {
+			return
+		}
+
+		data := FeedbackData{Team: team.Name, Content: feedback.Content, Category: feedback.Category}
+		if feedback.Rating != nil {
+			data.Rating = *feedback.Rating
+		}
+		if feedback.AuthorID != nil {
+			var author models.Person
+			if err := database.GetDB().Select("name").First(&author, *feedback.AuthorID).Error; err == nil {
+				data.Author = author.Name
+			}
+		}
+		msg, err := RenderFeedback(team.Chat.FeedbackTemplate, data)
+		p.post(team, msg, err)
+	case events.PersonAssignedToTeam:
+		var assignment models.TeamAssignment
+		if !decode(event, &assignment) || assignment.TeamID == nil {
+			return
+		}
+		if assignment.PreviousTeamID != nil && *assignment.PreviousTeamID != *assignment.TeamID {
+			p.membership(assignment.Person, *assignment.PreviousTeamID, "left")
+		}
+		p.membership(assignment.Person, *assignment.TeamID, "joined")
+	case events.PersonRemovedFromTeam:
+		var payload struct {
+			Person models.Person `json:"person"`
+			TeamID uint          `json:"team_id"`
+		}
+		if !decode(event, &payload) {
+			return
+		}
+		p.membership(payload.Person, payload.TeamID, "left")
+	}
+}
+
+func (p *Poster) Wait() {
+	p.wg.Wait()
+}
+
+func (p *Poster) Send(webhookURL string, msg Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	resp, err := p.Client.Post(webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		reply, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return fmt.Errorf("chat webhook returned %d: %s", resp.StatusCode, strings.TrimSpace(string(reply)))
+	}
+	return nil
+}
+
+func (p *Poster) membership(person models.Person, teamID uint, action string) {
+	team, ok := loadTeam(teamID)
+	if !ok {
+		return
+	}
+	msg, err := RenderMembership(team.Chat.MembershipTemplate, MembershipData{Team: team.Name, Person: person.Name, Action: action})
+	p.post(team, msg, err)
+}
+
+func (p *Poster) post(team models.Team, msg Message, err error) {
+	if err != nil {
+		log.Printf("chat: failed to render message for team %d: %v", team.ID, err)
+		return
+	}
+	if !p.allow(team.ID, time.Now()) {
+		log.Printf("chat: rate limit reached for team %d, message dropped", team.ID)
+		return
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		if err := p.Send(team.Chat.WebhookURL, msg); err != nil {
+			log.Printf("chat: failed to post to team %d: %v", team.ID, err)
+		}
+	}()
+}
+
+func (p *Poster) allow(teamID uint, now time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	w, ok := p.windows[teamID]
+	if !ok || now.Sub(w.start) >= p.Window {
+		p.windows[teamID] = &window{start: now, count: 1}
+		return true
+	}
+	if w.count >= p.Limit {
+		return false
+	}
+	w.count++
+	return true
+}
+
+func loadTeam(id uint) (models.Team, bool) {
+	var team models.Team
+	if err := database.GetDB().First(&team, id).Error; err != nil {
+		return team, false
+	}
+	return team, team.Chat.Enabled && team.Chat.WebhookURL != ""
+}
+
+func render(tmpl string, data interface{}) (string, error) {
+	t, err := template.New("chat").Funcs(templateFuncs).Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func section(text string) Message {
+	return Message{
+		Text:   text,
+		Blocks: []Block{{Type: "section", Text: &TextObject{Type: "mrkdwn", Text: text}}},
+	}
+}
+
+func decode(event events.Event, v interface{}) bool {
+	raw, ok := event.Data.(json.RawMessage)
+	if !ok {
+		var err error
+		if raw, err = json.Marshal(event.Data); err != nil {
+			return false
+		}
+	}
+	return json.Unmarshal(raw, v) == nil
+}
@@ -0,0 +1,199 @@
+package chat
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"coaching-backend/database"
+	"coaching-backend/events"
+	"coaching-backend/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type chatReceiver struct {
+	server *httptest.Server
+	status int
+
+	mu       sync.Mutex
+	messages []Message
+}
+
+func newChatReceiver(t *testing.T) *chatReceiver {
+	receiver := &chatReceiver{status: http.StatusOK}
+	receiver.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg Message
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+
+		receiver.mu.Lock()
+		receiver.messages = append(receiver.messages, msg)
+		receiver.mu.Unlock()
+
+		w.WriteHeader(receiver.status)
+		w.Write([]byte("ok"))
+	}))
+	t.Cleanup(receiver.server.Close)
+	return receiver
+}
+
+func (r *chatReceiver) received() []Message {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Message(nil), r.messages...)
+}
+
+func setupTestDB(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("Failed to access test database: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	database.DB = db
+}
+
+func createChatTeam(t *testing.T, name string, chat models.TeamChat) models.Team {
+	team := models.Team{Name: name, Chat: chat}
+	if err := database.GetDB().Create(&team).Error; err != nil {
+		t.Fatalf("Failed to create team: %v", err)
+	}
+	return team
+}
+
+func TestRenderFeedback(t *testing.T) {
+	t.Run("should escape content and add a context block", func(t *testing.T) {
+		msg, err := RenderFeedback("", FeedbackData{Team: "R&D", Author: "Ann", Content: "Ship <it>\nnow", Category: "delivery", Rating: 4})
+		assert.NoError(t, err)
+
+		assert.Equal(t, "*New feedback for R&amp;D* from Ann\n>Ship &lt;it&gt;\n>now", msg.Text)
+		assert.Len(t, msg.Blocks, 2)
+		assert.Equal(t, "section", msg.Blocks[0].Type)
+		assert.Equal(t, "mrkdwn", msg.Blocks[0].Text.Type)
+		assert.Equal(t, "Category: delivery · Rating: 4/5", msg.Blocks[1].Elements[0].Text)
+	})
+
+	t.Run("should use a custom template", func(t *testing.T) {
+		msg, err := RenderFeedback(":star: {{.Team}}: {{.Content}}", FeedbackData{Team: "Core", Content: "Great"})
+		assert.NoError(t, err)
+		assert.Equal(t, ":star: Core: Great", msg.Text)
+		assert.Len(t, msg.Blocks, 1)
+	})
+
+	t.Run("should reject broken templates", func(t *testing.T) {
+		assert.Error(t, ValidateTemplates("{{.Team", ""))
+		assert.Error(t, ValidateTemplates("", "{{.Unknown}}"))
+		assert.NoError(t, ValidateTemplates("", ""))
+	})
+}
+
+func TestPosterHandle(t *testing.T) {
+	setupTestDB(t)
+	receiver := newChatReceiver(t)
+	poster := NewPoster()
+
+	team := createChatTeam(t, "Chatty", models.TeamChat{WebhookURL: receiver.server.URL, Enabled: true})
+	quiet := createChatTeam(t, "Quiet", models.TeamChat{WebhookURL: receiver.server.URL, Enabled: false})
+	author := models.Person{Name: "Ann", Email: "ann@example.com"}
+	assert.NoError(t, database.GetDB().Create(&author).Error)
+
+	t.Run("should post team feedback", func(t *testing.T) {
+		feedback := models.Feedback{ID: 1, Content: "Great launch", TargetType: "team", TargetID: team.ID, AuthorID: &author.ID}
+		poster.Handle(events.New(events.FeedbackCreated, feedback))
+		poster.Wait()
+
+		received := receiver.received()
+		assert.Len(t, received, 1)
+		assert.Equal(t, "*New feedback for Chatty* from Ann\n>Great launch", received[0].Text)
+	})
+
+	t.Run("should skip private, personal and disabled feedback", func(t *testing.T) {
+		before := len(receiver.received())
+		poster.Handle(events.New(events.FeedbackCreated, models.Feedback{ID: 2, Content: "Private", TargetType: "team", TargetID: team.ID, Private: true}))
+		poster.Handle(events.New(events.FeedbackCreated, models.Feedback{ID: 3, Content: "Personal", TargetType: "person", TargetID: author.ID}))
+		poster.Handle(events.New(events.FeedbackCreated, models.Feedback{ID: 4, Content: "Muted", TargetType: "team", TargetID: quiet.ID}))
+		poster.Wait()
+
+		assert.Len(t, receiver.received(), before)
+	})
+
+	t.Run("should post private feedback when the team opts in", func(t *testing.T) {
+		open := createChatTeam(t, "Open", models.TeamChat{WebhookURL: receiver.server.URL, Enabled: true, IncludePrivate: true})
+		before := len(receiver.received())
+
+		poster.Handle(events.New(events.FeedbackCreated, models.Feedback{ID: 5, Content: "Private", TargetType: "team", TargetID: open.ID, Private: true}))
+		poster.Wait()
+
+		assert.Len(t, receiver.received(), before+1)
+	})
+
+	t.Run("should announce membership changes", func(t *testing.T) {
+		before := len(receiver.received())
+		member := models.Person{ID: 10, Name: "Ben", TeamID: &team.ID}
+
+		poster.Handle(events.New(events.PersonAssignedToTeam, member))
+		poster.Wait()
+		payload, _ := json.Marshal(map[string]interface{}{"person": member, "team_id": team.ID})
+		poster.Handle(events.Event{Type: events.PersonRemovedFromTeam, Data: json.RawMessage(payload)})
+		poster.Wait()
+
+		received := receiver.received()[before:]
+		assert.Len(t, received, 2)
+		assert.Equal(t, "Ben joined *Chatty*", received[0].Text)
+		assert.Equal(t, "Ben left *Chatty*", received[1].Text)
+	})
+
+	t.Run("should announce a move to both teams", func(t *testing.T) {
+		other := createChatTeam(t, "Other", models.TeamChat{WebhookURL: receiver.server.URL, Enabled: true})
+		before := len(receiver.received())
+		member := models.Person{ID: 11, Name: "Cleo", TeamID: &other.ID}
+
+		poster.Handle(events.New(events.PersonAssignedToTeam, models.TeamAssignment{Person: member, PreviousTeamID: &team.ID}))
+		poster.Wait()
+
+		received := receiver.received()[before:]
+		assert.Len(t, received, 2)
+		texts := []string{received[0].Text, received[1].Text}
+		assert.Contains(t, texts, "Cleo left *Chatty*")
+		assert.Contains(t, texts, "Cleo joined *Other*")
+	})
+}
+
+func TestPosterRateLimit(t *testing.T) {
+	setupTestDB(t)
+	receiver := newChatReceiver(t)
+	poster := NewPoster()
+	poster.Limit = 2
+
+	team := createChatTeam(t, "Busy", models.TeamChat{WebhookURL: receiver.server.URL, Enabled: true})
+	for i := uint(1); i <= 4; i++ {
+		poster.Handle(events.New(events.FeedbackCreated, models.Feedback{ID: i, Content: "Again", TargetType: "team", TargetID: team.ID}))
+	}
+	poster.Wait()
+	assert.Len(t, receiver.received(), 2)
+
+	now := time.Now()
+	assert.False(t, poster.allow(team.ID, now))
+	assert.True(t, poster.allow(team.ID, now.Add(poster.Window)))
+	assert.True(t, poster.allow(team.ID+1, now))
+}
+
+func TestPosterSend(t *testing.T) {
+	receiver := newChatReceiver(t)
+	receiver.status = http.StatusNotFound
+
+	err := NewPoster().Send(receiver.server.URL, Text("hello"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+}
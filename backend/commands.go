@@ -0,0 +1,296 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"coaching-backend/config"
+	"coaching-backend/database"
+	"coaching-backend/encryption"
+	"coaching-backend/importer"
+	"coaching-backend/models"
+	"coaching-backend/notifications"
+	"coaching-backend/retention"
+	"coaching-backend/sentiment"
+	"gorm.io/gorm"
+)
+
+var commands = map[string]func(args []string) error{
+	"backfill-sentiment": backfillSentimentCommand,
+	"send-digests":       sendDigestsCommand,
+	"import":             importCommand,
+	"apply-retention":    applyRetentionCommand,
+	"reencrypt-feedback": reencryptFeedbackCommand,
+}
+
+func runCommand(args []string) error {
+	command, ok := commands[args[0]]
+	if !ok {
+		names := make([]string, 0, len(commands))
+		for name := range commands {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("unknown command %q, expected one of: %s", args[0], strings.Join(names, ", "))
+	}
+	return command(args[1:])
+}
+
+func backfillSentimentCommand(args []string) error {
+	flags := flag.NewFlagSet("backfill-sentiment", flag.ContinueOnError)
+	all := flags.Bool("all", false, "rescore every feedback, not only those without a score")
+	batchSize := flags.Int("batch", 200, "number of feedbacks to load per batch")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	scored, err := backfillSentiment(database.GetDB(), *all, *batchSize)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Scored sentiment for %d feedbacks", scored)
+	return nil
+}
+
+func backfillSentiment(db *gorm.DB, all bool, batchSize int) (int, error) {
+	query := db.Model(&models.Feedback{})
+	if !all {
+		query = query.Where("sentiment = ? OR sentiment IS NULL", "")
+	}
+
+	scored := 0
+	var batch []models.Feedback
+	result := query.FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+		for _, feedback := range batch {
+			score := sentiment.Analyze(feedback.Content)
+			if err := db.Model(&models.Feedback{}).Where("id = ?", feedback.ID).
+				UpdateColumns(map[string]interface{}{"sentiment": score.Label, "sentiment_score": score.Score}).Error; err != nil {
+				return err
+			}
+			scored++
+		}
+		return nil
+	})
+
+	return scored, result.Error
+}
+
+func sendDigestsCommand(args []string) error {
+	flags := flag.NewFlagSet("send-digests", flag.ContinueOnError)
+	frequency := flags.String("frequency", models.EmailDaily, "digest to send: daily or weekly")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg := config.Load()
+	if cfg.SMTPHost == "" {
+		return fmt.Errorf("SMTP_HOST must be set to send digests")
+	}
+
+	sent, err := notifications.NewNotifier(smtpMailer(cfg)).SendDigests(*frequency)
+	log.Printf("Sent %d %s digests", sent, *frequency)
+	return err
+}
+
+func smtpMailer(cfg *config.Config) notifications.SMTPMailer {
+	return notifications.SMTPMailer{
+		Host:     cfg.SMTPHost,
+		Port:     cfg.SMTPPort,
+		Username: cfg.SMTPUsername,
+		Password: cfg.SMTPPassword,
+		From:     cfg.SMTPFrom,
+	}
+}
+
+func importCommand(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	kind := flags.String("type", "persons", "what the file contains: persons or teams")
+	file := flags.String("file", "", "CSV or JSON file to import")
+	format := flags.String("format", "", "csv or json (default: from the file extension)")
+	dryRun := flags.Bool("dry-run", false, "validate every row and roll back")
+	atomic := flags.Bool("atomic", false, "import nothing if any row fails")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return fmt.Errorf("-file is required")
+	}
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*file)), ".")
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	opts := importer.Options{DryRun: *dryRun, Atomic: *atomic}
+	var result importer.Result
+	switch *kind {
+	case "persons":
+		rows, err := importer.ParsePersons(f, *format)
+		if err != nil {
+			return err
+		}
+		result, err = importer.ImportPersons(database.GetDB(), rows, opts)
+		if err != nil {
+			return err
+		}
+	case "teams":
+		rows, err := importer.ParseTeams(f, *format)
+		if err != nil {
+			return err
+		}
+		result, err = importer.ImportTeams(database.GetDB(), rows, opts)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown import type %q, expected persons or teams", *kind)
+	}
+
+	for _, rowErr := range result.Errors {
+		log.Printf("Row %d: %s", rowErr.Row, rowErr.Error)
+	}
+	log.Printf("Imported %d rows: %d created, %d updated, %d unchanged, %d failed, %d teams created (committed: %t)",
+		result.Rows, result.Created, result.Updated, result.Unchanged, result.Failed, result.TeamsCreated, result.Committed)
+	if result.Failed > 0 {
+		return fmt.Errorf("%d of %d rows failed", result.Failed, result.Rows)
+	}
+	return nil
+}
+
+func applyRetentionCommand(args []string) error {
+	flags := flag.NewFlagSet("apply-retention", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "report what the rules would delete or anonymize without changing anything")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	run, err := retention.Apply(database.GetDB(), time.Now(), *dryRun, models.RetentionTriggerCommand)
+	for _, rule := range run.Rules {
+		log.Printf("Rule %q (%s): %d affected, %d held", rule.Name, rule.Action, rule.Affected, rule.Held)
+	}
+	log.Printf("Scanned %d feedbacks: %d deleted, %d anonymized, %d held (dry run: %t)",
+		run.Scanned, run.Deleted, run.Anonymized, run.Held, run.DryRun)
+	return err
+}
+
+func reencryptFeedbackCommand(args []string) error {
+	flags := flag.NewFlagSet("reencrypt-feedback", flag.ContinueOnError)
+	batchSize := flags.Int("batch", 200, "number of rows to load per batch")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if !encryption.Enabled() {
+		return fmt.Errorf("ENCRYPTION_KEYS or ENCRYPTION_KEY_FILE must be set to re-encrypt feedback")
+	}
+
+	feedbacks, err := reencryptTable(database.GetDB(), "feedbacks", []string{"content", "target_name"}, map[string]string{"target_name_index": "target_name"}, *batchSize)
+	if err != nil {
+		return err
+	}
+	answers, err := reencryptTable(database.GetDB(), "feedback_answers", []string{"value"}, nil, *batchSize)
+	if err != nil {
+		return err
+	}
+	// Event payloads carry copies of feedback.
+	outboxEvents, err := reencryptTable(database.GetDB(), "outbox_events", []string{"payload"}, nil, *batchSize)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Re-encrypted %d feedbacks, %d answers and %d events with key %q", feedbacks, answers, outboxEvents, encryption.ActiveKeyID())
+	return nil
+}
+
+// reencryptTable rewrites the encrypted columns of every row with the active
+// key, which also encrypts rows stored before encryption was turned on.
+// indexes maps blind index columns to the column they index; they are
+// recomputed as well. A row changed by someone else in the meantime is
+// skipped, since it was already written with the active key.
+func reencryptTable(db *gorm.DB, table string, columns []string, indexes map[string]string, batchSize int) (int, error) {
+	selected := append([]string{"id"}, columns...)
+	for column := range indexes {
+		selected = append(selected, column)
+	}
+
+	updated := 0
+	lastID := 0
+	for {
+		var rows []map[string]interface{}
+		if err := db.Table(table).Select(selected).Where("id > ?", lastID).Order("id").Limit(batchSize).Find(&rows).Error; err != nil {
+			return updated, err
+		}
+		if len(rows) == 0 {
+			return updated, nil
+		}
+
+		for _, row := range rows {
+			id, err := strconv.Atoi(columnString(row["id"]))
+			if err != nil {
+				return updated, fmt.Errorf("%s: invalid id %v", table, row["id"])
+			}
+			lastID = id
+
+			changes := map[string]interface{}{}
+			plaintext := map[string]string{}
+			for _, column := range columns {
+				value := columnString(row[column])
+				if plaintext[column], err = encryption.Decrypt(column, value); err != nil {
+					return updated, fmt.Errorf("%s %d: %w", table, id, err)
+				}
+				rotated, changed, err := encryption.Rotate(column, value)
+				if err != nil {
+					return updated, fmt.Errorf("%s %d: %w", table, id, err)
+				}
+				if changed {
+					changes[column] = rotated
+				}
+			}
+			for column, source := range indexes {
+				if index := encryption.BlindIndex(plaintext[source]); index != columnString(row[column]) {
+					changes[column] = index
+				}
+			}
+			if len(changes) == 0 {
+				continue
+			}
+
+			query := db.Table(table).Where("id = ?", id)
+			for _, column := range columns {
+				if row[column] == nil {
+					query = query.Where(column + " IS NULL")
+				} else {
+					query = query.Where(column+" = ?", columnString(row[column]))
+				}
+			}
+			result := query.UpdateColumns(changes)
+			if result.Error != nil {
+				return updated, result.Error
+			}
+			updated += int(result.RowsAffected)
+		}
+	}
+}
+
+func columnString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
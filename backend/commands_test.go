@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"coaching-backend/database"
+	"coaching-backend/encryption"
+	"coaching-backend/events"
+	"coaching-backend/models"
+	"coaching-backend/outbox"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunCommand(t *testing.T) {
+	t.Run("should reject unknown commands", func(t *testing.T) {
+		err := runCommand([]string{"unknown"})
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "backfill-sentiment")
+	})
+}
+
+func TestBackfillSentiment(t *testing.T) {
+	database.DB = setupTestDB()
+	person := createTestPerson(t, "Backfill Person", "backfill@example.com", "")
+
+	praise := createTestFeedback(t, "Excellent presentation, really helpful", "person", person.ID, person.Name)
+	criticism := createTestFeedback(t, "The report was late and sloppy", "person", person.ID, person.Name)
+	scored := createTestFeedback(t, "Already scored", "person", person.ID, person.Name)
+	assert.NoError(t, database.GetDB().Model(&scored).UpdateColumns(map[string]interface{}{"sentiment": "positive", "sentiment_score": 0.9}).Error)
+
+	t.Run("should score only feedback without a sentiment", func(t *testing.T) {
+		err := runCommand([]string{"backfill-sentiment", "-batch", "1"})
+		assert.NoError(t, err)
+
+		var feedbacks []models.Feedback
+		assert.NoError(t, database.GetDB().Order("id").Find(&feedbacks).Error)
+		assert.Equal(t, praise.ID, feedbacks[0].ID)
+		assert.Equal(t, "positive", feedbacks[0].Sentiment)
+		assert.Equal(t, criticism.ID, feedbacks[1].ID)
+		assert.Equal(t, "negative", feedbacks[1].Sentiment)
+		assert.Equal(t, 0.9, feedbacks[2].SentimentScore)
+	})
+
+	t.Run("should rescore everything with -all", func(t *testing.T) {
+		count, err := backfillSentiment(database.GetDB(), true, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, count)
+
+		var rescored models.Feedback
+		assert.NoError(t, database.GetDB().First(&rescored, scored.ID).Error)
+		assert.Equal(t, "neutral", rescored.Sentiment)
+	})
+}
+
+func TestSendDigestsCommand(t *testing.T) {
+	t.Run("should require an SMTP server", func(t *testing.T) {
+		os.Unsetenv("SMTP_HOST")
+
+		err := runCommand([]string{"send-digests", "-frequency", "weekly"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "SMTP_HOST")
+	})
+
+	t.Run("should reject unknown flags", func(t *testing.T) {
+		err := runCommand([]string{"send-digests", "-hourly"})
+		assert.Error(t, err)
+	})
+}
+
+func TestImportCommand(t *testing.T) {
+	database.DB = setupTestDB()
+	dir := t.TempDir()
+
+	t.Run("should import a CSV file", func(t *testing.T) {
+		path := filepath.Join(dir, "people.csv")
+		assert.NoError(t, os.WriteFile(path, []byte("name,email,team\nCli Person,cli@example.com,Cli Team\n"), 0o644))
+
+		assert.NoError(t, runCommand([]string{"import", "-file", path}))
+
+		var person models.Person
+		assert.NoError(t, database.GetDB().Preload("Team").Where("email = ?", "cli@example.com").First(&person).Error)
+		assert.Equal(t, "Cli Team", person.Team.Name)
+	})
+
+	t.Run("should fail when rows are rejected", func(t *testing.T) {
+		path := filepath.Join(dir, "teams.json")
+		assert.NoError(t, os.WriteFile(path, []byte(`[{"name":""}]`), 0o644))
+
+		err := runCommand([]string{"import", "-type", "teams", "-file", path, "-dry-run"})
+		assert.ErrorContains(t, err, "1 of 1 rows failed")
+	})
+
+	t.Run("should require a file", func(t *testing.T) {
+		assert.Error(t, runCommand([]string{"import"}))
+	})
+}
+
+func TestApplyRetentionCommand(t *testing.T) {
+	database.DB = setupTestDB()
+	old := models.Feedback{Content: "Old", TargetType: "team", TargetID: 1, TargetName: "Team", CreatedAt: time.Now().AddDate(-2, 0, 0)}
+	database.GetDB().Create(&old)
+	database.GetDB().Create(&models.RetentionRule{Name: "One year", Months: 12, Action: models.RetentionDelete, Enabled: true})
+
+	t.Run("should only report in dry-run mode", func(t *testing.T) {
+		assert.NoError(t, runCommand([]string{"apply-retention", "-dry-run"}))
+		assert.NoError(t, database.GetDB().First(&models.Feedback{}, old.ID).Error)
+	})
+
+	t.Run("should apply the rules", func(t *testing.T) {
+		assert.NoError(t, runCommand([]string{"apply-retention"}))
+		assert.Error(t, database.GetDB().First(&models.Feedback{}, old.ID).Error)
+
+		var runs []models.RetentionRun
+		database.GetDB().Order("id").Find(&runs)
+		assert.Len(t, runs, 2)
+		assert.Equal(t, models.RetentionTriggerCommand, runs[1].Trigger)
+		assert.Equal(t, int64(1), runs[1].Deleted)
+	})
+}
+
+func TestReencryptFeedbackCommand(t *testing.T) {
+	database.DB = setupTestDB()
+	defer encryption.Configure(nil)
+
+	plain := models.Feedback{Content: "Written in plaintext", TargetType: "person", TargetID: 1, TargetName: "Ann Smith"}
+	database.GetDB().Create(&plain)
+	database.GetDB().Create(&models.FeedbackAnswer{FeedbackID: plain.ID, QuestionID: 1, Prompt: "Why?", AnswerType: models.AnswerTypeText, Value: "Because"})
+	outbox.Add(database.GetDB(), events.FeedbackCreated, plain)
+	var event models.OutboxEvent
+	database.GetDB().First(&event)
+
+	t.Run("should require keys", func(t *testing.T) {
+		assert.Error(t, runCommand([]string{"reencrypt-feedback"}))
+	})
+
+	oldKey := encryption.Key{ID: "1", Secret: bytes.Repeat([]byte{'a'}, 32)}
+	newKey := encryption.Key{ID: "2", Secret: bytes.Repeat([]byte{'b'}, 32)}
+	indexKey := bytes.Repeat([]byte{'i'}, 32)
+	stored := func(table, column string, id uint) string {
+		var value string
+		database.GetDB().Table(table).Select(column).Where("id = ?", id).Row().Scan(&value)
+		return value
+	}
+
+	t.Run("should encrypt plaintext rows and index target names", func(t *testing.T) {
+		keyring, _ := encryption.NewKeyring([]encryption.Key{oldKey}, indexKey)
+		encryption.Configure(keyring)
+
+		assert.NoError(t, runCommand([]string{"reencrypt-feedback"}))
+		assert.Equal(t, "1", encryption.KeyID(stored("feedbacks", "content", plain.ID)))
+		assert.Equal(t, "1", encryption.KeyID(stored("feedbacks", "target_name", plain.ID)))
+		assert.Equal(t, "1", encryption.KeyID(stored("feedback_answers", "value", plain.ID)))
+		assert.Equal(t, "1", encryption.KeyID(stored("outbox_events", "payload", event.ID)))
+		assert.Equal(t, encryption.BlindIndex("Ann Smith"), stored("feedbacks", "target_name_index", plain.ID))
+	})
+
+	t.Run("should rotate to the active key", func(t *testing.T) {
+		keyring, _ := encryption.NewKeyring([]encryption.Key{newKey, oldKey}, indexKey)
+		encryption.Configure(keyring)
+		rotated := models.Feedback{Content: "Already rotated", TargetType: "team", TargetID: 1, TargetName: "Core"}
+		database.GetDB().Create(&rotated)
+		before := stored("feedbacks", "content", rotated.ID)
+
+		assert.NoError(t, runCommand([]string{"reencrypt-feedback", "-batch", "1"}))
+		assert.Equal(t, "2", encryption.KeyID(stored("feedbacks", "content", plain.ID)))
+		assert.Equal(t, "2", encryption.KeyID(stored("feedback_answers", "value", plain.ID)))
+		assert.Equal(t, "2", encryption.KeyID(stored("outbox_events", "payload", event.ID)))
+		assert.Equal(t, before, stored("feedbacks", "content", rotated.ID))
+
+		keyring, _ = encryption.NewKeyring([]encryption.Key{newKey}, indexKey)
+		encryption.Configure(keyring)
+		var feedback models.Feedback
+		assert.NoError(t, database.GetDB().Preload("Answers").First(&feedback, plain.ID).Error)
+		assert.Equal(t, "Written in plaintext", feedback.Content)
+		assert.Equal(t, "Ann Smith", feedback.TargetName)
+		assert.Equal(t, "Because", feedback.Answers[0].Value)
+		assert.NoError(t, database.GetDB().First(&event, event.ID).Error)
+		assert.Contains(t, event.Payload, "Written in plaintext")
+	})
+}
@@ -11,6 +11,30 @@ type Config struct {
 	DBPassword string
 	DBName     string
 	Port       string
+	AdminToken string
+
+	LiveAllowedOrigins string
+
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+
+	RetentionInterval string
+	RetentionDryRun   string
+
+	EncryptionKeys     string
+	EncryptionKeyFile  string
+	EncryptionIndexKey string
+
+	StorageBackend    string
+	StorageDir        string
+	S3Endpoint        string
+	S3Bucket          string
+	S3Region          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
 }
 
 func Load() *Config {
@@ -21,6 +45,30 @@ func Load() *Config {
 		DBPassword: getEnv("DB_PASSWORD", "password"),
 		DBName:     getEnv("DB_NAME", "coaching_db"),
 		Port:       getEnv("PORT", "8080"),
+		AdminToken: getEnv("ADMIN_TOKEN", ""),
+
+		LiveAllowedOrigins: getEnv("LIVE_ALLOWED_ORIGINS", ""),
+
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnv("SMTP_PORT", "587"),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:     getEnv("SMTP_FROM", "coaching@localhost"),
+
+		RetentionInterval: getEnv("RETENTION_INTERVAL", "24h"),
+		RetentionDryRun:   getEnv("RETENTION_DRY_RUN", "false"),
+
+		EncryptionKeys:     getEnv("ENCRYPTION_KEYS", ""),
+		EncryptionKeyFile:  getEnv("ENCRYPTION_KEY_FILE", ""),
+		EncryptionIndexKey: getEnv("ENCRYPTION_INDEX_KEY", ""),
+
+		StorageBackend:    getEnv("STORAGE_BACKEND", "local"),
+		StorageDir:        getEnv("STORAGE_DIR", "uploads"),
+		S3Endpoint:        getEnv("S3_ENDPOINT", ""),
+		S3Bucket:          getEnv("S3_BUCKET", ""),
+		S3Region:          getEnv("S3_REGION", "us-east-1"),
+		S3AccessKeyID:     getEnv("S3_ACCESS_KEY_ID", ""),
+		S3SecretAccessKey: getEnv("S3_SECRET_ACCESS_KEY", ""),
 	}
 }
 
@@ -19,6 +19,25 @@ func TestLoad(t *testing.T) {
 		assert.Equal(t, "password", cfg.DBPassword)
 		assert.Equal(t, "coaching_db", cfg.DBName)
 		assert.Equal(t, "8080", cfg.Port)
+		assert.Equal(t, "", cfg.AdminToken)
+		assert.Equal(t, "", cfg.LiveAllowedOrigins)
+		assert.Equal(t, "", cfg.SMTPHost)
+		assert.Equal(t, "587", cfg.SMTPPort)
+		assert.Equal(t, "", cfg.SMTPUsername)
+		assert.Equal(t, "", cfg.SMTPPassword)
+		assert.Equal(t, "coaching@localhost", cfg.SMTPFrom)
+		assert.Equal(t, "24h", cfg.RetentionInterval)
+		assert.Equal(t, "false", cfg.RetentionDryRun)
+		assert.Equal(t, "", cfg.EncryptionKeys)
+		assert.Equal(t, "", cfg.EncryptionKeyFile)
+		assert.Equal(t, "", cfg.EncryptionIndexKey)
+		assert.Equal(t, "local", cfg.StorageBackend)
+		assert.Equal(t, "uploads", cfg.StorageDir)
+		assert.Equal(t, "", cfg.S3Endpoint)
+		assert.Equal(t, "", cfg.S3Bucket)
+		assert.Equal(t, "us-east-1", cfg.S3Region)
+		assert.Equal(t, "", cfg.S3AccessKeyID)
+		assert.Equal(t, "", cfg.S3SecretAccessKey)
 	})
 
 	t.Run("should load custom values from env vars", func(t *testing.T) {
@@ -30,6 +49,25 @@ func TestLoad(t *testing.T) {
 		os.Setenv("DB_PASSWORD", "custom-pass")
 		os.Setenv("DB_NAME", "custom_db")
 		os.Setenv("PORT", "9000")
+		os.Setenv("ADMIN_TOKEN", "secret")
+		os.Setenv("LIVE_ALLOWED_ORIGINS", "https://dashboard.example.com")
+		os.Setenv("SMTP_HOST", "smtp.example.com")
+		os.Setenv("SMTP_PORT", "2525")
+		os.Setenv("SMTP_USERNAME", "mailer")
+		os.Setenv("SMTP_PASSWORD", "mail-pass")
+		os.Setenv("SMTP_FROM", "coach@example.com")
+		os.Setenv("RETENTION_INTERVAL", "6h")
+		os.Setenv("RETENTION_DRY_RUN", "true")
+		os.Setenv("ENCRYPTION_KEYS", "2:bmV3,1:b2xk")
+		os.Setenv("ENCRYPTION_KEY_FILE", "/etc/coaching/keys")
+		os.Setenv("ENCRYPTION_INDEX_KEY", "aW5kZXg=")
+		os.Setenv("STORAGE_BACKEND", "s3")
+		os.Setenv("STORAGE_DIR", "/var/lib/coaching")
+		os.Setenv("S3_ENDPOINT", "http://minio:9000")
+		os.Setenv("S3_BUCKET", "media")
+		os.Setenv("S3_REGION", "eu-west-1")
+		os.Setenv("S3_ACCESS_KEY_ID", "access")
+		os.Setenv("S3_SECRET_ACCESS_KEY", "s3-secret")
 		
 		cfg := Load()
 		
@@ -39,6 +77,25 @@ func TestLoad(t *testing.T) {
 		assert.Equal(t, "custom-pass", cfg.DBPassword)
 		assert.Equal(t, "custom_db", cfg.DBName)
 		assert.Equal(t, "9000", cfg.Port)
+		assert.Equal(t, "secret", cfg.AdminToken)
+		assert.Equal(t, "https://dashboard.example.com", cfg.LiveAllowedOrigins)
+		assert.Equal(t, "smtp.example.com", cfg.SMTPHost)
+		assert.Equal(t, "2525", cfg.SMTPPort)
+		assert.Equal(t, "mailer", cfg.SMTPUsername)
+		assert.Equal(t, "mail-pass", cfg.SMTPPassword)
+		assert.Equal(t, "coach@example.com", cfg.SMTPFrom)
+		assert.Equal(t, "6h", cfg.RetentionInterval)
+		assert.Equal(t, "true", cfg.RetentionDryRun)
+		assert.Equal(t, "2:bmV3,1:b2xk", cfg.EncryptionKeys)
+		assert.Equal(t, "/etc/coaching/keys", cfg.EncryptionKeyFile)
+		assert.Equal(t, "aW5kZXg=", cfg.EncryptionIndexKey)
+		assert.Equal(t, "s3", cfg.StorageBackend)
+		assert.Equal(t, "/var/lib/coaching", cfg.StorageDir)
+		assert.Equal(t, "http://minio:9000", cfg.S3Endpoint)
+		assert.Equal(t, "media", cfg.S3Bucket)
+		assert.Equal(t, "eu-west-1", cfg.S3Region)
+		assert.Equal(t, "access", cfg.S3AccessKeyID)
+		assert.Equal(t, "s3-secret", cfg.S3SecretAccessKey)
 		
 		clearEnvVars()
 	})
@@ -124,4 +181,23 @@ func clearEnvVars() {
 	os.Unsetenv("DB_PASSWORD")
 	os.Unsetenv("DB_NAME")
 	os.Unsetenv("PORT")
+	os.Unsetenv("ADMIN_TOKEN")
+	os.Unsetenv("LIVE_ALLOWED_ORIGINS")
+	os.Unsetenv("SMTP_HOST")
+	os.Unsetenv("SMTP_PORT")
+	os.Unsetenv("SMTP_USERNAME")
+	os.Unsetenv("SMTP_PASSWORD")
+	os.Unsetenv("SMTP_FROM")
+	os.Unsetenv("RETENTION_INTERVAL")
+	os.Unsetenv("RETENTION_DRY_RUN")
+	os.Unsetenv("ENCRYPTION_KEYS")
+	os.Unsetenv("ENCRYPTION_KEY_FILE")
+	os.Unsetenv("ENCRYPTION_INDEX_KEY")
+	os.Unsetenv("STORAGE_BACKEND")
+	os.Unsetenv("STORAGE_DIR")
+	os.Unsetenv("S3_ENDPOINT")
+	os.Unsetenv("S3_BUCKET")
+	os.Unsetenv("S3_REGION")
+	os.Unsetenv("S3_ACCESS_KEY_ID")
+	os.Unsetenv("S3_SECRET_ACCESS_KEY")
 }
@@ -41,14 +41,139 @@ func Connect(cfg *config.Config) {
 
 	log.Println("Database connected successfully, starting migration...")
 
-	err = DB.AutoMigrate(&models.Person{}, &models.Team{}, &models.Feedback{})
+	err = Migrate(DB)
 	if err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
 
+	err = SeedTemplates(DB)
+	if err != nil {
+		log.Fatal("Failed to seed feedback templates:", err)
+	}
+
+	err = SeedHealthSurveys(DB)
+	if err != nil {
+		log.Fatal("Failed to seed health surveys:", err)
+	}
+
+	err = SeedModerationRules(DB)
+	if err != nil {
+		log.Fatal("Failed to seed moderation rules:", err)
+	}
+
 	log.Println("Database connected and migrated successfully")
 }
 
+func Migrate(db *gorm.DB) error {
+	backfillPublished := db.Migrator().HasTable(&models.Feedback{}) && !db.Migrator().HasColumn(&models.Feedback{}, "published_at")
+	backfillSequences := db.Migrator().HasTable(&models.OutboxEvent{}) && !db.Migrator().HasColumn(&models.OutboxEvent{}, "sequence")
+	if err := db.AutoMigrate(
+		&models.Person{},
+		&models.Team{},
+		&models.Feedback{},
+		&models.FeedbackTemplate{},
+		&models.TemplateQuestion{},
+		&models.FeedbackAnswer{},
+		&models.FeedbackRequest{},
+		&models.ReviewCycle{},
+		&models.ReviewParticipant{},
+		&models.ReviewAssignment{},
+		&models.CoachingSession{},
+		&models.Goal{},
+		&models.KeyResult{},
+		&models.GoalCheckIn{},
+		&models.ActionItem{},
+		&models.Skill{},
+		&models.SkillAssessment{},
+		&models.HealthSurvey{},
+		&models.HealthDimension{},
+		&models.HealthRound{},
+		&models.HealthParticipation{},
+		&models.HealthVote{},
+		&models.ModerationRule{},
+		&models.WebhookSubscription{},
+		&models.WebhookDelivery{},
+		&models.OutboxEvent{},
+		&models.NotificationPreference{},
+		&models.EmailNotification{},
+		&models.Notification{},
+		&models.PrivacyAuditEntry{},
+		&models.RetentionRule{},
+		&models.RetentionRun{},
+		&models.TeamMembershipChange{},
+	); err != nil {
+		return err
+	}
+
+	// Approved feedback was published before published_at existed.
+	if backfillPublished {
+		if err := db.Model(&models.Feedback{}).Where("moderation_status = ?", models.ModerationApproved).
+			UpdateColumn("published_at", gorm.Expr("COALESCE(moderated_at, created_at)")).Error; err != nil {
+			return err
+		}
+	}
+
+	// Published events used to be numbered by id. Keeping those numbers lets
+	// clients resume from an id they already have.
+	if backfillSequences {
+		if err := db.Model(&models.OutboxEvent{}).Where("published_at IS NOT NULL").
+			UpdateColumn("sequence", gorm.Expr("id")).Error; err != nil {
+			return err
+		}
+	}
+
+	// Deliveries used to keep a copy of the event, which outlived erasure and
+	// retention. The outbox is now the only place event payloads are stored.
+	if db.Migrator().HasColumn(&models.WebhookDelivery{}, "payload") {
+		return db.Migrator().DropColumn(&models.WebhookDelivery{}, "payload")
+	}
+	return nil
+}
+
+func SeedTemplates(db *gorm.DB) error {
+	for _, template := range models.DefaultTemplates() {
+		var count int64
+		if err := db.Model(&models.FeedbackTemplate{}).Where("name = ?", template.Name).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		if err := db.Create(&template).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func GetDB() *gorm.DB {
 	return DB
 }
+
+func SeedHealthSurveys(db *gorm.DB) error {
+	for _, survey := range models.DefaultHealthSurveys() {
+		var count int64
+		if err := db.Model(&models.HealthSurvey{}).Where("name = ?", survey.Name).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		if err := db.Create(&survey).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func SeedModerationRules(db *gorm.DB) error {
+	var count int64
+	if err := db.Model(&models.ModerationRule{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	rules := models.DefaultModerationRules()
+	return db.Create(&rules).Error
+}
@@ -193,3 +193,44 @@ func TestDatabaseOperations(t *testing.T) {
 		assert.Equal(t, person.Name, loadedTeam.Members[0].Name)
 	})
 }
+
+func TestMigrateDropsDeliveryPayloads(t *testing.T) {
+	testDB, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, testDB.Exec("CREATE TABLE `webhook_deliveries` (`id` integer,`payload` text,PRIMARY KEY (`id`))").Error)
+
+	assert.NoError(t, Migrate(testDB))
+	assert.False(t, testDB.Migrator().HasColumn(&models.WebhookDelivery{}, "payload"))
+	assert.True(t, testDB.Migrator().HasColumn(&models.WebhookDelivery{}, "next_attempt_at"))
+}
+
+func TestMigrateBackfillsPublishedAt(t *testing.T) {
+	testDB, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, Migrate(testDB))
+	assert.NoError(t, testDB.Migrator().DropColumn(&models.Feedback{}, "published_at"))
+	assert.NoError(t, testDB.Exec("INSERT INTO `feedbacks` (`id`,`content`,`target_type`,`target_id`,`target_name`,`moderation_status`,`created_at`) VALUES (1,'Shipped','team',1,'Team',?,'2024-01-01 09:00:00'),(2,'Held','team',1,'Team',?,'2024-01-01 09:00:00')", models.ModerationApproved, models.ModerationPending).Error)
+
+	assert.NoError(t, Migrate(testDB))
+
+	var feedbacks []models.Feedback
+	assert.NoError(t, testDB.Select("id, published_at").Order("id").Find(&feedbacks).Error)
+	assert.NotNil(t, feedbacks[0].PublishedAt)
+	assert.Nil(t, feedbacks[1].PublishedAt)
+}
+
+func TestMigrateBackfillsOutboxSequences(t *testing.T) {
+	testDB, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, Migrate(testDB))
+	assert.NoError(t, testDB.Migrator().DropIndex(&models.OutboxEvent{}, "Sequence"))
+	assert.NoError(t, testDB.Migrator().DropColumn(&models.OutboxEvent{}, "sequence"))
+	assert.NoError(t, testDB.Exec("INSERT INTO `outbox_events` (`id`,`event_id`,`type`,`payload`,`published_at`) VALUES (4,'a','team.created','{}','2024-01-01 09:00:00'),(5,'b','team.created','{}',NULL)").Error)
+
+	assert.NoError(t, Migrate(testDB))
+
+	var rows []models.OutboxEvent
+	assert.NoError(t, testDB.Order("id").Find(&rows).Error)
+	assert.Equal(t, uint(4), *rows[0].Sequence)
+	assert.Nil(t, rows[1].Sequence)
+}
@@ -0,0 +1,289 @@
+package encryption
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Encrypted values look like enc:v1:<key id>:<wrapped data key>:<ciphertext>.
+// Each value has its own random data key, wrapped with a configured key, so
+// rotating keys only has to rewrap data keys.
+const prefix = "enc:v1:"
+
+const keySize = 32
+
+var (
+	ErrNotConfigured = errors.New("encryption keys are not configured")
+	ErrUnknownKey    = errors.New("value was encrypted with an unknown key")
+	ErrMalformed     = errors.New("malformed encrypted value")
+)
+
+type Key struct {
+	ID     string
+	Secret []byte
+}
+
+type Keyring struct {
+	keys     map[string][]byte
+	active   string
+	indexKey []byte
+}
+
+var (
+	mu      sync.RWMutex
+	current *Keyring
+)
+
+// NewKeyring encrypts with the first key and decrypts with any of them.
+func NewKeyring(keys []Key, indexKey []byte) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one encryption key is required")
+	}
+	if len(indexKey) < keySize {
+		return nil, fmt.Errorf("the blind index key must be at least %d bytes", keySize)
+	}
+
+	k := &Keyring{keys: map[string][]byte{}, active: keys[0].ID, indexKey: indexKey}
+	for _, key := range keys {
+		if key.ID == "" || strings.Contains(key.ID, ":") {
+			return nil, fmt.Errorf("invalid encryption key ID %q", key.ID)
+		}
+		if len(key.Secret) != keySize {
+			return nil, fmt.Errorf("encryption key %q must be %d bytes", key.ID, keySize)
+		}
+		if _, ok := k.keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate encryption key ID %q", key.ID)
+		}
+		k.keys[key.ID] = key.Secret
+	}
+	return k, nil
+}
+
+// ParseKeys reads "id:base64" entries separated by commas or new lines.
+// Blank lines and lines starting with # are skipped.
+func ParseKeys(spec string) ([]Key, error) {
+	var keys []Key
+	scanner := bufio.NewScanner(strings.NewReader(strings.ReplaceAll(spec, ",", "\n")))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		id, encoded, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("encryption key %q must be written as id:base64", line)
+		}
+		secret, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %q is not valid base64: %w", id, err)
+		}
+		keys = append(keys, Key{ID: strings.TrimSpace(id), Secret: secret})
+	}
+	return keys, scanner.Err()
+}
+
+// Load builds a keyring from configuration. Keys come from keySpec, or from
+// keyFile when keySpec is empty. Without keys it returns nil, which leaves
+// encryption off.
+func Load(keySpec, keyFile, indexKey string) (*Keyring, error) {
+	if keySpec == "" && keyFile != "" {
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read encryption key file: %w", err)
+		}
+		keySpec = string(data)
+	}
+
+	keys, err := ParseKeys(keySpec)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		if indexKey != "" {
+			return nil, errors.New("a blind index key was given without encryption keys")
+		}
+		return nil, nil
+	}
+
+	secret, err := base64.StdEncoding.DecodeString(indexKey)
+	if err != nil {
+		return nil, fmt.Errorf("blind index key is not valid base64: %w", err)
+	}
+	return NewKeyring(keys, secret)
+}
+
+// Configure sets the keyring used by the serializer. A nil keyring turns
+// encryption off; encrypted values then fail to load.
+func Configure(k *Keyring) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = k
+}
+
+func keyring() *Keyring {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+func Enabled() bool {
+	return keyring() != nil
+}
+
+func ActiveKeyID() string {
+	if k := keyring(); k != nil {
+		return k.active
+	}
+	return ""
+}
+
+// KeyID returns the ID of the key a stored value was encrypted with, or ""
+// for plaintext.
+func KeyID(value string) string {
+	if !strings.HasPrefix(value, prefix) {
+		return ""
+	}
+	id, _, _ := strings.Cut(strings.TrimPrefix(value, prefix), ":")
+	return id
+}
+
+// Encrypt seals plaintext with the active key. The column name is bound to the
+// ciphertext, so a value copied into another column does not decrypt. Empty
+// values and values written while encryption is off are stored as they are.
+func Encrypt(column, plaintext string) (string, error) {
+	k := keyring()
+	if k == nil || plaintext == "" {
+		return plaintext, nil
+	}
+
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+	wrapped, err := seal(k.keys[k.active], dataKey, []byte(k.active))
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(dataKey, []byte(plaintext), []byte(column))
+	if err != nil {
+		return "", err
+	}
+
+	encode := base64.RawStdEncoding.EncodeToString
+	return prefix + k.active + ":" + encode(wrapped) + ":" + encode(sealed), nil
+}
+
+// Decrypt opens a value written by Encrypt. Plaintext values are returned
+// unchanged, so rows written before encryption was turned on still load.
+func Decrypt(column, value string) (string, error) {
+	if !strings.HasPrefix(value, prefix) {
+		return value, nil
+	}
+	k := keyring()
+	if k == nil {
+		return "", ErrNotConfigured
+	}
+
+	parts := strings.Split(strings.TrimPrefix(value, prefix), ":")
+	if len(parts) != 3 {
+		return "", ErrMalformed
+	}
+	secret, ok := k.keys[parts[0]]
+	if !ok {
+		return "", fmt.Errorf("%w %q", ErrUnknownKey, parts[0])
+	}
+	wrapped, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", ErrMalformed
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrMalformed
+	}
+
+	dataKey, err := open(secret, wrapped, []byte(parts[0]))
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(dataKey, sealed, []byte(column))
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// Rotate re-encrypts a stored value with the active key. It reports whether
+// the value changed; values already under the active key are left alone.
+func Rotate(column, value string) (string, bool, error) {
+	if value == "" || KeyID(value) == ActiveKeyID() {
+		return value, false, nil
+	}
+	plaintext, err := Decrypt(column, value)
+	if err != nil {
+		return value, false, err
+	}
+	rotated, err := Encrypt(column, plaintext)
+	if err != nil {
+		return value, false, err
+	}
+	return rotated, rotated != value, nil
+}
+
+// BlindIndex returns a keyed hash of a value for exact-match lookups on an
+// encrypted column. Case and surrounding or repeated spaces are ignored. The
+// index key never rotates with the encryption keys.
+func BlindIndex(value string) string {
+	var indexKey []byte
+	if k := keyring(); k != nil {
+		indexKey = k.indexKey
+	}
+	mac := hmac.New(sha256.New, indexKey)
+	mac.Write([]byte(strings.ToLower(strings.Join(strings.Fields(value), " "))))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(key, sealed, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, ErrMalformed
+	}
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,200 @@
+package encryption
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func testKey(id string, b byte) Key {
+	return Key{ID: id, Secret: bytes.Repeat([]byte{b}, keySize)}
+}
+
+func useKeys(t *testing.T, keys ...Key) {
+	k, err := NewKeyring(keys, bytes.Repeat([]byte{'i'}, keySize))
+	if err != nil {
+		t.Fatalf("Failed to create keyring: %v", err)
+	}
+	Configure(k)
+	t.Cleanup(func() { Configure(nil) })
+}
+
+func TestEncrypt(t *testing.T) {
+	t.Run("should store values as they are without keys", func(t *testing.T) {
+		value, err := Encrypt("content", "Great work")
+		assert.NoError(t, err)
+		assert.Equal(t, "Great work", value)
+	})
+
+	t.Run("should round trip with the active key", func(t *testing.T) {
+		useKeys(t, testKey("1", 'a'))
+
+		value, err := Encrypt("content", "Great work")
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(value, "enc:v1:1:"))
+		assert.NotContains(t, value, "Great work")
+		assert.Equal(t, "1", KeyID(value))
+
+		again, _ := Encrypt("content", "Great work")
+		assert.NotEqual(t, value, again)
+
+		plaintext, err := Decrypt("content", value)
+		assert.NoError(t, err)
+		assert.Equal(t, "Great work", plaintext)
+	})
+
+	t.Run("should bind ciphertext to its column", func(t *testing.T) {
+		useKeys(t, testKey("1", 'a'))
+
+		value, _ := Encrypt("content", "Great work")
+		_, err := Decrypt("target_name", value)
+		assert.Error(t, err)
+	})
+
+	t.Run("should pass plaintext and empty values through", func(t *testing.T) {
+		useKeys(t, testKey("1", 'a'))
+
+		value, _ := Encrypt("content", "")
+		assert.Equal(t, "", value)
+		plaintext, err := Decrypt("content", "Written before encryption")
+		assert.NoError(t, err)
+		assert.Equal(t, "Written before encryption", plaintext)
+	})
+
+	t.Run("should fail for unknown keys and tampered values", func(t *testing.T) {
+		useKeys(t, testKey("1", 'a'))
+		value, _ := Encrypt("content", "Great work")
+
+		useKeys(t, testKey("2", 'b'))
+		_, err := Decrypt("content", value)
+		assert.ErrorIs(t, err, ErrUnknownKey)
+
+		useKeys(t, testKey("1", 'a'))
+		_, err = Decrypt("content", value[:len(value)-2]+"AA")
+		assert.Error(t, err)
+		_, err = Decrypt("content", "enc:v1:1:nonsense")
+		assert.ErrorIs(t, err, ErrMalformed)
+
+		Configure(nil)
+		_, err = Decrypt("content", value)
+		assert.ErrorIs(t, err, ErrNotConfigured)
+	})
+}
+
+func TestRotate(t *testing.T) {
+	useKeys(t, testKey("1", 'a'))
+	old, _ := Encrypt("content", "Great work")
+
+	useKeys(t, testKey("2", 'b'), testKey("1", 'a'))
+
+	rotated, changed, err := Rotate("content", old)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "2", KeyID(rotated))
+	plaintext, _ := Decrypt("content", rotated)
+	assert.Equal(t, "Great work", plaintext)
+
+	_, changed, err = Rotate("content", rotated)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	encrypted, changed, err := Rotate("content", "Plaintext")
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "2", KeyID(encrypted))
+}
+
+func TestBlindIndex(t *testing.T) {
+	useKeys(t, testKey("1", 'a'))
+
+	assert.Equal(t, BlindIndex("Ann Smith"), BlindIndex("  ann   SMITH "))
+	assert.NotEqual(t, BlindIndex("Ann Smith"), BlindIndex("Ann Smyth"))
+	assert.Len(t, BlindIndex("Ann Smith"), 64)
+
+	index := BlindIndex("Ann Smith")
+	useKeys(t, testKey("2", 'b'), testKey("1", 'a'))
+	assert.Equal(t, index, BlindIndex("Ann Smith"))
+}
+
+func TestLoad(t *testing.T) {
+	secret := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{'a'}, keySize))
+	other := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{'b'}, keySize))
+	indexKey := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{'i'}, keySize))
+
+	t.Run("should leave encryption off without keys", func(t *testing.T) {
+		k, err := Load("", "", "")
+		assert.NoError(t, err)
+		assert.Nil(t, k)
+
+		_, err = Load("", "", indexKey)
+		assert.Error(t, err)
+	})
+
+	t.Run("should use the first key for encryption", func(t *testing.T) {
+		k, err := Load("2:"+other+", 1:"+secret, "", indexKey)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "2", k.active)
+			assert.Len(t, k.keys, 2)
+		}
+	})
+
+	t.Run("should read keys from a file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "keys")
+		assert.NoError(t, os.WriteFile(path, []byte("# newest first\n3:"+other+"\n\n1:"+secret+"\n"), 0600))
+
+		k, err := Load("", path, indexKey)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "3", k.active)
+			assert.Len(t, k.keys, 2)
+		}
+	})
+
+	t.Run("should reject invalid keys", func(t *testing.T) {
+		for _, spec := range []string{"1:" + secret + ",1:" + other, "1:c2hvcnQ=", "1:not base64", secret} {
+			_, err := Load(spec, "", indexKey)
+			assert.Error(t, err, spec)
+		}
+
+		_, err := Load("1:"+secret, "", "")
+		assert.Error(t, err)
+		_, err = Load("", filepath.Join(t.TempDir(), "missing"), indexKey)
+		assert.Error(t, err)
+	})
+}
+
+type note struct {
+	ID   uint
+	Body string `gorm:"type:text;serializer:encrypted"`
+}
+
+func TestSerializer(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&note{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	useKeys(t, testKey("1", 'a'))
+
+	n := note{Body: "Needs to speak up in meetings"}
+	assert.NoError(t, db.Create(&n).Error)
+
+	var stored string
+	db.Table("notes").Select("body").Where("id = ?", n.ID).Row().Scan(&stored)
+	assert.Equal(t, "1", KeyID(stored))
+
+	var loaded note
+	assert.NoError(t, db.First(&loaded, n.ID).Error)
+	assert.Equal(t, "Needs to speak up in meetings", loaded.Body)
+
+	db.Exec("UPDATE notes SET body = ? WHERE id = ?", "enc:v1:9:x:y", n.ID)
+	assert.ErrorIs(t, db.First(&loaded, n.ID).Error, ErrUnknownKey)
+}
@@ -0,0 +1,43 @@
+package encryption
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"gorm.io/gorm/schema"
+)
+
+// Serializer encrypts string fields tagged `gorm:"serializer:encrypted"`.
+type Serializer struct{}
+
+func init() {
+	schema.RegisterSerializer("encrypted", Serializer{})
+}
+
+func (Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var value string
+	switch v := dbValue.(type) {
+	case nil:
+	case string:
+		value = v
+	case []byte:
+		value = string(v)
+	default:
+		return fmt.Errorf("cannot decrypt %s from %T", field.DBName, dbValue)
+	}
+
+	plaintext, err := Decrypt(field.DBName, value)
+	if err != nil {
+		return fmt.Errorf("%s: %w", field.DBName, err)
+	}
+	field.ReflectValueOf(ctx, dst).SetString(plaintext)
+	return nil
+}
+
+func (Serializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	value, ok := fieldValue.(string)
+	if !ok {
+		return nil, fmt.Errorf("cannot encrypt %s of type %T", field.DBName, fieldValue)
+	}
+	return Encrypt(field.DBName, value)
+}
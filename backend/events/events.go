@@ -0,0 +1,108 @@
+package events
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+const (
+	PersonCreated         = "person.created"
+	PersonUpdated         = "person.updated"
+	PersonDeleted         = "person.deleted"
+	PersonAssignedToTeam  = "person.assigned_to_team"
+	PersonRemovedFromTeam = "person.removed_from_team"
+	PersonErased          = "person.erased"
+	TeamCreated           = "team.created"
+	TeamUpdated           = "team.updated"
+	TeamDeleted           = "team.deleted"
+	FeedbackCreated       = "feedback.created"
+	FeedbackUpdated       = "feedback.updated"
+	FeedbackDeleted       = "feedback.deleted"
+)
+
+var Types = []string{
+	PersonCreated,
+	PersonUpdated,
+	PersonDeleted,
+	PersonAssignedToTeam,
+	PersonRemovedFromTeam,
+	PersonErased,
+	TeamCreated,
+	TeamUpdated,
+	TeamDeleted,
+	FeedbackCreated,
+	FeedbackUpdated,
+	FeedbackDeleted,
+}
+
+type Event struct {
+	ID         string      `json:"id"`
+	Sequence   uint        `json:"sequence,omitempty"`
+	Type       string      `json:"type"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+type Handler func(Event)
+
+var (
+	mu          sync.RWMutex
+	subscribers = map[int]Handler{}
+	nextID      int
+)
+
+func IsKnown(eventType string) bool {
+	for _, t := range Types {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func New(eventType string, data interface{}) Event {
+	return Event{
+		ID:         newID(),
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}
+}
+
+func Subscribe(handler Handler) func() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	id := nextID
+	nextID++
+	subscribers[id] = handler
+
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		delete(subscribers, id)
+	}
+}
+
+func Publish(event Event) {
+	mu.RLock()
+	handlers := make([]Handler, 0, len(subscribers))
+	for _, h := range subscribers {
+		handlers = append(handlers, h)
+	}
+	mu.RUnlock()
+
+	for _, h := range handlers {
+		h(event)
+	}
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return time.Now().UTC().Format("20060102150405.000000000")
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,106 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeAll(t *testing.T, format string, columns []string, rows ...[]interface{}) []byte {
+	var b bytes.Buffer
+	w, err := NewWriter(&b, format, columns)
+	assert.NoError(t, err)
+	for _, row := range rows {
+		assert.NoError(t, w.Write(row))
+	}
+	assert.NoError(t, w.Close())
+	return b.Bytes()
+}
+
+func TestCSVWriter(t *testing.T) {
+	var missing *uint
+	id := uint(7)
+	created := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	out := writeAll(t, FormatCSV, []string{"id", "parent", "name", "score", "created_at"},
+		[]interface{}{&id, missing, "Smith, Ann", -0.5, created},
+		[]interface{}{uint(8), nil, "=HYPERLINK(\"x\")", 1.0, created},
+	)
+
+	assert.Equal(t, "id,parent,name,score,created_at\n"+
+		"7,,\"Smith, Ann\",-0.5,2026-03-01T09:30:00Z\n"+
+		"8,,\"'=HYPERLINK(\"\"x\"\")\",1,2026-03-01T09:30:00Z\n", string(out))
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	var missing *string
+	out := writeAll(t, FormatNDJSON, []string{"name", "id", "team"},
+		[]interface{}{"Ann", 1, missing},
+		[]interface{}{"=Bob", 2, "Core"},
+	)
+
+	assert.Equal(t, `{"name":"Ann","id":1,"team":null}`+"\n"+`{"name":"=Bob","id":2,"team":"Core"}`+"\n", string(out))
+}
+
+func TestXLSXWriter(t *testing.T) {
+	out := writeAll(t, FormatXLSX, []string{"id", "name", "private"},
+		[]interface{}{1, "Ann & <Bob>", true},
+		[]interface{}{2, nil, false},
+	)
+
+	archive, err := zip.NewReader(bytes.NewReader(out), int64(len(out)))
+	assert.NoError(t, err)
+
+	files := map[string][]byte{}
+	for _, f := range archive.File {
+		r, err := f.Open()
+		assert.NoError(t, err)
+		files[f.Name], _ = io.ReadAll(r)
+		r.Close()
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml"} {
+		assert.Contains(t, files, name)
+		assert.NoError(t, xml.Unmarshal(files[name], new(interface{})), name)
+	}
+
+	var sheet struct {
+		Rows []struct {
+			Ref   string `xml:"r,attr"`
+			Cells []struct {
+				Ref    string `xml:"r,attr"`
+				Type   string `xml:"t,attr"`
+				Value  string `xml:"v"`
+				Inline string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	assert.NoError(t, xml.Unmarshal(files["xl/worksheets/sheet1.xml"], &sheet))
+	assert.Len(t, sheet.Rows, 3)
+	assert.Equal(t, "name", sheet.Rows[0].Cells[1].Inline)
+	assert.Equal(t, "A2", sheet.Rows[1].Cells[0].Ref)
+	assert.Equal(t, "1", sheet.Rows[1].Cells[0].Value)
+	assert.Equal(t, "Ann & <Bob>", sheet.Rows[1].Cells[1].Inline)
+	assert.Equal(t, "b", sheet.Rows[1].Cells[2].Type)
+	assert.Len(t, sheet.Rows[2].Cells, 2)
+}
+
+func TestCellRef(t *testing.T) {
+	assert.Equal(t, "A1", cellRef(0, 1))
+	assert.Equal(t, "Z2", cellRef(25, 2))
+	assert.Equal(t, "AA3", cellRef(26, 3))
+	assert.Equal(t, "BA4", cellRef(52, 4))
+}
+
+func TestFormats(t *testing.T) {
+	assert.True(t, IsFormat(FormatXLSX))
+	assert.False(t, IsFormat("pdf"))
+	assert.Equal(t, "feedbacks-2026-10-18.ndjson", Filename("feedbacks", FormatNDJSON, time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)))
+
+	_, err := NewWriter(io.Discard, "pdf", nil)
+	assert.Error(t, err)
+}
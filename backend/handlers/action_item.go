@@ -0,0 +1,312 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var openActionStatuses = []string{models.ActionStatusOpen, models.ActionStatusInProgress}
+
+func CreateActionItem(c *gin.Context) {
+	var req models.CreateActionItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var owner models.Person
+	if err := database.GetDB().First(&owner, req.OwnerID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Owner not found"})
+		return
+	}
+
+	if req.SourceType != "" {
+		if !actionSourceExists(req.SourceType, *req.SourceID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Source " + req.SourceType + " not found"})
+			return
+		}
+	} else if req.SourceID != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "source_type is required with source_id"})
+		return
+	}
+
+	item := models.ActionItem{
+		Title:       req.Title,
+		Description: req.Description,
+		OwnerID:     req.OwnerID,
+		DueDate:     req.DueDate,
+		Status:      models.ActionStatusOpen,
+		SourceType:  req.SourceType,
+		SourceID:    req.SourceID,
+	}
+
+	if err := database.GetDB().Create(&item).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create action item"})
+		return
+	}
+
+	item.Owner = &owner
+	c.JSON(http.StatusCreated, item)
+}
+
+func GetActionItems(c *gin.Context) {
+	query := actionItemListQuery(c, database.GetDB())
+
+	if value := c.Query("owner_id"); value != "" {
+		ownerID, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid owner_id"})
+			return
+		}
+		query = query.Where("owner_id = ?", ownerID)
+	}
+	if sourceType := c.Query("source_type"); sourceType != "" {
+		query = query.Where("source_type = ?", sourceType)
+	}
+	if value := c.Query("source_id"); value != "" {
+		sourceID, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid source_id"})
+			return
+		}
+		query = query.Where("source_id = ?", sourceID)
+	}
+
+	var items []models.ActionItem
+	if err := query.Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch action items"})
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+func GetActionItem(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid action item ID"})
+		return
+	}
+
+	var item models.ActionItem
+	if err := database.GetDB().Preload("Owner").First(&item, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Action item not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+func UpdateActionItem(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid action item ID"})
+		return
+	}
+
+	var req models.UpdateActionItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var item models.ActionItem
+	if err := database.GetDB().First(&item, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Action item not found"})
+		return
+	}
+
+	var owner models.Person
+	if err := database.GetDB().First(&owner, req.OwnerID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Owner not found"})
+		return
+	}
+
+	if req.Status == models.ActionStatusDone && item.Status != models.ActionStatusDone {
+		now := time.Now()
+		item.CompletedAt = &now
+	} else if req.Status != models.ActionStatusDone {
+		item.CompletedAt = nil
+	}
+
+	item.Title = req.Title
+	item.Description = req.Description
+	item.OwnerID = req.OwnerID
+	item.DueDate = req.DueDate
+	item.Status = req.Status
+
+	if err := database.GetDB().Save(&item).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update action item"})
+		return
+	}
+
+	item.Owner = &owner
+	c.JSON(http.StatusOK, item)
+}
+
+func DeleteActionItem(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid action item ID"})
+		return
+	}
+
+	if err := database.GetDB().Delete(&models.ActionItem{}, id).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete action item"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Action item deleted successfully"})
+}
+
+func GetPersonActionItems(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid person ID"})
+		return
+	}
+
+	var person models.Person
+	if err := database.GetDB().First(&person, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Person not found"})
+		return
+	}
+
+	query := actionItemListQuery(c, database.GetDB().Where("owner_id = ?", person.ID))
+	query = openByDefault(c, query)
+
+	var items []models.ActionItem
+	if err := query.Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch action items"})
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+func GetTeamActionItems(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	var team models.Team
+	if err := database.GetDB().First(&team, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+		return
+	}
+
+	members := database.GetDB().Model(&models.Person{}).Select("id").Where("team_id = ?", team.ID)
+	query := actionItemListQuery(c, database.GetDB().Where("owner_id IN (?)", members))
+	query = openByDefault(c, query)
+
+	var items []models.ActionItem
+	if err := query.Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch action items"})
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+func GetOverdueActionItems(c *gin.Context) {
+	now := time.Now()
+	query := database.GetDB().Preload("Owner").
+		Where("status IN ? AND due_date IS NOT NULL AND due_date < ?", openActionStatuses, now)
+
+	if value := c.Query("owner_id"); value != "" {
+		ownerID, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid owner_id"})
+			return
+		}
+		query = query.Where("owner_id = ?", ownerID)
+	}
+	if value := c.Query("team_id"); value != "" {
+		teamID, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team_id"})
+			return
+		}
+		members := database.GetDB().Model(&models.Person{}).Select("id").Where("team_id = ?", teamID)
+		query = query.Where("owner_id IN (?)", members)
+	}
+
+	var items []models.ActionItem
+	if err := query.Order("due_date").Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch overdue action items"})
+		return
+	}
+
+	c.JSON(http.StatusOK, buildOverdueReport(items, now))
+}
+
+func buildOverdueReport(items []models.ActionItem, now time.Time) models.OverdueReport {
+	report := models.OverdueReport{
+		Total:   len(items),
+		ByOwner: []models.OverdueOwnerCount{},
+		Items:   make([]models.OverdueActionItem, 0, len(items)),
+	}
+
+	owners := make(map[uint]int)
+	for _, item := range items {
+		report.Items = append(report.Items, models.OverdueActionItem{
+			ActionItem:  item,
+			DaysOverdue: int(now.Sub(*item.DueDate).Hours() / 24),
+		})
+
+		idx, ok := owners[item.OwnerID]
+		if !ok {
+			idx = len(report.ByOwner)
+			owners[item.OwnerID] = idx
+			entry := models.OverdueOwnerCount{OwnerID: item.OwnerID}
+			if item.Owner != nil {
+				entry.OwnerName = item.Owner.Name
+			}
+			report.ByOwner = append(report.ByOwner, entry)
+		}
+		report.ByOwner[idx].Count++
+	}
+
+	return report
+}
+
+func actionItemListQuery(c *gin.Context, query *gorm.DB) *gorm.DB {
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	return query.Preload("Owner").Order("due_date IS NULL, due_date, created_at desc")
+}
+
+func openByDefault(c *gin.Context, query *gorm.DB) *gorm.DB {
+	if c.Query("status") == "" {
+		return query.Where("status IN ?", openActionStatuses)
+	}
+	return query
+}
+
+func actionSourceExists(sourceType string, sourceID uint) bool {
+	var model interface{}
+	switch sourceType {
+	case models.ActionSourceFeedback:
+		model = &models.Feedback{}
+	case models.ActionSourceSession:
+		model = &models.CoachingSession{}
+	case models.ActionSourceGoal:
+		model = &models.Goal{}
+	default:
+		return false
+	}
+
+	var count int64
+	if err := database.GetDB().Model(model).Where("id = ?", sourceID).Count(&count).Error; err != nil {
+		return false
+	}
+	return count > 0
+}
@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupActionItemTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic("Failed to connect to test database")
+	}
+
+	err = database.Migrate(db)
+	if err != nil {
+		panic("Failed to migrate test database")
+	}
+
+	database.DB = db
+
+	r := gin.New()
+
+	api := r.Group("/api/v1")
+	actionItems := api.Group("/action-items")
+	{
+		actionItems.POST("", CreateActionItem)
+		actionItems.GET("", GetActionItems)
+		actionItems.GET("/overdue", GetOverdueActionItems)
+		actionItems.GET("/:id", GetActionItem)
+		actionItems.PUT("/:id", UpdateActionItem)
+		actionItems.DELETE("/:id", DeleteActionItem)
+	}
+	api.GET("/persons/:id/action-items", GetPersonActionItems)
+	api.GET("/teams/:id/action-items", GetTeamActionItems)
+
+	return r
+}
+
+func TestCreateActionItem(t *testing.T) {
+	router := setupActionItemTestRouter()
+	owner := createTestPerson(t, "Owner", "action.owner1@example.com", "")
+	feedback := models.Feedback{Content: "Needs to improve time management", TargetType: "person", TargetID: owner.ID, TargetName: owner.Name}
+	assert.NoError(t, database.GetDB().Create(&feedback).Error)
+
+	t.Run("should create action item linked to feedback", func(t *testing.T) {
+		due := time.Now().Add(7 * 24 * time.Hour)
+		reqBody := models.CreateActionItemRequest{
+			Title:      "Block focus time in calendar",
+			OwnerID:    owner.ID,
+			DueDate:    &due,
+			SourceType: models.ActionSourceFeedback,
+			SourceID:   &feedback.ID,
+		}
+
+		w := makeRequest(t, router, "POST", "/api/v1/action-items", reqBody)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response models.ActionItem
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, models.ActionStatusOpen, response.Status)
+		assert.Equal(t, feedback.ID, *response.SourceID)
+		assert.Equal(t, "Owner", response.Owner.Name)
+	})
+
+	t.Run("should return error for non-existent source", func(t *testing.T) {
+		missing := uint(999)
+		reqBody := models.CreateActionItemRequest{
+			Title:      "Follow up",
+			OwnerID:    owner.ID,
+			SourceType: models.ActionSourceGoal,
+			SourceID:   &missing,
+		}
+
+		w := makeRequest(t, router, "POST", "/api/v1/action-items", reqBody)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("should require source_id with source_type", func(t *testing.T) {
+		reqBody := models.CreateActionItemRequest{
+			Title:      "Follow up",
+			OwnerID:    owner.ID,
+			SourceType: models.ActionSourceSession,
+		}
+
+		w := makeRequest(t, router, "POST", "/api/v1/action-items", reqBody)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should return error for non-existent owner", func(t *testing.T) {
+		reqBody := models.CreateActionItemRequest{Title: "Orphan", OwnerID: 999}
+
+		w := makeRequest(t, router, "POST", "/api/v1/action-items", reqBody)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestUpdateActionItem(t *testing.T) {
+	router := setupActionItemTestRouter()
+	owner := createTestPerson(t, "Owner", "action.owner2@example.com", "")
+	item := createActionItemTestItem(t, owner.ID, "Write the runbook", nil)
+
+	reqBody := models.UpdateActionItemRequest{Title: item.Title, OwnerID: owner.ID, Status: models.ActionStatusDone}
+	w := makeRequest(t, router, "PUT", fmt.Sprintf("/api/v1/action-items/%d", item.ID), reqBody)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.ActionItem
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, models.ActionStatusDone, response.Status)
+	assert.NotNil(t, response.CompletedAt)
+
+	reqBody.Status = models.ActionStatusOpen
+	w = makeRequest(t, router, "PUT", fmt.Sprintf("/api/v1/action-items/%d", item.ID), reqBody)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	response = models.ActionItem{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Nil(t, response.CompletedAt)
+}
+
+func TestListOpenActionItems(t *testing.T) {
+	router := setupActionItemTestRouter()
+	team := createTestTeam(t, "Infra", "")
+	member := createTestPerson(t, "Member", "action.member3@example.com", "")
+	outsider := createTestPerson(t, "Outsider", "action.outsider3@example.com", "")
+	assert.NoError(t, database.GetDB().Model(&member).Update("team_id", team.ID).Error)
+
+	createActionItemTestItem(t, member.ID, "Open item", nil)
+	done := createActionItemTestItem(t, member.ID, "Done item", nil)
+	assert.NoError(t, database.GetDB().Model(&done).Update("status", models.ActionStatusDone).Error)
+	createActionItemTestItem(t, outsider.ID, "Someone else's item", nil)
+
+	t.Run("should list open items for a person", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/persons/%d/action-items", member.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response []models.ActionItem
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response, 1)
+		assert.Equal(t, "Open item", response[0].Title)
+	})
+
+	t.Run("should honour an explicit status", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/persons/%d/action-items?status=done", member.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response []models.ActionItem
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response, 1)
+		assert.Equal(t, "Done item", response[0].Title)
+	})
+
+	t.Run("should list open items for a team", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/teams/%d/action-items", team.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response []models.ActionItem
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response, 1)
+	})
+}
+
+func TestGetOverdueActionItems(t *testing.T) {
+	router := setupActionItemTestRouter()
+	alice := createTestPerson(t, "Alice", "action.alice4@example.com", "")
+	bob := createTestPerson(t, "Bob", "action.bob4@example.com", "")
+
+	threeDaysAgo := time.Now().Add(-3*24*time.Hour - time.Hour)
+	yesterday := time.Now().Add(-25 * time.Hour)
+	tomorrow := time.Now().Add(24 * time.Hour)
+	createActionItemTestItem(t, alice.ID, "Late one", &threeDaysAgo)
+	createActionItemTestItem(t, alice.ID, "Late two", &yesterday)
+	createActionItemTestItem(t, bob.ID, "Not due", &tomorrow)
+	closed := createActionItemTestItem(t, bob.ID, "Late but done", &yesterday)
+	assert.NoError(t, database.GetDB().Model(&closed).Update("status", models.ActionStatusDone).Error)
+
+	w := makeRequest(t, router, "GET", "/api/v1/action-items/overdue", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var report models.OverdueReport
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, 2, report.Total)
+	assert.Equal(t, "Late one", report.Items[0].Title)
+	assert.Equal(t, 3, report.Items[0].DaysOverdue)
+	assert.Equal(t, []models.OverdueOwnerCount{{OwnerID: alice.ID, OwnerName: "Alice", Count: 2}}, report.ByOwner)
+}
+
+func createActionItemTestItem(t *testing.T, ownerID uint, title string, due *time.Time) models.ActionItem {
+	item := models.ActionItem{
+		Title:   title,
+		OwnerID: ownerID,
+		DueDate: due,
+		Status:  models.ActionStatusOpen,
+	}
+
+	err := database.GetDB().Create(&item).Error
+	assert.NoError(t, err)
+
+	return item
+}
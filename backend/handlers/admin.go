@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"time"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ViewerTicketHeader = "X-Viewer-Ticket"
+
+	viewerKey = "viewer_id"
+)
+
+func RequireAdmin(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.Next()
+			return
+		}
+
+		if !tokenMatches(bearerToken(c), token) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Admin token required"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// IdentifyViewer records who is making the request from a ticket issued by
+// CreateLiveTicket and sent in the X-Viewer-Ticket header. Requests without a
+// ticket continue anonymously; an invalid ticket is rejected.
+func IdentifyViewer(adminToken string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ticket := c.GetHeader(ViewerTicketHeader)
+		if ticket == "" {
+			c.Next()
+			return
+		}
+
+		id, err := verifyLiveTicket(adminToken, ticket, time.Now())
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.Set(viewerKey, id)
+		c.Next()
+	}
+}
+
+func viewerID(c *gin.Context) (uint, bool) {
+	id, ok := c.Get(viewerKey)
+	if !ok {
+		return 0, false
+	}
+	viewer, ok := id.(uint)
+	return viewer, ok
+}
+
+func bearerToken(c *gin.Context) string {
+	return strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+}
+
+func tokenMatches(provided, token string) bool {
+	return subtle.ConstantTimeCompare([]byte(provided), []byte(token)) == 1
+}
@@ -0,0 +1,333 @@
+package handlers
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Bucket expressions convert created_at into the requested time zone before
+// formatting it. MySQL converts with its time zone tables; sqlite has none, so
+// it shifts by the zone's current offset.
+var bucketExpressions = map[string]map[string]string{
+	"sqlite": {
+		models.IntervalDay:   "strftime('%Y-%m-%d', created_at, @offset)",
+		models.IntervalWeek:  "date(created_at, @offset, 'weekday 0', '-6 days')",
+		models.IntervalMonth: "strftime('%Y-%m', created_at, @offset)",
+	},
+	"mysql": {
+		models.IntervalDay:   "DATE_FORMAT(CONVERT_TZ(created_at, @source, @zone), '%Y-%m-%d')",
+		models.IntervalWeek:  "DATE_FORMAT(DATE_SUB(CONVERT_TZ(created_at, @source, @zone), INTERVAL WEEKDAY(CONVERT_TZ(created_at, @source, @zone)) DAY), '%Y-%m-%d')",
+		models.IntervalMonth: "DATE_FORMAT(CONVERT_TZ(created_at, @source, @zone), '%Y-%m')",
+	},
+}
+
+// bucketArgs names the time zones used by bucketExpressions. MySQL stores
+// timestamps in the server's local time, as set by loc=Local in the DSN.
+func bucketArgs(location *time.Location, now time.Time) map[string]interface{} {
+	_, offset := now.In(location).Zone()
+	return map[string]interface{}{
+		"offset": fmt.Sprintf("%+d minutes", offset/60),
+		"source": now.Local().Format("-07:00"),
+		"zone":   location.String(),
+	}
+}
+
+var aggregateTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	time.RFC3339Nano,
+}
+
+type aggregateTime struct {
+	Time  time.Time
+	Valid bool
+}
+
+func (t *aggregateTime) Scan(value interface{}) error {
+	t.Valid = false
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case time.Time:
+		t.Time, t.Valid = v, true
+		return nil
+	case []byte:
+		return t.parse(string(v))
+	case string:
+		return t.parse(v)
+	}
+	return fmt.Errorf("cannot scan %T into a time", value)
+}
+
+func (t aggregateTime) Value() (driver.Value, error) {
+	if !t.Valid {
+		return nil, nil
+	}
+	return t.Time, nil
+}
+
+func (t *aggregateTime) parse(value string) error {
+	for _, layout := range aggregateTimeLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			t.Time, t.Valid = parsed, true
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot parse %q as a time", value)
+}
+
+func GetFeedbackVolume(c *gin.Context) {
+	interval := c.DefaultQuery("interval", models.IntervalDay)
+	expression, ok := bucketExpressions[database.GetDB().Dialector.Name()][interval]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "interval must be day, week or month"})
+		return
+	}
+	location, err := time.LoadLocation(c.DefaultQuery("tz", "UTC"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tz, expected an IANA time zone such as Europe/Berlin"})
+		return
+	}
+
+	query, ok := analyticsFeedbackQuery(c)
+	if !ok {
+		return
+	}
+
+	series := models.VolumeSeries{Interval: interval, TimeZone: location.String(), Points: []models.VolumePoint{}}
+	if query, series.TargetType, series.TargetID, ok = analyticsTargetFilter(c, query); !ok {
+		return
+	}
+
+	if err := query.Select(expression+" AS bucket, COUNT(*) AS count, AVG(rating) AS average_rating", bucketArgs(location, time.Now())).
+		Group("bucket").Order("bucket").Scan(&series.Points).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute feedback volume"})
+		return
+	}
+
+	for _, point := range series.Points {
+		series.Total += point.Count
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+func GetGivenReceived(c *gin.Context) {
+	persons := database.GetDB().Order("name")
+	if value := c.Query("team_id"); value != "" {
+		teamID, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team_id"})
+			return
+		}
+		persons = persons.Where("team_id = ?", teamID)
+	}
+
+	var people []models.Person
+	if err := persons.Find(&people).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute given and received feedback"})
+		return
+	}
+
+	type personCount struct {
+		PersonID uint
+		Count    int64
+	}
+
+	query, ok := analyticsFeedbackQuery(c)
+	if !ok {
+		return
+	}
+
+	var givenCounts []personCount
+	if err := query.Session(&gorm.Session{}).Select("author_id AS person_id, COUNT(*) AS count").
+		Where("author_id IS NOT NULL").Group("author_id").Scan(&givenCounts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute given and received feedback"})
+		return
+	}
+
+	var receivedCounts []personCount
+	if err := query.Session(&gorm.Session{}).Select("target_id AS person_id, COUNT(*) AS count").
+		Where("target_type = ?", "person").Group("target_id").Scan(&receivedCounts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute given and received feedback"})
+		return
+	}
+
+	givenBy := make(map[uint]int64, len(givenCounts))
+	for _, row := range givenCounts {
+		givenBy[row.PersonID] = row.Count
+	}
+	receivedBy := make(map[uint]int64, len(receivedCounts))
+	for _, row := range receivedCounts {
+		receivedBy[row.PersonID] = row.Count
+	}
+
+	results := make([]models.GivenReceived, 0, len(people))
+	for _, p := range people {
+		entry := models.GivenReceived{
+			PersonID: p.ID,
+			Name:     p.Name,
+			Given:    givenBy[p.ID],
+			Received: receivedBy[p.ID],
+		}
+		if entry.Received > 0 {
+			ratio := float64(entry.Given) / float64(entry.Received)
+			entry.Ratio = &ratio
+		}
+		results = append(results, entry)
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+func GetFeedbackGaps(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be a positive number"})
+		return
+	}
+	cutoff := time.Now().AddDate(0, 0, -days)
+
+	latest := database.GetDB().Model(&models.Feedback{}).
+		Select("target_id, MAX(created_at) AS last_received_at").
+		Where("target_type = ? AND moderation_status = ?", "person", models.ModerationApproved).Group("target_id")
+	recent := database.GetDB().Model(&models.Feedback{}).Select("1").
+		Where("target_type = ? AND target_id = people.id AND created_at >= ? AND moderation_status = ?", "person", cutoff, models.ModerationApproved)
+
+	query := database.GetDB().Model(&models.Person{}).
+		Select("people.id AS person_id, people.name, people.email, people.team_id, latest.last_received_at").
+		Joins("LEFT JOIN (?) AS latest ON latest.target_id = people.id", latest).
+		Where("NOT EXISTS (?)", recent).
+		Order("latest.last_received_at, people.name")
+
+	if value := c.Query("team_id"); value != "" {
+		teamID, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team_id"})
+			return
+		}
+		query = query.Where("people.team_id = ?", teamID)
+	}
+
+	var rows []struct {
+		PersonID       uint
+		Name           string
+		Email          string
+		TeamID         *uint
+		LastReceivedAt aggregateTime
+	}
+	if err := query.Scan(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute feedback gaps"})
+		return
+	}
+
+	gaps := make([]models.FeedbackGap, 0, len(rows))
+	for _, row := range rows {
+		gap := models.FeedbackGap{PersonID: row.PersonID, Name: row.Name, Email: row.Email, TeamID: row.TeamID}
+		if row.LastReceivedAt.Valid {
+			last := row.LastReceivedAt.Time
+			gap.LastReceivedAt = &last
+		}
+		gaps = append(gaps, gap)
+	}
+
+	c.JSON(http.StatusOK, gaps)
+}
+
+func GetCategoryStats(c *gin.Context) {
+	query, ok := analyticsFeedbackQuery(c)
+	if !ok {
+		return
+	}
+	if query, _, _, ok = analyticsTargetFilter(c, query); !ok {
+		return
+	}
+
+	report := models.CategoryReport{Categories: []models.CategoryStats{}}
+	if err := query.Session(&gorm.Session{}).
+		Select("category, COUNT(*) AS count, COUNT(rating) AS rated, AVG(rating) AS average_rating").
+		Group("category").Order("count desc, category").Scan(&report.Categories).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute category statistics"})
+		return
+	}
+
+	var overall struct {
+		Total         int64
+		Rated         int64
+		AverageRating *float64
+	}
+	if err := query.Session(&gorm.Session{}).
+		Select("COUNT(*) AS total, COUNT(rating) AS rated, AVG(rating) AS average_rating").
+		Scan(&overall).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute category statistics"})
+		return
+	}
+	report.Total = overall.Total
+	report.Rated = overall.Rated
+	report.AverageRating = overall.AverageRating
+
+	c.JSON(http.StatusOK, report)
+}
+
+func analyticsFeedbackQuery(c *gin.Context) (*gorm.DB, bool) {
+	query := database.GetDB().Model(&models.Feedback{}).Where("moderation_status = ?", models.ModerationApproved)
+
+	for _, bound := range []struct {
+		param  string
+		clause string
+	}{
+		{"from", "created_at >= ?"},
+		{"to", "created_at < ?"},
+	} {
+		value := c.Query(bound.param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + bound.param + ", expected RFC3339"})
+			return nil, false
+		}
+		query = query.Where(bound.clause, t)
+	}
+
+	return query, true
+}
+
+func analyticsTargetFilter(c *gin.Context, query *gorm.DB) (*gorm.DB, string, uint, bool) {
+	targetType := c.Query("target_type")
+	if targetType == "" {
+		if c.Query("target_id") != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "target_type is required with target_id"})
+			return nil, "", 0, false
+		}
+		return query, "", 0, true
+	}
+	if targetType != "person" && targetType != "team" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "target_type must be person or team"})
+		return nil, "", 0, false
+	}
+	query = query.Where("target_type = ?", targetType)
+
+	var targetID uint
+	if value := c.Query("target_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid target_id"})
+			return nil, "", 0, false
+		}
+		targetID = uint(id)
+		query = query.Where("target_id = ?", targetID)
+	}
+
+	return query, targetType, targetID, true
+}
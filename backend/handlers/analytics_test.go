@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupAnalyticsTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic("Failed to connect to test database")
+	}
+
+	err = database.Migrate(db)
+	if err != nil {
+		panic("Failed to migrate test database")
+	}
+
+	database.DB = db
+
+	r := gin.New()
+
+	api := r.Group("/api/v1")
+	analytics := api.Group("/analytics")
+	{
+		analytics.GET("/volume", GetFeedbackVolume)
+		analytics.GET("/given-received", GetGivenReceived)
+		analytics.GET("/gaps", GetFeedbackGaps)
+		analytics.GET("/categories", GetCategoryStats)
+	}
+
+	return r
+}
+
+func TestGetFeedbackVolume(t *testing.T) {
+	router := setupAnalyticsTestRouter()
+	alice := createTestPerson(t, "Alice", "analytics.alice1@example.com", "")
+	bob := createTestPerson(t, "Bob", "analytics.bob1@example.com", "")
+
+	createAnalyticsTestFeedback(t, alice.ID, nil, "", intPtr(4), time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC))
+	createAnalyticsTestFeedback(t, alice.ID, nil, "", intPtr(2), time.Date(2024, 1, 3, 9, 0, 0, 0, time.UTC))
+	createAnalyticsTestFeedback(t, alice.ID, nil, "", nil, time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC))
+	createAnalyticsTestFeedback(t, bob.ID, nil, "", nil, time.Date(2024, 2, 7, 9, 0, 0, 0, time.UTC))
+
+	t.Run("should bucket by week", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/analytics/volume?interval=week&target_type=person&target_id=%d", alice.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var series models.VolumeSeries
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &series))
+		assert.Equal(t, int64(3), series.Total)
+		assert.Len(t, series.Points, 2)
+		assert.Equal(t, "2024-01-01", series.Points[0].Bucket)
+		assert.Equal(t, int64(2), series.Points[0].Count)
+		assert.Equal(t, 3.0, *series.Points[0].AverageRating)
+		assert.Equal(t, "2024-01-08", series.Points[1].Bucket)
+		assert.Nil(t, series.Points[1].AverageRating)
+	})
+
+	t.Run("should bucket by month within range", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", "/api/v1/analytics/volume?interval=month&from=2024-01-02T00:00:00Z", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var series models.VolumeSeries
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &series))
+		assert.Equal(t, []models.VolumePoint{
+			{Bucket: "2024-01", Count: 2, AverageRating: floatPtr(2)},
+			{Bucket: "2024-02", Count: 1},
+		}, series.Points)
+	})
+
+	t.Run("should bucket in the requested time zone", func(t *testing.T) {
+		carol := createTestPerson(t, "Carol", "analytics.carol1@example.com", "")
+		createAnalyticsTestFeedback(t, carol.ID, nil, "", nil, time.Date(2024, 3, 4, 23, 30, 0, 0, time.UTC))
+
+		url := fmt.Sprintf("/api/v1/analytics/volume?target_type=person&target_id=%d", carol.ID)
+		w := makeRequest(t, router, "GET", url, nil)
+		var series models.VolumeSeries
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &series))
+		assert.Equal(t, "UTC", series.TimeZone)
+		assert.Equal(t, "2024-03-04", series.Points[0].Bucket)
+
+		w = makeRequest(t, router, "GET", url+"&tz=Europe/Berlin", nil)
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &series))
+		assert.Equal(t, "Europe/Berlin", series.TimeZone)
+		assert.Equal(t, "2024-03-05", series.Points[0].Bucket)
+
+		createAnalyticsTestFeedback(t, carol.ID, nil, "", nil, time.Date(2024, 3, 11, 2, 0, 0, 0, time.UTC))
+		w = makeRequest(t, router, "GET", url+"&interval=week&tz=America/New_York", nil)
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &series))
+		assert.Equal(t, []models.VolumePoint{{Bucket: "2024-03-04", Count: 2}}, series.Points)
+	})
+
+	t.Run("should reject unknown interval", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", "/api/v1/analytics/volume?interval=year", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = makeRequest(t, router, "GET", "/api/v1/analytics/volume?tz=Mars/Olympus", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestGetGivenReceived(t *testing.T) {
+	router := setupAnalyticsTestRouter()
+	alice := createTestPerson(t, "Alice", "analytics.alice2@example.com", "")
+	bob := createTestPerson(t, "Bob", "analytics.bob2@example.com", "")
+
+	now := time.Now()
+	createAnalyticsTestFeedback(t, bob.ID, &alice.ID, "", nil, now)
+	createAnalyticsTestFeedback(t, bob.ID, &alice.ID, "", nil, now)
+	createAnalyticsTestFeedback(t, alice.ID, &bob.ID, "", nil, now)
+	createAnalyticsTestFeedback(t, alice.ID, nil, "", nil, now)
+
+	w := makeRequest(t, router, "GET", "/api/v1/analytics/given-received", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var results []models.GivenReceived
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &results))
+	assert.Len(t, results, 2)
+	assert.Equal(t, "Alice", results[0].Name)
+	assert.Equal(t, int64(2), results[0].Given)
+	assert.Equal(t, int64(2), results[0].Received)
+	assert.Equal(t, 1.0, *results[0].Ratio)
+	assert.Equal(t, int64(1), results[1].Given)
+	assert.Equal(t, 0.5, *results[1].Ratio)
+}
+
+func TestGetFeedbackGaps(t *testing.T) {
+	router := setupAnalyticsTestRouter()
+	recent := createTestPerson(t, "Recent", "analytics.recent3@example.com", "")
+	stale := createTestPerson(t, "Stale", "analytics.stale3@example.com", "")
+	never := createTestPerson(t, "Never", "analytics.never3@example.com", "")
+
+	createAnalyticsTestFeedback(t, recent.ID, nil, "", nil, time.Now().Add(-24*time.Hour))
+	staleAt := time.Now().Add(-60 * 24 * time.Hour).UTC().Truncate(time.Second)
+	createAnalyticsTestFeedback(t, stale.ID, nil, "", nil, staleAt)
+
+	w := makeRequest(t, router, "GET", "/api/v1/analytics/gaps?days=30", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var gaps []models.FeedbackGap
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &gaps))
+	assert.Len(t, gaps, 2)
+	assert.Equal(t, never.ID, gaps[0].PersonID)
+	assert.Nil(t, gaps[0].LastReceivedAt)
+	assert.Equal(t, stale.ID, gaps[1].PersonID)
+	assert.True(t, staleAt.Equal(*gaps[1].LastReceivedAt))
+
+	w = makeRequest(t, router, "GET", "/api/v1/analytics/gaps?days=0", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetCategoryStats(t *testing.T) {
+	router := setupAnalyticsTestRouter()
+	alice := createTestPerson(t, "Alice", "analytics.alice4@example.com", "")
+
+	now := time.Now()
+	createAnalyticsTestFeedback(t, alice.ID, nil, "praise", intPtr(5), now)
+	createAnalyticsTestFeedback(t, alice.ID, nil, "praise", intPtr(4), now)
+	createAnalyticsTestFeedback(t, alice.ID, nil, "growth", intPtr(2), now)
+	createAnalyticsTestFeedback(t, alice.ID, nil, "growth", nil, now)
+	createAnalyticsTestFeedback(t, alice.ID, nil, "growth", nil, now)
+	for _, status := range []string{models.ModerationPending, models.ModerationRejected} {
+		held := createAnalyticsTestFeedback(t, alice.ID, nil, "growth", intPtr(1), now)
+		database.GetDB().Model(&held).Update("moderation_status", status)
+	}
+
+	w := makeRequest(t, router, "GET", "/api/v1/analytics/categories", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var report models.CategoryReport
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, int64(5), report.Total)
+	assert.Equal(t, int64(3), report.Rated)
+	assert.InDelta(t, 11.0/3.0, *report.AverageRating, 0.001)
+	assert.Equal(t, []models.CategoryStats{
+		{Category: "growth", Count: 3, Rated: 1, AverageRating: floatPtr(2)},
+		{Category: "praise", Count: 2, Rated: 2, AverageRating: floatPtr(4.5)},
+	}, report.Categories)
+}
+
+func createAnalyticsTestFeedback(t *testing.T, targetID uint, authorID *uint, category string, rating *int, createdAt time.Time) models.Feedback {
+	feedback := models.Feedback{
+		Content:    "Analytics feedback",
+		TargetType: "person",
+		TargetID:   targetID,
+		TargetName: "Someone",
+		AuthorID:   authorID,
+		Category:   category,
+		Rating:     rating,
+		CreatedAt:  createdAt,
+	}
+
+	err := database.GetDB().Create(&feedback).Error
+	assert.NoError(t, err)
+
+	return feedback
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/http"
+	"coaching-backend/chat"
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+)
+
+func GetTeamChat(c *gin.Context) {
+	team, ok := loadTeam(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, team.Chat)
+}
+
+func UpdateTeamChat(c *gin.Context) {
+	team, ok := loadTeam(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateTeamChatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := chat.ValidateTemplates(req.FeedbackTemplate, req.MembershipTemplate); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// The URL is never returned, so leaving it out keeps the stored one.
+	webhookURL := req.WebhookURL
+	if webhookURL == "" {
+		webhookURL = team.Chat.WebhookURL
+	}
+	enabled := req.Enabled == nil || *req.Enabled
+	if enabled && webhookURL == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "webhook_url is required when chat is enabled"})
+		return
+	}
+
+	team.Chat = models.TeamChat{
+		WebhookURL:         webhookURL,
+		Enabled:            enabled,
+		IncludePrivate:     req.IncludePrivate,
+		FeedbackTemplate:   req.FeedbackTemplate,
+		MembershipTemplate: req.MembershipTemplate,
+	}
+	if err := database.GetDB().Save(&team).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update team chat"})
+		return
+	}
+
+	c.JSON(http.StatusOK, team.Chat)
+}
+
+func DeleteTeamChat(c *gin.Context) {
+	team, ok := loadTeam(c)
+	if !ok {
+		return
+	}
+
+	team.Chat = models.TeamChat{}
+	if err := database.GetDB().Save(&team).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete team chat"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Team chat deleted successfully"})
+}
+
+func TestTeamChat(c *gin.Context) {
+	team, ok := loadTeam(c)
+	if !ok {
+		return
+	}
+	if team.Chat.WebhookURL == "" {
+		c.JSON(http.StatusConflict, gin.H{"error": "Team chat is not configured"})
+		return
+	}
+
+	if err := chat.Default.Send(team.Chat.WebhookURL, chat.Text("Feedback notifications for "+team.Name+" are connected.")); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Test message sent"})
+}
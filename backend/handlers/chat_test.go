@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupChatTestRouter(adminToken string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic("Failed to connect to test database")
+	}
+
+	err = database.Migrate(db)
+	if err != nil {
+		panic("Failed to migrate test database")
+	}
+
+	database.DB = db
+
+	r := gin.New()
+
+	api := r.Group("/api/v1")
+	api.GET("/teams/:id", GetTeam)
+	teamChat := api.Group("/teams/:id/chat", RequireAdmin(adminToken))
+	{
+		teamChat.GET("", GetTeamChat)
+		teamChat.PUT("", UpdateTeamChat)
+		teamChat.DELETE("", DeleteTeamChat)
+		teamChat.POST("/test", TestTeamChat)
+	}
+
+	return r
+}
+
+func TestTeamChatSettings(t *testing.T) {
+	router := setupChatTestRouter("")
+	team := createTestTeam(t, "Chat Team", "")
+	url := fmt.Sprintf("/api/v1/teams/%d/chat", team.ID)
+
+	var posted int
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posted++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	t.Run("should report an unconfigured team", func(t *testing.T) {
+		w := makeRequest(t, router, "POST", url+"/test", nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("should save settings without exposing the webhook URL", func(t *testing.T) {
+		w := makeRequest(t, router, "PUT", url, models.UpdateTeamChatRequest{
+			WebhookURL:       receiver.URL,
+			FeedbackTemplate: "{{.Team}}: {{.Content}}",
+		})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), receiver.URL)
+
+		var settings models.TeamChat
+		json.Unmarshal(w.Body.Bytes(), &settings)
+		assert.True(t, settings.Enabled)
+		assert.False(t, settings.IncludePrivate)
+
+		w = makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/teams/%d", team.ID), nil)
+		assert.NotContains(t, w.Body.String(), receiver.URL)
+
+		var saved models.Team
+		database.GetDB().First(&saved, team.ID)
+		assert.Equal(t, receiver.URL, saved.Chat.WebhookURL)
+	})
+
+	t.Run("should send a test message", func(t *testing.T) {
+		w := makeRequest(t, router, "POST", url+"/test", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 1, posted)
+	})
+
+	t.Run("should validate settings", func(t *testing.T) {
+		w := makeRequest(t, router, "PUT", url, models.UpdateTeamChatRequest{WebhookURL: "not a url"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = makeRequest(t, router, "PUT", url, models.UpdateTeamChatRequest{WebhookURL: receiver.URL, MembershipTemplate: "{{.Missing}}"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should toggle chat without resending the webhook URL", func(t *testing.T) {
+		disabled := false
+		w := makeRequest(t, router, "PUT", url, models.UpdateTeamChatRequest{Enabled: &disabled})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var saved models.Team
+		database.GetDB().First(&saved, team.ID)
+		assert.False(t, saved.Chat.Enabled)
+		assert.Equal(t, receiver.URL, saved.Chat.WebhookURL)
+
+		w = makeRequest(t, router, "PUT", url, models.UpdateTeamChatRequest{})
+		assert.Equal(t, http.StatusOK, w.Code)
+		database.GetDB().First(&saved, team.ID)
+		assert.True(t, saved.Chat.Enabled)
+	})
+
+	t.Run("should require a webhook URL to enable chat", func(t *testing.T) {
+		other := createTestTeam(t, "Unconfigured Chat Team", "")
+		otherURL := fmt.Sprintf("/api/v1/teams/%d/chat", other.ID)
+
+		w := makeRequest(t, router, "PUT", otherURL, models.UpdateTeamChatRequest{})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		disabled := false
+		w = makeRequest(t, router, "PUT", otherURL, models.UpdateTeamChatRequest{Enabled: &disabled})
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("should delete settings", func(t *testing.T) {
+		w := makeRequest(t, router, "DELETE", url, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var saved models.Team
+		database.GetDB().First(&saved, team.ID)
+		assert.Equal(t, models.TeamChat{}, saved.Chat)
+	})
+
+	t.Run("should return error for non-existent team", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", "/api/v1/teams/999/chat", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestTeamChatRequiresAdmin(t *testing.T) {
+	router := setupChatTestRouter("s3cret")
+	team := createTestTeam(t, "Guarded Team", "")
+
+	w := makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/teams/%d/chat", team.ID), nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"database/sql"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+	"coaching-backend/database"
+	"coaching-backend/export"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const exportFlushEvery = 500
+
+var (
+	personExportColumns   = []string{"id", "name", "email", "picture", "team_id", "team_name", "manager_id", "created_at", "updated_at"}
+	teamExportColumns     = []string{"id", "name", "logo", "member_count", "created_at", "updated_at"}
+	feedbackExportColumns = []string{"id", "created_at", "target_type", "target_id", "target_name", "author_id", "author_name", "category", "rating", "sentiment", "sentiment_score", "private", "acknowledged_at", "content"}
+)
+
+type personExportRow struct {
+	ID        uint
+	Name      string
+	Email     string
+	Picture   string
+	TeamID    *uint
+	TeamName  *string
+	ManagerID *uint
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type teamExportRow struct {
+	ID          uint
+	Name        string
+	Logo        string
+	MemberCount int64
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type feedbackExportRow struct {
+	ID             uint
+	CreatedAt      time.Time
+	TargetType     string
+	TargetID       uint
+	TargetName     string `gorm:"serializer:encrypted"`
+	AuthorID       *uint
+	AuthorName     *string
+	Category       string
+	Rating         *int
+	Sentiment      string
+	SentimentScore float64
+	Private        bool
+	AcknowledgedAt *time.Time
+	Content        string `gorm:"serializer:encrypted"`
+}
+
+func ExportPersons(c *gin.Context) {
+	query := database.GetDB().Table("people").
+		Select("people.id, people.name, people.email, people.picture, people.team_id, teams.name AS team_name, people.manager_id, people.created_at, people.updated_at").
+		Joins("LEFT JOIN teams ON teams.id = people.team_id").
+		Order("people.id")
+
+	streamExport(c, "persons", personExportColumns, query, func(rows *sql.Rows) ([]interface{}, error) {
+		var r personExportRow
+		if err := database.GetDB().ScanRows(rows, &r); err != nil {
+			return nil, err
+		}
+		return []interface{}{r.ID, r.Name, r.Email, r.Picture, r.TeamID, r.TeamName, r.ManagerID, r.CreatedAt, r.UpdatedAt}, nil
+	})
+}
+
+func ExportTeams(c *gin.Context) {
+	query := database.GetDB().Table("teams").
+		Select("teams.id, teams.name, teams.logo, (SELECT COUNT(*) FROM people WHERE people.team_id = teams.id) AS member_count, teams.created_at, teams.updated_at").
+		Order("teams.id")
+
+	streamExport(c, "teams", teamExportColumns, query, func(rows *sql.Rows) ([]interface{}, error) {
+		var r teamExportRow
+		if err := database.GetDB().ScanRows(rows, &r); err != nil {
+			return nil, err
+		}
+		return []interface{}{r.ID, r.Name, r.Logo, r.MemberCount, r.CreatedAt, r.UpdatedAt}, nil
+	})
+}
+
+func ExportFeedbacks(c *gin.Context) {
+	query := database.GetDB().Table("feedbacks").
+		Select("feedbacks.id, feedbacks.created_at, feedbacks.target_type, feedbacks.target_id, feedbacks.target_name, feedbacks.author_id, people.name AS author_name, feedbacks.category, feedbacks.rating, feedbacks.sentiment, feedbacks.sentiment_score, feedbacks.private, feedbacks.acknowledged_at, feedbacks.content").
+		Joins("LEFT JOIN people ON people.id = feedbacks.author_id").
+		Where("feedbacks.moderation_status = ?", models.ModerationApproved).
+		Order("feedbacks.created_at desc, feedbacks.id desc")
+
+	query, ok := sentimentFilter(c, query)
+	if !ok {
+		return
+	}
+
+	// The same target filter as /feedbacks/by-target, but optional.
+	targetType, targetIDStr := c.Query("target_type"), c.Query("target_id")
+	if targetType != "" || targetIDStr != "" {
+		targetID, err := strconv.Atoi(targetIDStr)
+		if targetType == "" || err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "target_type and a numeric target_id must be given together"})
+			return
+		}
+		query = query.Where("feedbacks.target_type = ? AND feedbacks.target_id = ?", targetType, targetID)
+	}
+
+	streamExport(c, "feedbacks", feedbackExportColumns, query, func(rows *sql.Rows) ([]interface{}, error) {
+		var r feedbackExportRow
+		if err := database.GetDB().ScanRows(rows, &r); err != nil {
+			return nil, err
+		}
+		return []interface{}{r.ID, r.CreatedAt, r.TargetType, r.TargetID, r.TargetName, r.AuthorID, r.AuthorName, r.Category, r.Rating, r.Sentiment, r.SentimentScore, r.Private, r.AcknowledgedAt, r.Content}, nil
+	})
+}
+
+func streamExport(c *gin.Context, resource string, columns []string, query *gorm.DB, scan func(rows *sql.Rows) ([]interface{}, error)) {
+	format := c.DefaultQuery("format", export.FormatCSV)
+	if !export.IsFormat(format) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv, ndjson or xlsx"})
+		return
+	}
+
+	rows, err := query.Rows()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export " + resource})
+		return
+	}
+	defer rows.Close()
+
+	filename := export.Filename(resource, format, time.Now())
+	c.Header("Content-Type", export.ContentType(format))
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	c.Status(http.StatusOK)
+
+	writer, err := export.NewWriter(c.Writer, format, columns)
+	if err == nil {
+		err = writeExportRows(c, writer, rows, scan)
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		// Headers are already sent, so the client only sees a truncated file.
+		log.Printf("export: failed to stream %s: %v", resource, err)
+		c.Abort()
+	}
+}
+
+func writeExportRows(c *gin.Context, writer export.Writer, rows *sql.Rows, scan func(rows *sql.Rows) ([]interface{}, error)) error {
+	written := 0
+	for rows.Next() {
+		values, err := scan(rows)
+		if err != nil {
+			return err
+		}
+		if err := writer.Write(values); err != nil {
+			return err
+		}
+		written++
+		if written%exportFlushEvery == 0 {
+			c.Writer.Flush()
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"coaching-backend/database"
+	"coaching-backend/encryption"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupExportTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic("Failed to connect to test database")
+	}
+
+	err = database.Migrate(db)
+	if err != nil {
+		panic("Failed to migrate test database")
+	}
+
+	database.DB = db
+
+	r := gin.New()
+
+	api := r.Group("/api/v1")
+	api.GET("/persons/export", ExportPersons)
+	api.GET("/teams/export", ExportTeams)
+	api.GET("/feedbacks/export", ExportFeedbacks)
+
+	return r
+}
+
+func readCSVExport(t *testing.T, body string) [][]string {
+	records, err := csv.NewReader(strings.NewReader(body)).ReadAll()
+	assert.NoError(t, err)
+	return records
+}
+
+func TestExportPersons(t *testing.T) {
+	router := setupExportTestRouter()
+	team := createTestTeam(t, "Export Team", "")
+	person := createTestPerson(t, "Export Person", "export@example.com", "")
+	database.GetDB().Model(&person).Update("team_id", team.ID)
+	createTestPerson(t, "Teamless Person", "teamless@example.com", "")
+
+	t.Run("should export CSV with a download filename", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", "/api/v1/persons/export", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Regexp(t, `^attachment; filename=persons-\d{4}-\d{2}-\d{2}\.csv$`, w.Header().Get("Content-Disposition"))
+
+		records := readCSVExport(t, w.Body.String())
+		assert.Len(t, records, 3)
+		assert.Equal(t, personExportColumns, records[0])
+		assert.Equal(t, "Export Person", records[1][1])
+		assert.Equal(t, "Export Team", records[1][5])
+		assert.Equal(t, "", records[2][5])
+	})
+
+	t.Run("should export NDJSON", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", "/api/v1/persons/export?format=ndjson", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+
+		scanner := bufio.NewScanner(w.Body)
+		var lines []map[string]interface{}
+		for scanner.Scan() {
+			var line map[string]interface{}
+			assert.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+			lines = append(lines, line)
+		}
+		assert.Len(t, lines, 2)
+		assert.Equal(t, "export@example.com", lines[0]["email"])
+		assert.Nil(t, lines[1]["team_id"])
+	})
+
+	t.Run("should export XLSX", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", "/api/v1/persons/export?format=xlsx", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Disposition"), ".xlsx")
+		assert.True(t, strings.HasPrefix(w.Body.String(), "PK"))
+	})
+
+	t.Run("should reject unknown formats", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", "/api/v1/persons/export?format=pdf", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestExportTeams(t *testing.T) {
+	router := setupExportTestRouter()
+	team := createTestTeam(t, "Counted Team", "logo.png")
+	person := createTestPerson(t, "Counted Person", "counted@example.com", "")
+	database.GetDB().Model(&person).Update("team_id", team.ID)
+
+	w := makeRequest(t, router, "GET", "/api/v1/teams/export", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	records := readCSVExport(t, w.Body.String())
+	assert.Equal(t, teamExportColumns, records[0])
+	assert.Equal(t, []string{"Counted Team", "logo.png", "1"}, records[1][1:4])
+}
+
+func TestExportFeedbacks(t *testing.T) {
+	router := setupExportTestRouter()
+	author := createTestPerson(t, "Export Author", "export.author@example.com", "")
+	person := createTestPerson(t, "Export Target", "export.target@example.com", "")
+	team := createTestTeam(t, "Export Target Team", "")
+
+	feedbacks := []models.Feedback{
+		{Content: "=1+1 great work", TargetType: "person", TargetID: person.ID, TargetName: person.Name, AuthorID: &author.ID, Sentiment: "positive"},
+		{Content: "Late again", TargetType: "person", TargetID: person.ID, TargetName: person.Name, Sentiment: "negative"},
+		{Content: "Team note", TargetType: "team", TargetID: team.ID, TargetName: team.Name, Sentiment: "neutral"},
+		{Content: "Held for review", TargetType: "person", TargetID: person.ID, TargetName: person.Name, ModerationStatus: models.ModerationPending},
+	}
+	assert.NoError(t, database.GetDB().Create(&feedbacks).Error)
+
+	t.Run("should export approved feedback only", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", "/api/v1/feedbacks/export", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		records := readCSVExport(t, w.Body.String())
+		assert.Len(t, records, 4)
+		assert.Equal(t, feedbackExportColumns, records[0])
+	})
+
+	t.Run("should honor the list filters", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/feedbacks/export?sentiment=positive&target_type=person&target_id=%d", person.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		records := readCSVExport(t, w.Body.String())
+		assert.Len(t, records, 2)
+		assert.Equal(t, "Export Author", records[1][6])
+		assert.Equal(t, "'=1+1 great work", records[1][13])
+	})
+
+	t.Run("should reject invalid filters", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", "/api/v1/feedbacks/export?sentiment=angry", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = makeRequest(t, router, "GET", "/api/v1/feedbacks/export?target_id=1", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should decrypt encrypted feedback", func(t *testing.T) {
+		keyring, err := encryption.NewKeyring([]encryption.Key{{ID: "1", Secret: bytes.Repeat([]byte{'k'}, 32)}}, bytes.Repeat([]byte{'i'}, 32))
+		assert.NoError(t, err)
+		encryption.Configure(keyring)
+		defer encryption.Configure(nil)
+
+		secret := models.Feedback{Content: "Encrypted note", TargetType: "team", TargetID: team.ID, TargetName: team.Name}
+		assert.NoError(t, database.GetDB().Create(&secret).Error)
+
+		w := makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/feedbacks/export?target_type=team&target_id=%d", team.ID), nil)
+		records := readCSVExport(t, w.Body.String())
+		assert.Len(t, records, 3)
+		assert.Equal(t, "Export Target Team", records[1][4])
+		assert.Equal(t, "Encrypted note", records[1][13])
+	})
+}
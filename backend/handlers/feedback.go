@@ -1,13 +1,25 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 	"coaching-backend/database"
+	"coaching-backend/encryption"
+	"coaching-backend/events"
+	"coaching-backend/inbox"
 	"coaching-backend/models"
+	"coaching-backend/outbox"
+	"coaching-backend/sentiment"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
+var errFeedbackAcknowledged = errors.New("Feedback has already been acknowledged")
+var errFeedbackOnLegalHold = errors.New("Feedback is on legal hold")
+
 func CreateFeedback(c *gin.Context) {
 	var req models.CreateFeedbackRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -15,41 +27,145 @@ func CreateFeedback(c *gin.Context) {
 		return
 	}
 
-	var targetName string
-	if req.TargetType == "person" {
-		var person models.Person
-		if err := database.GetDB().First(&person, req.TargetID).Error; err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Person not found"})
+	targetName, err := lookupTargetName(req.TargetType, req.TargetID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.AuthorID != nil {
+		var author models.Person
+		if err := database.GetDB().First(&author, *req.AuthorID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Author not found"})
 			return
 		}
-		targetName = person.Name
-	} else if req.TargetType == "team" {
-		var team models.Team
-		if err := database.GetDB().First(&team, req.TargetID).Error; err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+	}
+
+	var feedbackRequest *models.FeedbackRequest
+	if req.RequestID != nil {
+		feedbackRequest, err = loadOpenFeedbackRequest(*req.RequestID)
+		if err != nil {
+			c.JSON(feedbackRequestErrorStatus(err), gin.H{"error": err.Error()})
 			return
 		}
-		targetName = team.Name
+		if req.TargetType != "person" || req.TargetID != feedbackRequest.RequesterID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Feedback must target the person who requested it"})
+			return
+		}
+		if req.AuthorID != nil && *req.AuthorID != feedbackRequest.RecipientID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Feedback must be written by the requested colleague"})
+			return
+		}
+		req.AuthorID = &feedbackRequest.RecipientID
+	}
+
+	content := req.Content
+	var answers []models.FeedbackAnswer
+	if req.TemplateID != nil {
+		var template models.FeedbackTemplate
+		if err := database.GetDB().Preload("Questions", orderQuestions).First(&template, *req.TemplateID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+			return
+		}
+
+		answers, err = buildTemplateAnswers(template, req.Answers)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		content = renderFeedbackContent(req.Content, answers)
+		if content == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Content or at least one answer is required"})
+			return
+		}
+	} else if len(req.Answers) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "answers require a template_id"})
+		return
 	}
 
 	feedback := models.Feedback{
-		Content:    req.Content,
-		TargetType: req.TargetType,
-		TargetID:   req.TargetID,
-		TargetName: targetName,
+		Content:         content,
+		TargetType:      req.TargetType,
+		TargetID:        req.TargetID,
+		TargetName:      targetName,
+		TargetNameIndex: encryption.BlindIndex(targetName),
+		TemplateID:      req.TemplateID,
+		Answers:         answers,
+		AuthorID:        req.AuthorID,
+		RequestID:       req.RequestID,
+		Category:        strings.ToLower(strings.TrimSpace(req.Category)),
+		Rating:          req.Rating,
+		Private:         req.Private,
+	}
+
+	moderated, err := moderateFeedback(&feedback)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create feedback"})
+		return
 	}
+	if moderated.Rejected {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Feedback was rejected by moderation", "reasons": moderated.Reasons})
+		return
+	}
+	applySentiment(&feedback)
 
-	if err := database.GetDB().Create(&feedback).Error; err != nil {
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&feedback).Error; err != nil {
+			return err
+		}
+		if feedbackRequest != nil {
+			if err := fulfillFeedbackRequest(tx, feedbackRequest, feedback.ID); err != nil {
+				return err
+			}
+		}
+		if feedback.ModerationStatus != models.ModerationApproved {
+			return nil
+		}
+		if err := outbox.Add(tx, events.FeedbackCreated, feedback); err != nil {
+			return err
+		}
+		return inbox.FeedbackReceived(tx, feedback)
+	})
+	if errors.Is(err, errFeedbackRequestClosed) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create feedback"})
 		return
 	}
 
+	outbox.Notify()
 	c.JSON(http.StatusCreated, feedback)
 }
 
+func lookupTargetName(targetType string, targetID uint) (string, error) {
+	switch targetType {
+	case "person":
+		var person models.Person
+		if err := database.GetDB().First(&person, targetID).Error; err != nil {
+			return "", errors.New("Person not found")
+		}
+		return person.Name, nil
+	case "team":
+		var team models.Team
+		if err := database.GetDB().First(&team, targetID).Error; err != nil {
+			return "", errors.New("Team not found")
+		}
+		return team.Name, nil
+	}
+	return "", errors.New("Unknown target type")
+}
+
 func GetFeedbacks(c *gin.Context) {
+	query, ok := sentimentFilter(c, database.GetDB())
+	if !ok {
+		return
+	}
+
 	var feedbacks []models.Feedback
-	if err := database.GetDB().Order("created_at desc").Find(&feedbacks).Error; err != nil {
+	if err := query.Preload("Answers").Where("moderation_status = ?", models.ModerationApproved).
+		Order("created_at desc").Find(&feedbacks).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch feedbacks"})
 		return
 	}
@@ -65,7 +181,8 @@ func GetFeedback(c *gin.Context) {
 	}
 
 	var feedback models.Feedback
-	if err := database.GetDB().First(&feedback, id).Error; err != nil {
+	if err := database.GetDB().Preload("Answers").Where("moderation_status = ?", models.ModerationApproved).
+		First(&feedback, id).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Feedback not found"})
 		return
 	}
@@ -76,20 +193,32 @@ func GetFeedback(c *gin.Context) {
 func GetFeedbacksByTarget(c *gin.Context) {
 	targetType := c.Query("target_type")
 	targetIDStr := c.Query("target_id")
+	targetName := c.Query("target_name")
 
-	if targetType == "" || targetIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "target_type and target_id are required"})
+	if targetType == "" || (targetIDStr == "" && targetName == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "target_type and target_id are required, or target_type and target_name"})
 		return
 	}
 
-	targetID, err := strconv.Atoi(targetIDStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid target_id"})
+	query, ok := sentimentFilter(c, database.GetDB())
+	if !ok {
 		return
 	}
 
+	// Target names are encrypted, so they are matched through their blind index.
+	if targetIDStr != "" {
+		targetID, err := strconv.Atoi(targetIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid target_id"})
+			return
+		}
+		query = query.Where("target_id = ?", targetID)
+	} else {
+		query = query.Where("target_name_index = ?", encryption.BlindIndex(targetName))
+	}
+
 	var feedbacks []models.Feedback
-	if err := database.GetDB().Where("target_type = ? AND target_id = ?", targetType, targetID).
+	if err := query.Preload("Answers").Where("target_type = ? AND moderation_status = ?", targetType, models.ModerationApproved).
 		Order("created_at desc").Find(&feedbacks).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch feedbacks"})
 		return
@@ -98,6 +227,108 @@ func GetFeedbacksByTarget(c *gin.Context) {
 	c.JSON(http.StatusOK, feedbacks)
 }
 
+func UpdateFeedback(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid feedback ID"})
+		return
+	}
+
+	var req models.UpdateFeedbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var feedback models.Feedback
+	if err := database.GetDB().First(&feedback, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feedback not found"})
+		return
+	}
+	if feedback.LegalHoldAt != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": errFeedbackOnLegalHold.Error()})
+		return
+	}
+
+	content := req.Content
+	var answers []models.FeedbackAnswer
+	if feedback.TemplateID != nil {
+		if len(req.Answers) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Feedback written with a template is edited through its answers"})
+			return
+		}
+		var template models.FeedbackTemplate
+		if err := database.GetDB().Preload("Questions", orderQuestions).First(&template, *feedback.TemplateID).Error; err != nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "The feedback's template no longer exists"})
+			return
+		}
+
+		answers, err = buildTemplateAnswers(template, req.Answers)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		content = renderFeedbackContent(req.Content, answers)
+		if content == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Content or at least one answer is required"})
+			return
+		}
+	} else if len(req.Answers) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "answers require a template_id"})
+		return
+	}
+
+	feedback.Content = content
+	feedback.Answers = answers
+	feedback.Category = strings.ToLower(strings.TrimSpace(req.Category))
+	feedback.Rating = req.Rating
+
+	moderated, err := moderateFeedback(&feedback)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update feedback"})
+		return
+	}
+	if moderated.Rejected {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Feedback was rejected by moderation", "reasons": moderated.Reasons})
+		return
+	}
+	applySentiment(&feedback)
+
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		// Saving would also clear a hold placed since the feedback was read.
+		var held int64
+		if err := tx.Model(&models.Feedback{}).Where("id = ? AND legal_hold_at IS NOT NULL", feedback.ID).Count(&held).Error; err != nil {
+			return err
+		}
+		if held > 0 {
+			return errFeedbackOnLegalHold
+		}
+		if feedback.TemplateID != nil {
+			if err := tx.Where("feedback_id = ?", feedback.ID).Delete(&models.FeedbackAnswer{}).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Save(&feedback).Error; err != nil {
+			return err
+		}
+		if feedback.ModerationStatus != models.ModerationApproved {
+			return nil
+		}
+		return outbox.Add(tx, events.FeedbackUpdated, feedback)
+	})
+	if errors.Is(err, errFeedbackOnLegalHold) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update feedback"})
+		return
+	}
+
+	outbox.Notify()
+	c.JSON(http.StatusOK, feedback)
+}
+
 func DeleteFeedback(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -105,10 +336,103 @@ func DeleteFeedback(c *gin.Context) {
 		return
 	}
 
-	if err := database.GetDB().Delete(&models.Feedback{}, id).Error; err != nil {
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		var feedback models.Feedback
+		if err := tx.First(&feedback, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		if feedback.LegalHoldAt != nil {
+			return errFeedbackOnLegalHold
+		}
+
+		if err := tx.Where("feedback_id = ?", id).Delete(&models.FeedbackAnswer{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM session_feedbacks WHERE feedback_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("feedback_id = ?", id).Delete(&models.Notification{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&feedback).Error; err != nil {
+			return err
+		}
+		return outbox.Add(tx, events.FeedbackDeleted, gin.H{"id": feedback.ID, "target_type": feedback.TargetType, "target_id": feedback.TargetID})
+	})
+	if errors.Is(err, errFeedbackOnLegalHold) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete feedback"})
 		return
 	}
 
+	outbox.Notify()
 	c.JSON(http.StatusOK, gin.H{"message": "Feedback deleted successfully"})
 }
+
+func AcknowledgeFeedback(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid feedback ID"})
+		return
+	}
+
+	var feedback models.Feedback
+	if err := database.GetDB().First(&feedback, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feedback not found"})
+		return
+	}
+	if feedback.ModerationStatus != models.ModerationApproved {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only approved feedback can be acknowledged"})
+		return
+	}
+
+	now := time.Now()
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Feedback{}).
+			Where("id = ? AND acknowledged_at IS NULL", feedback.ID).
+			Update("acknowledged_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errFeedbackAcknowledged
+		}
+
+		feedback.AcknowledgedAt = &now
+		return inbox.FeedbackAcknowledged(tx, feedback)
+	})
+	if errors.Is(err, errFeedbackAcknowledged) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to acknowledge feedback"})
+		return
+	}
+
+	c.JSON(http.StatusOK, feedback)
+}
+
+func applySentiment(feedback *models.Feedback) {
+	result := sentiment.Analyze(feedback.Content)
+	feedback.Sentiment = result.Label
+	feedback.SentimentScore = result.Score
+}
+
+func sentimentFilter(c *gin.Context, query *gorm.DB) (*gorm.DB, bool) {
+	value := c.Query("sentiment")
+	if value == "" {
+		return query, true
+	}
+	if !sentiment.IsLabel(value) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sentiment must be positive, neutral or negative"})
+		return nil, false
+	}
+	return query.Where("sentiment = ?", value), true
+}
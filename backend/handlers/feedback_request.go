@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var (
+	errFeedbackRequestNotFound = errors.New("Feedback request not found")
+	errFeedbackRequestClosed   = errors.New("Feedback request is no longer pending")
+)
+
+func CreateFeedbackRequest(c *gin.Context) {
+	var req models.CreateFeedbackRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if len(req.RecipientIDs) == 0 && req.TeamID == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "recipient_ids or team_id is required"})
+		return
+	}
+
+	if req.DueDate != nil && req.DueDate.Before(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "due_date must be in the future"})
+		return
+	}
+
+	var requester models.Person
+	if err := database.GetDB().First(&requester, req.RequesterID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Requester not found"})
+		return
+	}
+
+	if req.TemplateID != nil {
+		var template models.FeedbackTemplate
+		if err := database.GetDB().First(&template, *req.TemplateID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+			return
+		}
+	}
+
+	recipientIDs := make([]uint, 0, len(req.RecipientIDs))
+	seen := map[uint]bool{requester.ID: true}
+	for _, id := range req.RecipientIDs {
+		if !seen[id] {
+			seen[id] = true
+			recipientIDs = append(recipientIDs, id)
+		}
+	}
+
+	if req.TeamID != nil {
+		var team models.Team
+		if err := database.GetDB().Preload("Members").First(&team, *req.TeamID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+			return
+		}
+		for _, member := range team.Members {
+			if !seen[member.ID] {
+				seen[member.ID] = true
+				recipientIDs = append(recipientIDs, member.ID)
+			}
+		}
+	}
+
+	if len(recipientIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No colleagues to ask for feedback"})
+		return
+	}
+
+	var count int64
+	if err := database.GetDB().Model(&models.Person{}).Where("id IN ?", recipientIDs).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create feedback requests"})
+		return
+	}
+	if int(count) != len(recipientIDs) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recipient not found"})
+		return
+	}
+
+	requests := make([]models.FeedbackRequest, 0, len(recipientIDs))
+	for _, id := range recipientIDs {
+		requests = append(requests, models.FeedbackRequest{
+			RequesterID: requester.ID,
+			RecipientID: id,
+			TeamID:      req.TeamID,
+			TemplateID:  req.TemplateID,
+			Message:     req.Message,
+			DueDate:     req.DueDate,
+			Status:      models.RequestStatusPending,
+		})
+	}
+
+	if err := database.GetDB().Create(&requests).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create feedback requests"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, requests)
+}
+
+func GetFeedbackRequests(c *gin.Context) {
+	if err := expireFeedbackRequests(database.GetDB()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch feedback requests"})
+		return
+	}
+
+	query := database.GetDB().Preload("Requester").Preload("Recipient")
+	for _, filter := range []string{"requester_id", "recipient_id"} {
+		if value := c.Query(filter); value != "" {
+			id, err := strconv.Atoi(value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + filter})
+				return
+			}
+			query = query.Where(filter+" = ?", id)
+		}
+	}
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var requests []models.FeedbackRequest
+	if err := query.Order("created_at desc").Find(&requests).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch feedback requests"})
+		return
+	}
+
+	c.JSON(http.StatusOK, requests)
+}
+
+func GetFeedbackRequest(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid feedback request ID"})
+		return
+	}
+
+	if err := expireFeedbackRequests(database.GetDB()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch feedback request"})
+		return
+	}
+
+	var request models.FeedbackRequest
+	if err := database.GetDB().Preload("Requester").Preload("Recipient").First(&request, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feedback request not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, request)
+}
+
+func DeclineFeedbackRequest(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid feedback request ID"})
+		return
+	}
+
+	request, err := loadOpenFeedbackRequest(uint(id))
+	if err != nil {
+		c.JSON(feedbackRequestErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	now := time.Now()
+	result := database.GetDB().Model(&models.FeedbackRequest{}).
+		Where("id = ? AND status = ?", request.ID, models.RequestStatusPending).
+		Updates(map[string]interface{}{"status": models.RequestStatusDeclined, "responded_at": now})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decline feedback request"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": errFeedbackRequestClosed.Error()})
+		return
+	}
+
+	request.Status = models.RequestStatusDeclined
+	request.RespondedAt = &now
+	c.JSON(http.StatusOK, request)
+}
+
+func DeleteFeedbackRequest(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid feedback request ID"})
+		return
+	}
+
+	if err := database.GetDB().Delete(&models.FeedbackRequest{}, id).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete feedback request"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Feedback request deleted successfully"})
+}
+
+func expireFeedbackRequests(db *gorm.DB) error {
+	return db.Model(&models.FeedbackRequest{}).
+		Where("status = ? AND due_date IS NOT NULL AND due_date < ?", models.RequestStatusPending, time.Now()).
+		Update("status", models.RequestStatusExpired).Error
+}
+
+func loadOpenFeedbackRequest(id uint) (*models.FeedbackRequest, error) {
+	if err := expireFeedbackRequests(database.GetDB()); err != nil {
+		return nil, err
+	}
+
+	var request models.FeedbackRequest
+	if err := database.GetDB().First(&request, id).Error; err != nil {
+		return nil, errFeedbackRequestNotFound
+	}
+	if request.Status != models.RequestStatusPending {
+		return nil, errFeedbackRequestClosed
+	}
+	return &request, nil
+}
+
+func fulfillFeedbackRequest(tx *gorm.DB, request *models.FeedbackRequest, feedbackID uint) error {
+	now := time.Now()
+	result := tx.Model(&models.FeedbackRequest{}).
+		Where("id = ? AND status = ?", request.ID, models.RequestStatusPending).
+		Updates(map[string]interface{}{
+			"status":       models.RequestStatusFulfilled,
+			"feedback_id":  feedbackID,
+			"responded_at": now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errFeedbackRequestClosed
+	}
+	return nil
+}
+
+func feedbackRequestErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, errFeedbackRequestNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, errFeedbackRequestClosed):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupFeedbackRequestTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic("Failed to connect to test database")
+	}
+
+	err = database.Migrate(db)
+	if err != nil {
+		panic("Failed to migrate test database")
+	}
+
+	database.DB = db
+
+	r := gin.New()
+
+	api := r.Group("/api/v1")
+	feedbackRequests := api.Group("/feedback-requests")
+	{
+		feedbackRequests.POST("", CreateFeedbackRequest)
+		feedbackRequests.GET("", GetFeedbackRequests)
+		feedbackRequests.GET("/:id", GetFeedbackRequest)
+		feedbackRequests.POST("/:id/decline", DeclineFeedbackRequest)
+		feedbackRequests.DELETE("/:id", DeleteFeedbackRequest)
+	}
+	api.POST("/feedbacks", CreateFeedback)
+
+	return r
+}
+
+func TestCreateFeedbackRequest(t *testing.T) {
+	router := setupFeedbackRequestTestRouter()
+
+	t.Run("should create one request per colleague", func(t *testing.T) {
+		requester := createTestPerson(t, "Requester", "fr.requester1@example.com", "")
+		alice := createTestPerson(t, "Alice", "fr.alice1@example.com", "")
+		bob := createTestPerson(t, "Bob", "fr.bob1@example.com", "")
+		due := time.Now().Add(48 * time.Hour)
+
+		reqBody := models.CreateFeedbackRequestRequest{
+			RequesterID:  requester.ID,
+			RecipientIDs: []uint{alice.ID, bob.ID, alice.ID, requester.ID},
+			Message:      "How did the launch go?",
+			DueDate:      &due,
+		}
+
+		w := makeRequest(t, router, "POST", "/api/v1/feedback-requests", reqBody)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response []models.FeedbackRequest
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response, 2)
+		assert.Equal(t, models.RequestStatusPending, response[0].Status)
+		assert.Equal(t, alice.ID, response[0].RecipientID)
+		assert.Equal(t, bob.ID, response[1].RecipientID)
+	})
+
+	t.Run("should expand a team into its members", func(t *testing.T) {
+		team := createTestTeam(t, "Platform", "")
+		requester := createTestPerson(t, "Requester", "fr.requester2@example.com", "")
+		member := createTestPerson(t, "Member", "fr.member2@example.com", "")
+		database.GetDB().Model(&requester).Update("team_id", team.ID)
+		database.GetDB().Model(&member).Update("team_id", team.ID)
+
+		reqBody := models.CreateFeedbackRequestRequest{
+			RequesterID: requester.ID,
+			TeamID:      &team.ID,
+		}
+
+		w := makeRequest(t, router, "POST", "/api/v1/feedback-requests", reqBody)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response []models.FeedbackRequest
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response, 1)
+		assert.Equal(t, member.ID, response[0].RecipientID)
+		assert.Equal(t, team.ID, *response[0].TeamID)
+	})
+
+	t.Run("should require recipients or team", func(t *testing.T) {
+		requester := createTestPerson(t, "Requester", "fr.requester3@example.com", "")
+
+		reqBody := models.CreateFeedbackRequestRequest{RequesterID: requester.ID}
+
+		w := makeRequest(t, router, "POST", "/api/v1/feedback-requests", reqBody)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should return error for unknown recipient", func(t *testing.T) {
+		requester := createTestPerson(t, "Requester", "fr.requester4@example.com", "")
+
+		reqBody := models.CreateFeedbackRequestRequest{
+			RequesterID:  requester.ID,
+			RecipientIDs: []uint{999},
+		}
+
+		w := makeRequest(t, router, "POST", "/api/v1/feedback-requests", reqBody)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestFulfillFeedbackRequest(t *testing.T) {
+	router := setupFeedbackRequestTestRouter()
+
+	t.Run("should link feedback and mark request fulfilled", func(t *testing.T) {
+		requester := createTestPerson(t, "Requester", "fr.requester5@example.com", "")
+		colleague := createTestPerson(t, "Colleague", "fr.colleague5@example.com", "")
+		request := createFeedbackRequestTestRequest(t, requester.ID, colleague.ID, nil)
+
+		reqBody := models.CreateFeedbackRequest{
+			Content:    "You ran the retro really well",
+			TargetType: "person",
+			TargetID:   requester.ID,
+			RequestID:  &request.ID,
+		}
+
+		w := makeRequest(t, router, "POST", "/api/v1/feedbacks", reqBody)
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var feedback models.Feedback
+		err := json.Unmarshal(w.Body.Bytes(), &feedback)
+		assert.NoError(t, err)
+		assert.Equal(t, colleague.ID, *feedback.AuthorID)
+		assert.Equal(t, request.ID, *feedback.RequestID)
+
+		w = makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/feedback-requests/%d", request.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var fetched models.FeedbackRequest
+		err = json.Unmarshal(w.Body.Bytes(), &fetched)
+		assert.NoError(t, err)
+		assert.Equal(t, models.RequestStatusFulfilled, fetched.Status)
+		assert.Equal(t, feedback.ID, *fetched.FeedbackID)
+		assert.NotNil(t, fetched.RespondedAt)
+
+		w = makeRequest(t, router, "POST", "/api/v1/feedbacks", reqBody)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("should reject feedback about someone else", func(t *testing.T) {
+		requester := createTestPerson(t, "Requester", "fr.requester6@example.com", "")
+		colleague := createTestPerson(t, "Colleague", "fr.colleague6@example.com", "")
+		request := createFeedbackRequestTestRequest(t, requester.ID, colleague.ID, nil)
+
+		reqBody := models.CreateFeedbackRequest{
+			Content:    "Wrong person",
+			TargetType: "person",
+			TargetID:   colleague.ID,
+			RequestID:  &request.ID,
+		}
+
+		w := makeRequest(t, router, "POST", "/api/v1/feedbacks", reqBody)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should expire requests past their due date", func(t *testing.T) {
+		requester := createTestPerson(t, "Requester", "fr.requester7@example.com", "")
+		colleague := createTestPerson(t, "Colleague", "fr.colleague7@example.com", "")
+		past := time.Now().Add(-time.Hour)
+		request := createFeedbackRequestTestRequest(t, requester.ID, colleague.ID, &past)
+
+		reqBody := models.CreateFeedbackRequest{
+			Content:    "Too late",
+			TargetType: "person",
+			TargetID:   requester.ID,
+			RequestID:  &request.ID,
+		}
+
+		w := makeRequest(t, router, "POST", "/api/v1/feedbacks", reqBody)
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		w = makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/feedback-requests?recipient_id=%d&status=expired", colleague.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response []models.FeedbackRequest
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response, 1)
+	})
+}
+
+func TestDeclineFeedbackRequest(t *testing.T) {
+	router := setupFeedbackRequestTestRouter()
+
+	requester := createTestPerson(t, "Requester", "fr.requester8@example.com", "")
+	colleague := createTestPerson(t, "Colleague", "fr.colleague8@example.com", "")
+	request := createFeedbackRequestTestRequest(t, requester.ID, colleague.ID, nil)
+
+	w := makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/feedback-requests/%d/decline", request.ID), nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.FeedbackRequest
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, models.RequestStatusDeclined, response.Status)
+
+	w = makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/feedback-requests/%d/decline", request.ID), nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = makeRequest(t, router, "POST", "/api/v1/feedback-requests/999/decline", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func createFeedbackRequestTestRequest(t *testing.T, requesterID, recipientID uint, due *time.Time) models.FeedbackRequest {
+	request := models.FeedbackRequest{
+		RequesterID: requesterID,
+		RecipientID: recipientID,
+		DueDate:     due,
+		Status:      models.RequestStatusPending,
+	}
+
+	err := database.GetDB().Create(&request).Error
+	assert.NoError(t, err)
+
+	return request
+}
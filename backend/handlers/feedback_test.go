@@ -9,6 +9,8 @@ import (
 	"testing"
 
 	"coaching-backend/database"
+	"coaching-backend/encryption"
+	"coaching-backend/events"
 	"coaching-backend/models"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -24,7 +26,7 @@ func setupFeedbackTestRouter() *gin.Engine {
 		panic("Failed to connect to test database")
 	}
 
-	err = db.AutoMigrate(&models.Person{}, &models.Team{}, &models.Feedback{})
+	err = database.Migrate(db)
 	if err != nil {
 		panic("Failed to migrate test database")
 	}
@@ -40,6 +42,7 @@ func setupFeedbackTestRouter() *gin.Engine {
 		feedbacks.GET("", GetFeedbacks)
 		feedbacks.GET("/:id", GetFeedback)
 		feedbacks.GET("/by-target", GetFeedbacksByTarget)
+		feedbacks.PUT("/:id", UpdateFeedback)
 		feedbacks.DELETE("/:id", DeleteFeedback)
 	}
 
@@ -157,6 +160,45 @@ func TestCreateFeedback(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Contains(t, response["error"], "Team not found")
 	})
+
+	t.Run("should store normalized category and rating", func(t *testing.T) {
+		team := createFeedbackTestTeam(t, "Ops Team", "logo.png")
+		rating := 4
+
+		reqBody := models.CreateFeedbackRequest{
+			Content:    "Smooth on-call handover",
+			TargetType: "team",
+			TargetID:   team.ID,
+			Category:   " Praise ",
+			Rating:     &rating,
+		}
+
+		w := makeFeedbackRequest(t, router, "POST", "/api/v1/feedbacks", reqBody)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response models.Feedback
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "praise", response.Category)
+		assert.Equal(t, 4, *response.Rating)
+	})
+
+	t.Run("should return error for rating out of range", func(t *testing.T) {
+		team := createFeedbackTestTeam(t, "QA Team", "logo.png")
+		rating := 6
+
+		reqBody := models.CreateFeedbackRequest{
+			Content:    "Test feedback",
+			TargetType: "team",
+			TargetID:   team.ID,
+			Rating:     &rating,
+		}
+
+		w := makeFeedbackRequest(t, router, "POST", "/api/v1/feedbacks", reqBody)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
 }
 
 func TestGetFeedbacks(t *testing.T) {
@@ -329,6 +371,133 @@ func TestGetFeedbacksByTarget(t *testing.T) {
 	})
 }
 
+func TestFeedbackEncryption(t *testing.T) {
+	router := setupFeedbackTestRouter()
+	keyring, err := encryption.NewKeyring([]encryption.Key{{ID: "1", Secret: bytes.Repeat([]byte{'k'}, 32)}}, bytes.Repeat([]byte{'i'}, 32))
+	assert.NoError(t, err)
+	encryption.Configure(keyring)
+	defer encryption.Configure(nil)
+
+	person := createFeedbackTestPerson(t, "Ann Smith", "ann@example.com", "")
+	w := makeFeedbackRequest(t, router, "POST", "/api/v1/feedbacks", models.CreateFeedbackRequest{Content: "Needs to delegate more", TargetType: "person", TargetID: person.ID})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var created models.Feedback
+	json.Unmarshal(w.Body.Bytes(), &created)
+
+	t.Run("should store content and target name encrypted", func(t *testing.T) {
+		var stored struct {
+			Content    string
+			TargetName string
+		}
+		database.GetDB().Table("feedbacks").Select("content, target_name").Where("id = ?", created.ID).Scan(&stored)
+		assert.Equal(t, "1", encryption.KeyID(stored.Content))
+		assert.Equal(t, "1", encryption.KeyID(stored.TargetName))
+		assert.NotContains(t, stored.Content, "delegate")
+
+		w := makeFeedbackRequest(t, router, "GET", fmt.Sprintf("/api/v1/feedbacks/%d", created.ID), nil)
+		var response models.Feedback
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, "Needs to delegate more", response.Content)
+		assert.Equal(t, "Ann Smith", response.TargetName)
+	})
+
+	t.Run("should store the event payload encrypted", func(t *testing.T) {
+		var payload string
+		database.GetDB().Table("outbox_events").Select("payload").Where("type = ?", events.FeedbackCreated).Order("id desc").Limit(1).Row().Scan(&payload)
+		assert.Equal(t, "1", encryption.KeyID(payload))
+		assert.NotContains(t, payload, "delegate")
+
+		var row models.OutboxEvent
+		database.GetDB().Where("type = ?", events.FeedbackCreated).Order("id desc").First(&row)
+		assert.Contains(t, row.Payload, "Needs to delegate more")
+	})
+
+	t.Run("should find feedback by target name", func(t *testing.T) {
+		createFeedbackTestFeedback(t, "Other", "person", person.ID+1, "Bob Jones")
+
+		w := makeFeedbackRequest(t, router, "GET", "/api/v1/feedbacks/by-target?target_type=person&target_name=ann%20smith", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response []models.Feedback
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Len(t, response, 1)
+		assert.Equal(t, created.ID, response[0].ID)
+
+		w = makeFeedbackRequest(t, router, "GET", "/api/v1/feedbacks/by-target?target_type=team&target_name=Ann%20Smith", nil)
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Empty(t, response)
+	})
+}
+
+func TestUpdateFeedback(t *testing.T) {
+	router := setupFeedbackTestRouter()
+
+	t.Run("should update content and rescore sentiment", func(t *testing.T) {
+		team := createFeedbackTestTeam(t, "Dev Team", "logo.png")
+		feedback := createFeedbackTestFeedback(t, "Excellent sprint demo", "team", team.ID, "Dev Team")
+
+		reqBody := models.UpdateFeedbackRequest{Content: "The demo was late and confusing"}
+		w := makeFeedbackRequest(t, router, "PUT", fmt.Sprintf("/api/v1/feedbacks/%d", feedback.ID), reqBody)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response models.Feedback
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "The demo was late and confusing", response.Content)
+		assert.Equal(t, "negative", response.Sentiment)
+		assert.Less(t, response.SentimentScore, 0.0)
+	})
+
+	t.Run("should return error for non-existent feedback", func(t *testing.T) {
+		reqBody := models.UpdateFeedbackRequest{Content: "Anything"}
+		w := makeFeedbackRequest(t, router, "PUT", "/api/v1/feedbacks/999", reqBody)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestFilterFeedbacksBySentiment(t *testing.T) {
+	router := setupFeedbackTestRouter()
+	team := createFeedbackTestTeam(t, "Dev Team", "logo.png")
+
+	for _, content := range []string{"Great collaboration, thank you", "Standups keep running late", "We moved the retro to Friday"} {
+		w := makeFeedbackRequest(t, router, "POST", "/api/v1/feedbacks", models.CreateFeedbackRequest{Content: content, TargetType: "team", TargetID: team.ID})
+		assert.Equal(t, http.StatusCreated, w.Code)
+	}
+
+	t.Run("should filter all feedbacks by sentiment", func(t *testing.T) {
+		w := makeFeedbackRequest(t, router, "GET", "/api/v1/feedbacks?sentiment=negative", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response []models.Feedback
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response, 1)
+		assert.Equal(t, "Standups keep running late", response[0].Content)
+	})
+
+	t.Run("should filter feedbacks by target and sentiment", func(t *testing.T) {
+		w := makeFeedbackRequest(t, router, "GET", fmt.Sprintf("/api/v1/feedbacks/by-target?target_type=team&target_id=%d&sentiment=neutral", team.ID), nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response []models.Feedback
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response, 1)
+		assert.Equal(t, "We moved the retro to Friday", response[0].Content)
+	})
+
+	t.Run("should return error for unknown sentiment", func(t *testing.T) {
+		w := makeFeedbackRequest(t, router, "GET", "/api/v1/feedbacks?sentiment=angry", nil)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
 func TestDeleteFeedback(t *testing.T) {
 	router := setupFeedbackTestRouter()
 
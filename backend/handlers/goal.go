@@ -0,0 +1,314 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func CreateGoal(c *gin.Context) {
+	var req models.CreateGoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	targetName, err := lookupTargetName(req.TargetType, req.TargetID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	keyResults := make([]models.KeyResult, 0, len(req.KeyResults))
+	for _, kr := range req.KeyResults {
+		keyResult := models.KeyResult{
+			Title:        kr.Title,
+			StartValue:   kr.StartValue,
+			TargetValue:  kr.TargetValue,
+			CurrentValue: kr.StartValue,
+			Unit:         kr.Unit,
+		}
+		keyResult.Progress = keyResultProgress(keyResult)
+		keyResults = append(keyResults, keyResult)
+	}
+
+	goal := models.Goal{
+		Title:       req.Title,
+		Description: req.Description,
+		TargetType:  req.TargetType,
+		TargetID:    req.TargetID,
+		TargetName:  targetName,
+		Status:      models.GoalStatusOnTrack,
+		Progress:    goalProgress(keyResults),
+		DueDate:     req.DueDate,
+		KeyResults:  keyResults,
+	}
+
+	if err := database.GetDB().Create(&goal).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create goal"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, goal)
+}
+
+func GetGoals(c *gin.Context) {
+	query := database.GetDB().Preload("KeyResults")
+	if targetType := c.Query("target_type"); targetType != "" {
+		query = query.Where("target_type = ?", targetType)
+	}
+	if value := c.Query("target_id"); value != "" {
+		targetID, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid target_id"})
+			return
+		}
+		query = query.Where("target_id = ?", targetID)
+	}
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var goals []models.Goal
+	if err := query.Order("created_at desc").Find(&goals).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch goals"})
+		return
+	}
+
+	c.JSON(http.StatusOK, goals)
+}
+
+func GetGoal(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid goal ID"})
+		return
+	}
+
+	var goal models.Goal
+	if err := database.GetDB().Preload("KeyResults").Preload("CheckIns", orderCheckIns).First(&goal, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Goal not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, goal)
+}
+
+func UpdateGoal(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid goal ID"})
+		return
+	}
+
+	var req models.UpdateGoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var goal models.Goal
+	if err := database.GetDB().First(&goal, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Goal not found"})
+		return
+	}
+
+	goal.Title = req.Title
+	goal.Description = req.Description
+	goal.Status = req.Status
+	goal.DueDate = req.DueDate
+
+	if err := database.GetDB().Save(&goal).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update goal"})
+		return
+	}
+
+	c.JSON(http.StatusOK, goal)
+}
+
+func DeleteGoal(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid goal ID"})
+		return
+	}
+
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("goal_id = ?", id).Delete(&models.GoalCheckIn{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("goal_id = ?", id).Delete(&models.KeyResult{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Goal{}, id).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete goal"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Goal deleted successfully"})
+}
+
+func CreateCheckIn(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid goal ID"})
+		return
+	}
+
+	var req models.CheckInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var goal models.Goal
+	if err := database.GetDB().Preload("KeyResults").First(&goal, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Goal not found"})
+		return
+	}
+
+	checkIn := models.GoalCheckIn{
+		GoalID:   goal.ID,
+		Note:     req.Note,
+		AuthorID: req.AuthorID,
+	}
+
+	var updated *models.KeyResult
+	if req.KeyResultID != nil {
+		for i := range goal.KeyResults {
+			if goal.KeyResults[i].ID == *req.KeyResultID {
+				updated = &goal.KeyResults[i]
+			}
+		}
+		if updated == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Key result not found"})
+			return
+		}
+		if req.Value == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "value is required when checking in on a key result"})
+			return
+		}
+		updated.CurrentValue = *req.Value
+		updated.Progress = keyResultProgress(*updated)
+		checkIn.KeyResultID = req.KeyResultID
+		checkIn.Value = req.Value
+		goal.Progress = goalProgress(goal.KeyResults)
+	} else if req.Progress != nil {
+		if len(goal.KeyResults) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Progress of goals with key results is derived from their key results"})
+			return
+		}
+		goal.Progress = *req.Progress
+	}
+
+	if req.Status != "" {
+		goal.Status = req.Status
+	}
+	checkIn.Progress = goal.Progress
+	checkIn.Status = goal.Status
+
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if updated != nil {
+			if err := tx.Save(updated).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&goal).Updates(map[string]interface{}{"progress": goal.Progress, "status": goal.Status}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&checkIn).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record check-in"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, checkIn)
+}
+
+func GetTargetOverview(c *gin.Context) {
+	targetType := c.Query("target_type")
+	targetIDStr := c.Query("target_id")
+
+	if targetType == "" || targetIDStr == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "target_type and target_id are required"})
+		return
+	}
+
+	if targetType != "person" && targetType != "team" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "target_type must be person or team"})
+		return
+	}
+
+	targetID, err := strconv.Atoi(targetIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid target_id"})
+		return
+	}
+
+	targetName, err := lookupTargetName(targetType, uint(targetID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	overview := models.TargetOverview{
+		TargetType: targetType,
+		TargetID:   uint(targetID),
+		TargetName: targetName,
+	}
+
+	if err := database.GetDB().Preload("KeyResults").Where("target_type = ? AND target_id = ?", targetType, targetID).
+		Order("created_at desc").Find(&overview.Goals).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch goals"})
+		return
+	}
+
+	if err := database.GetDB().Where("target_type = ? AND target_id = ?", targetType, targetID).
+		Order("created_at desc").Find(&overview.Feedbacks).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch feedbacks"})
+		return
+	}
+
+	c.JSON(http.StatusOK, overview)
+}
+
+func orderCheckIns(db *gorm.DB) *gorm.DB {
+	return db.Order("created_at desc, id desc")
+}
+
+func keyResultProgress(kr models.KeyResult) float64 {
+	span := kr.TargetValue - kr.StartValue
+	if span == 0 {
+		if kr.CurrentValue == kr.TargetValue {
+			return 100
+		}
+		return 0
+	}
+
+	progress := (kr.CurrentValue - kr.StartValue) / span * 100
+	if progress < 0 {
+		return 0
+	}
+	if progress > 100 {
+		return 100
+	}
+	return progress
+}
+
+func goalProgress(keyResults []models.KeyResult) float64 {
+	if len(keyResults) == 0 {
+		return 0
+	}
+
+	var total float64
+	for _, kr := range keyResults {
+		total += kr.Progress
+	}
+	return total / float64(len(keyResults))
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupGoalTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic("Failed to connect to test database")
+	}
+
+	err = database.Migrate(db)
+	if err != nil {
+		panic("Failed to migrate test database")
+	}
+
+	database.DB = db
+
+	r := gin.New()
+
+	api := r.Group("/api/v1")
+	goals := api.Group("/goals")
+	{
+		goals.POST("", CreateGoal)
+		goals.GET("", GetGoals)
+		goals.GET("/by-target", GetTargetOverview)
+		goals.GET("/:id", GetGoal)
+		goals.PUT("/:id", UpdateGoal)
+		goals.DELETE("/:id", DeleteGoal)
+		goals.POST("/:id/check-ins", CreateCheckIn)
+	}
+
+	return r
+}
+
+func TestCreateGoal(t *testing.T) {
+	router := setupGoalTestRouter()
+
+	t.Run("should create team goal with key results", func(t *testing.T) {
+		team := createTestTeam(t, "Search", "")
+
+		reqBody := models.CreateGoalRequest{
+			Title:      "Faster search",
+			TargetType: "team",
+			TargetID:   team.ID,
+			KeyResults: []models.KeyResultRequest{
+				{Title: "p95 latency", StartValue: 800, TargetValue: 200, Unit: "ms"},
+				{Title: "Relevance score", StartValue: 0, TargetValue: 10},
+			},
+		}
+
+		w := makeRequest(t, router, "POST", "/api/v1/goals", reqBody)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response models.Goal
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Search", response.TargetName)
+		assert.Equal(t, models.GoalStatusOnTrack, response.Status)
+		assert.Len(t, response.KeyResults, 2)
+		assert.Equal(t, 800.0, response.KeyResults[0].CurrentValue)
+		assert.Zero(t, response.Progress)
+	})
+
+	t.Run("should return error for non-existent person", func(t *testing.T) {
+		reqBody := models.CreateGoalRequest{
+			Title:      "Ghost goal",
+			TargetType: "person",
+			TargetID:   999,
+		}
+
+		w := makeRequest(t, router, "POST", "/api/v1/goals", reqBody)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("should return error for invalid target type", func(t *testing.T) {
+		reqBody := models.CreateGoalRequest{
+			Title:      "Bad goal",
+			TargetType: "department",
+			TargetID:   1,
+		}
+
+		w := makeRequest(t, router, "POST", "/api/v1/goals", reqBody)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestGoalCheckIns(t *testing.T) {
+	router := setupGoalTestRouter()
+	person := createTestPerson(t, "Grace", "goal.grace@example.com", "")
+
+	goal := models.Goal{
+		Title:      "Ship onboarding",
+		TargetType: "person",
+		TargetID:   person.ID,
+		TargetName: person.Name,
+		Status:     models.GoalStatusOnTrack,
+		KeyResults: []models.KeyResult{
+			{Title: "Screens done", StartValue: 0, TargetValue: 4},
+			{Title: "Bugs open", StartValue: 10, TargetValue: 0, CurrentValue: 10},
+		},
+	}
+	assert.NoError(t, database.GetDB().Create(&goal).Error)
+
+	t.Run("should update key result and goal progress", func(t *testing.T) {
+		value := 2.0
+		reqBody := models.CheckInRequest{KeyResultID: &goal.KeyResults[0].ID, Value: &value, Note: "Two screens merged"}
+		w := makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/goals/%d/check-ins", goal.ID), reqBody)
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		value = 5
+		reqBody = models.CheckInRequest{KeyResultID: &goal.KeyResults[1].ID, Value: &value, Status: models.GoalStatusAtRisk}
+		w = makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/goals/%d/check-ins", goal.ID), reqBody)
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		w = makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/goals/%d", goal.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response models.Goal
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 50.0, response.KeyResults[0].Progress)
+		assert.Equal(t, 50.0, response.KeyResults[1].Progress)
+		assert.Equal(t, 50.0, response.Progress)
+		assert.Equal(t, models.GoalStatusAtRisk, response.Status)
+		assert.Len(t, response.CheckIns, 2)
+		assert.Equal(t, models.GoalStatusAtRisk, response.CheckIns[0].Status)
+	})
+
+	t.Run("should reject manual progress on goals with key results", func(t *testing.T) {
+		progress := 90.0
+		reqBody := models.CheckInRequest{Progress: &progress}
+
+		w := makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/goals/%d/check-ins", goal.ID), reqBody)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should return error for unknown key result", func(t *testing.T) {
+		missing := uint(999)
+		value := 1.0
+		reqBody := models.CheckInRequest{KeyResultID: &missing, Value: &value}
+
+		w := makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/goals/%d/check-ins", goal.ID), reqBody)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestGetTargetOverview(t *testing.T) {
+	router := setupGoalTestRouter()
+	person := createTestPerson(t, "Linus", "goal.linus@example.com", "")
+	other := createTestPerson(t, "Other", "goal.other@example.com", "")
+
+	assert.NoError(t, database.GetDB().Create(&models.Goal{Title: "Mentor a junior", TargetType: "person", TargetID: person.ID, TargetName: person.Name, Status: models.GoalStatusOnTrack, Progress: 40}).Error)
+	assert.NoError(t, database.GetDB().Create(&models.Goal{Title: "Unrelated", TargetType: "person", TargetID: other.ID, TargetName: other.Name, Status: models.GoalStatusOnTrack}).Error)
+	assert.NoError(t, database.GetDB().Create(&models.Feedback{Content: "Great mentoring", TargetType: "person", TargetID: person.ID, TargetName: person.Name}).Error)
+
+	t.Run("should return goals alongside feedback", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/goals/by-target?target_type=person&target_id=%d", person.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response models.TargetOverview
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Linus", response.TargetName)
+		assert.Len(t, response.Goals, 1)
+		assert.Equal(t, 40.0, response.Goals[0].Progress)
+		assert.Len(t, response.Feedbacks, 1)
+		assert.Equal(t, "Great mentoring", response.Feedbacks[0].Content)
+	})
+
+	t.Run("should return error for missing parameters", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", "/api/v1/goals/by-target?target_type=person", nil)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should return error for non-existent target", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", "/api/v1/goals/by-target?target_type=team&target_id=999", nil)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
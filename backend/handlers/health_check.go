@@ -0,0 +1,549 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var errAlreadyVoted = errors.New("You have already voted in this round")
+
+var healthColorScores = map[string]float64{
+	models.HealthGreen:  3,
+	models.HealthYellow: 2,
+	models.HealthRed:    1,
+}
+
+func CreateHealthSurvey(c *gin.Context) {
+	var req models.CreateHealthSurveyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	survey := models.HealthSurvey{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	for i, d := range req.Dimensions {
+		survey.Dimensions = append(survey.Dimensions, models.HealthDimension{
+			Position:   i + 1,
+			Name:       d.Name,
+			GreenLabel: d.GreenLabel,
+			RedLabel:   d.RedLabel,
+		})
+	}
+
+	if err := database.GetDB().Create(&survey).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create health survey"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, survey)
+}
+
+func GetHealthSurveys(c *gin.Context) {
+	var surveys []models.HealthSurvey
+	if err := database.GetDB().Preload("Dimensions", orderDimensions).Order("name").Find(&surveys).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch health surveys"})
+		return
+	}
+
+	c.JSON(http.StatusOK, surveys)
+}
+
+func GetHealthSurvey(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid health survey ID"})
+		return
+	}
+
+	var survey models.HealthSurvey
+	if err := database.GetDB().Preload("Dimensions", orderDimensions).First(&survey, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Health survey not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, survey)
+}
+
+func DeleteHealthSurvey(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid health survey ID"})
+		return
+	}
+
+	var rounds int64
+	if err := database.GetDB().Model(&models.HealthRound{}).Where("survey_id = ?", id).Count(&rounds).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete health survey"})
+		return
+	}
+	if rounds > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Health survey has rounds and cannot be deleted"})
+		return
+	}
+
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("survey_id = ?", id).Delete(&models.HealthDimension{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.HealthSurvey{}, id).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete health survey"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Health survey deleted successfully"})
+}
+
+func OpenHealthRound(c *gin.Context) {
+	var req models.OpenHealthRoundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.ClosesAt != nil && !req.ClosesAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "closes_at must be in the future"})
+		return
+	}
+
+	var survey models.HealthSurvey
+	if err := database.GetDB().First(&survey, req.SurveyID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Health survey not found"})
+		return
+	}
+
+	var team models.Team
+	if err := database.GetDB().First(&team, req.TeamID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+		return
+	}
+
+	if err := closeExpiredHealthRounds(database.GetDB()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open health round"})
+		return
+	}
+
+	var open int64
+	if err := database.GetDB().Model(&models.HealthRound{}).
+		Where("survey_id = ? AND team_id = ? AND status = ?", survey.ID, team.ID, models.HealthRoundOpen).
+		Count(&open).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open health round"})
+		return
+	}
+	if open > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Team already has an open round for this survey"})
+		return
+	}
+
+	round := models.HealthRound{
+		SurveyID: survey.ID,
+		TeamID:   team.ID,
+		Status:   models.HealthRoundOpen,
+		ClosesAt: req.ClosesAt,
+	}
+
+	if err := database.GetDB().Create(&round).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open health round"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, round)
+}
+
+func GetHealthRounds(c *gin.Context) {
+	if err := closeExpiredHealthRounds(database.GetDB()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch health rounds"})
+		return
+	}
+
+	query := database.GetDB().Order("created_at desc")
+	if value := c.Query("team_id"); value != "" {
+		teamID, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team_id"})
+			return
+		}
+		query = query.Where("team_id = ?", teamID)
+	}
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var rounds []models.HealthRound
+	if err := query.Find(&rounds).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch health rounds"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rounds)
+}
+
+func GetHealthRound(c *gin.Context) {
+	round, ok := loadHealthRound(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, round)
+}
+
+func CloseHealthRound(c *gin.Context) {
+	round, ok := loadHealthRound(c)
+	if !ok {
+		return
+	}
+
+	if round.Status == models.HealthRoundClosed {
+		c.JSON(http.StatusConflict, gin.H{"error": "Health round is already closed"})
+		return
+	}
+
+	now := time.Now()
+	round.Status = models.HealthRoundClosed
+	round.ClosedAt = &now
+	if err := database.GetDB().Save(&round).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to close health round"})
+		return
+	}
+
+	c.JSON(http.StatusOK, round)
+}
+
+func SubmitHealthVotes(c *gin.Context) {
+	round, ok := loadHealthRound(c)
+	if !ok {
+		return
+	}
+
+	var req models.SubmitHealthVotesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if round.Status != models.HealthRoundOpen {
+		c.JSON(http.StatusConflict, gin.H{"error": "Health round is closed"})
+		return
+	}
+
+	var person models.Person
+	if err := database.GetDB().First(&person, req.PersonID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Person not found"})
+		return
+	}
+	if person.TeamID == nil || *person.TeamID != round.TeamID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only members of the team can vote in this round"})
+		return
+	}
+
+	var dimensions []models.HealthDimension
+	if err := database.GetDB().Where("survey_id = ?", round.SurveyID).Find(&dimensions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record votes"})
+		return
+	}
+	known := make(map[uint]bool, len(dimensions))
+	for _, d := range dimensions {
+		known[d.ID] = true
+	}
+
+	votes := make([]models.HealthVote, 0, len(req.Votes))
+	seen := make(map[uint]bool, len(req.Votes))
+	for _, v := range req.Votes {
+		if !known[v.DimensionID] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Dimension " + strconv.Itoa(int(v.DimensionID)) + " is not part of this survey"})
+			return
+		}
+		if seen[v.DimensionID] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Dimension " + strconv.Itoa(int(v.DimensionID)) + " was voted more than once"})
+			return
+		}
+		seen[v.DimensionID] = true
+		votes = append(votes, models.HealthVote{
+			RoundID:     round.ID,
+			DimensionID: v.DimensionID,
+			Color:       v.Color,
+			Trend:       v.Trend,
+			Comment:     v.Comment,
+		})
+	}
+
+	err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+		var voted int64
+		if err := tx.Model(&models.HealthParticipation{}).
+			Where("round_id = ? AND person_id = ?", round.ID, person.ID).Count(&voted).Error; err != nil {
+			return err
+		}
+		if voted > 0 {
+			return errAlreadyVoted
+		}
+		if err := tx.Create(&models.HealthParticipation{RoundID: round.ID, PersonID: person.ID}).Error; err != nil {
+			return errAlreadyVoted
+		}
+		return tx.Create(&votes).Error
+	})
+	if errors.Is(err, errAlreadyVoted) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record votes"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Votes recorded successfully"})
+}
+
+func GetHealthRoundResults(c *gin.Context) {
+	round, ok := loadHealthRound(c)
+	if !ok {
+		return
+	}
+
+	var dimensions []models.HealthDimension
+	if err := database.GetDB().Where("survey_id = ?", round.SurveyID).Order("position").Find(&dimensions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch health results"})
+		return
+	}
+
+	var participants, teamSize int64
+	if err := database.GetDB().Model(&models.HealthParticipation{}).Where("round_id = ?", round.ID).Count(&participants).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch health results"})
+		return
+	}
+	if err := database.GetDB().Model(&models.Person{}).Where("team_id = ?", round.TeamID).Count(&teamSize).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch health results"})
+		return
+	}
+
+	visible := healthResultsVisible(round, participants)
+	var votes []models.HealthVote
+	if visible {
+		if err := database.GetDB().Where("round_id = ?", round.ID).Find(&votes).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch health results"})
+			return
+		}
+	}
+
+	results := models.HealthRoundResults{
+		Round:        round,
+		Participants: int(participants),
+		TeamSize:     int(teamSize),
+		Hidden:       !visible,
+		Dimensions:   summarizeHealthVotes(dimensions, votes),
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+func GetTeamHealth(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	var team models.Team
+	if err := database.GetDB().First(&team, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+		return
+	}
+
+	if err := closeExpiredHealthRounds(database.GetDB()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch team health"})
+		return
+	}
+
+	trends := models.TeamHealthTrends{TeamID: team.ID, Dimensions: []models.HealthDimensionTrend{}}
+
+	if value := c.Query("survey_id"); value != "" {
+		surveyID, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid survey_id"})
+			return
+		}
+		trends.SurveyID = uint(surveyID)
+	} else {
+		var latest models.HealthRound
+		err := database.GetDB().Where("team_id = ?", team.ID).Order("created_at desc, id desc").First(&latest).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusOK, trends)
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch team health"})
+			return
+		}
+		trends.SurveyID = latest.SurveyID
+	}
+
+	var dimensions []models.HealthDimension
+	if err := database.GetDB().Where("survey_id = ?", trends.SurveyID).Order("position").Find(&dimensions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch team health"})
+		return
+	}
+
+	var rounds []models.HealthRound
+	if err := database.GetDB().Where("team_id = ? AND survey_id = ?", team.ID, trends.SurveyID).
+		Order("created_at, id").Find(&rounds).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch team health"})
+		return
+	}
+
+	for _, d := range dimensions {
+		trends.Dimensions = append(trends.Dimensions, models.HealthDimensionTrend{
+			DimensionID: d.ID,
+			Name:        d.Name,
+			Points:      []models.HealthTrendPoint{},
+		})
+	}
+
+	for _, round := range rounds {
+		var participants int64
+		if err := database.GetDB().Model(&models.HealthParticipation{}).Where("round_id = ?", round.ID).Count(&participants).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch team health"})
+			return
+		}
+
+		var votes []models.HealthVote
+		if healthResultsVisible(round, participants) {
+			if err := database.GetDB().Where("round_id = ?", round.ID).Find(&votes).Error; err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch team health"})
+				return
+			}
+		}
+
+		for i, result := range summarizeHealthVotes(dimensions, votes) {
+			trends.Dimensions[i].Points = append(trends.Dimensions[i].Points, models.HealthTrendPoint{
+				RoundID:   round.ID,
+				Status:    round.Status,
+				CreatedAt: round.CreatedAt,
+				Votes:     result.Green + result.Yellow + result.Red,
+				Score:     result.Score,
+				Color:     result.Color,
+			})
+		}
+	}
+	trends.Rounds = len(rounds)
+
+	c.JSON(http.StatusOK, trends)
+}
+
+func loadHealthRound(c *gin.Context) (models.HealthRound, bool) {
+	var round models.HealthRound
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid health round ID"})
+		return round, false
+	}
+
+	if err := closeExpiredHealthRounds(database.GetDB()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch health round"})
+		return round, false
+	}
+
+	if err := database.GetDB().First(&round, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Health round not found"})
+		return round, false
+	}
+
+	return round, true
+}
+
+// healthResultsVisible keeps votes hidden while a round is open, so comparing
+// results before and after someone votes cannot reveal their votes.
+func healthResultsVisible(round models.HealthRound, participants int64) bool {
+	return round.Status == models.HealthRoundClosed && participants >= models.MinHealthResponses
+}
+
+func orderDimensions(db *gorm.DB) *gorm.DB {
+	return db.Order("position")
+}
+
+func closeExpiredHealthRounds(db *gorm.DB) error {
+	now := time.Now()
+	return db.Model(&models.HealthRound{}).
+		Where("status = ? AND closes_at IS NOT NULL AND closes_at < ?", models.HealthRoundOpen, now).
+		Updates(map[string]interface{}{"status": models.HealthRoundClosed, "closed_at": now}).Error
+}
+
+func summarizeHealthVotes(dimensions []models.HealthDimension, votes []models.HealthVote) []models.HealthDimensionResult {
+	results := make([]models.HealthDimensionResult, 0, len(dimensions))
+	index := make(map[uint]int, len(dimensions))
+	trends := make([]map[string]int, len(dimensions))
+	for i, d := range dimensions {
+		index[d.ID] = i
+		trends[i] = make(map[string]int)
+		results = append(results, models.HealthDimensionResult{DimensionID: d.ID, Name: d.Name})
+	}
+
+	for _, v := range votes {
+		i, ok := index[v.DimensionID]
+		if !ok {
+			continue
+		}
+		switch v.Color {
+		case models.HealthGreen:
+			results[i].Green++
+		case models.HealthYellow:
+			results[i].Yellow++
+		case models.HealthRed:
+			results[i].Red++
+		}
+		if v.Trend != "" {
+			trends[i][v.Trend]++
+		}
+		if v.Comment != "" {
+			results[i].Comments = append(results[i].Comments, v.Comment)
+		}
+	}
+
+	for i := range results {
+		r := &results[i]
+		total := r.Green + r.Yellow + r.Red
+		if total == 0 {
+			continue
+		}
+		score := (float64(r.Green)*healthColorScores[models.HealthGreen] +
+			float64(r.Yellow)*healthColorScores[models.HealthYellow] +
+			float64(r.Red)*healthColorScores[models.HealthRed]) / float64(total)
+		r.Score = &score
+		r.Color = healthScoreColor(score)
+		r.Trend = dominantHealthTrend(trends[i])
+		sort.Strings(r.Comments)
+	}
+
+	return results
+}
+
+func healthScoreColor(score float64) string {
+	switch {
+	case score >= 2.5:
+		return models.HealthGreen
+	case score >= 1.5:
+		return models.HealthYellow
+	default:
+		return models.HealthRed
+	}
+}
+
+func dominantHealthTrend(counts map[string]int) string {
+	best, bestCount := "", 0
+	for _, trend := range []string{models.HealthTrendUp, models.HealthTrendStable, models.HealthTrendDown} {
+		if counts[trend] > bestCount {
+			best, bestCount = trend, counts[trend]
+		}
+	}
+	return best
+}
@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupHealthCheckTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic("Failed to connect to test database")
+	}
+
+	err = database.Migrate(db)
+	if err != nil {
+		panic("Failed to migrate test database")
+	}
+
+	database.DB = db
+
+	r := gin.New()
+
+	api := r.Group("/api/v1")
+	healthSurveys := api.Group("/health-surveys")
+	{
+		healthSurveys.POST("", CreateHealthSurvey)
+		healthSurveys.GET("/:id", GetHealthSurvey)
+		healthSurveys.DELETE("/:id", DeleteHealthSurvey)
+	}
+	healthRounds := api.Group("/health-rounds")
+	{
+		healthRounds.POST("", OpenHealthRound)
+		healthRounds.POST("/:id/close", CloseHealthRound)
+		healthRounds.POST("/:id/votes", SubmitHealthVotes)
+		healthRounds.GET("/:id/results", GetHealthRoundResults)
+	}
+	api.GET("/teams/:id/health", GetTeamHealth)
+
+	return r
+}
+
+func TestOpenHealthRound(t *testing.T) {
+	router := setupHealthCheckTestRouter()
+	team := createTestTeam(t, "Payments", "")
+	survey := createHealthTestSurvey(t, router, "Health 1")
+
+	w := makeRequest(t, router, "POST", "/api/v1/health-rounds", models.OpenHealthRoundRequest{SurveyID: survey.ID, TeamID: team.ID})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	t.Run("should reject a second open round", func(t *testing.T) {
+		w := makeRequest(t, router, "POST", "/api/v1/health-rounds", models.OpenHealthRoundRequest{SurveyID: survey.ID, TeamID: team.ID})
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("should not delete a survey with rounds", func(t *testing.T) {
+		w := makeRequest(t, router, "DELETE", fmt.Sprintf("/api/v1/health-surveys/%d", survey.ID), nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestSubmitHealthVotes(t *testing.T) {
+	router := setupHealthCheckTestRouter()
+	team := createTestTeam(t, "Search", "")
+	member := createHealthTestMember(t, "Member", "health.member2@example.com", team.ID)
+	outsider := createTestPerson(t, "Outsider", "health.outsider2@example.com", "")
+	survey := createHealthTestSurvey(t, router, "Health 2")
+	round := openHealthTestRound(t, router, survey.ID, team.ID)
+	dimension := survey.Dimensions[0].ID
+
+	votes := func(personID uint) models.SubmitHealthVotesRequest {
+		return models.SubmitHealthVotesRequest{
+			PersonID: personID,
+			Votes:    []models.HealthVoteRequest{{DimensionID: dimension, Color: models.HealthGreen}},
+		}
+	}
+
+	t.Run("should accept votes from a team member", func(t *testing.T) {
+		w := makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/health-rounds/%d/votes", round.ID), votes(member.ID))
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("should reject a second ballot", func(t *testing.T) {
+		w := makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/health-rounds/%d/votes", round.ID), votes(member.ID))
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("should reject people outside the team", func(t *testing.T) {
+		w := makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/health-rounds/%d/votes", round.ID), votes(outsider.ID))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("should reject dimensions from another survey", func(t *testing.T) {
+		other := createHealthTestMember(t, "Other", "health.other2@example.com", team.ID)
+		req := votes(other.ID)
+		req.Votes[0].DimensionID = 999
+
+		w := makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/health-rounds/%d/votes", round.ID), req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should reject votes after close", func(t *testing.T) {
+		w := makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/health-rounds/%d/close", round.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		late := createHealthTestMember(t, "Late", "health.late2@example.com", team.ID)
+		w = makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/health-rounds/%d/votes", round.ID), votes(late.ID))
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestHealthRoundResults(t *testing.T) {
+	router := setupHealthCheckTestRouter()
+	team := createTestTeam(t, "Growth", "")
+	survey := createHealthTestSurvey(t, router, "Health 3")
+	round := openHealthTestRound(t, router, survey.ID, team.ID)
+	dimension := survey.Dimensions[0].ID
+
+	colors := []string{models.HealthGreen, models.HealthGreen, models.HealthRed}
+	for i, color := range colors {
+		member := createHealthTestMember(t, fmt.Sprintf("Member %d", i), fmt.Sprintf("health.member3.%d@example.com", i), team.ID)
+		req := models.SubmitHealthVotesRequest{
+			PersonID: member.ID,
+			Votes:    []models.HealthVoteRequest{{DimensionID: dimension, Color: color, Comment: "ok"}},
+		}
+
+		if i == len(colors)-1 {
+			w := makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/health-rounds/%d/results", round.ID), nil)
+			var results models.HealthRoundResults
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &results))
+			assert.True(t, results.Hidden)
+			assert.Equal(t, 0, results.Dimensions[0].Green)
+		}
+
+		w := makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/health-rounds/%d/votes", round.ID), req)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	}
+
+	w := makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/health-rounds/%d/results", round.ID), nil)
+	var results models.HealthRoundResults
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &results))
+	assert.True(t, results.Hidden)
+	assert.Equal(t, 3, results.Participants)
+	assert.Equal(t, 0, results.Dimensions[0].Green)
+	assert.Empty(t, results.Dimensions[0].Comments)
+
+	w = makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/health-rounds/%d/close", round.ID), nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/health-rounds/%d/results", round.ID), nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	results = models.HealthRoundResults{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &results))
+	assert.False(t, results.Hidden)
+	assert.Equal(t, 3, results.Participants)
+	assert.Equal(t, 3, results.TeamSize)
+	assert.Equal(t, 2, results.Dimensions[0].Green)
+	assert.Equal(t, 1, results.Dimensions[0].Red)
+	assert.InDelta(t, 7.0/3.0, *results.Dimensions[0].Score, 0.001)
+	assert.Equal(t, models.HealthYellow, results.Dimensions[0].Color)
+	assert.Nil(t, results.Dimensions[1].Score)
+}
+
+func TestGetTeamHealth(t *testing.T) {
+	router := setupHealthCheckTestRouter()
+	team := createTestTeam(t, "Mobile", "")
+	survey := createHealthTestSurvey(t, router, "Health 4")
+	dimension := survey.Dimensions[0].ID
+
+	var members []models.Person
+	for i := 0; i < 3; i++ {
+		members = append(members, createHealthTestMember(t, fmt.Sprintf("Member %d", i), fmt.Sprintf("health.member4.%d@example.com", i), team.ID))
+	}
+
+	for i, color := range []string{models.HealthRed, models.HealthGreen, models.HealthYellow} {
+		round := openHealthTestRound(t, router, survey.ID, team.ID)
+		for _, m := range members {
+			req := models.SubmitHealthVotesRequest{
+				PersonID: m.ID,
+				Votes:    []models.HealthVoteRequest{{DimensionID: dimension, Color: color}},
+			}
+			w := makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/health-rounds/%d/votes", round.ID), req)
+			assert.Equal(t, http.StatusCreated, w.Code)
+		}
+		if i < 2 {
+			w := makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/health-rounds/%d/close", round.ID), nil)
+			assert.Equal(t, http.StatusOK, w.Code)
+		}
+	}
+
+	w := makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/teams/%d/health", team.ID), nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var trends models.TeamHealthTrends
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &trends))
+	assert.Equal(t, survey.ID, trends.SurveyID)
+	assert.Equal(t, 3, trends.Rounds)
+	points := trends.Dimensions[0].Points
+	assert.Len(t, points, 3)
+	assert.Equal(t, models.HealthRed, points[0].Color)
+	assert.Equal(t, models.HealthGreen, points[1].Color)
+	assert.Equal(t, 3, points[1].Votes)
+	assert.Equal(t, models.HealthRoundOpen, points[2].Status)
+	assert.Equal(t, 0, points[2].Votes)
+	assert.Nil(t, points[2].Score)
+}
+
+func createHealthTestSurvey(t *testing.T, router *gin.Engine, name string) models.HealthSurvey {
+	reqBody := models.CreateHealthSurveyRequest{
+		Name: name,
+		Dimensions: []models.HealthDimensionRequest{
+			{Name: "Speed"},
+			{Name: "Fun"},
+		},
+	}
+
+	w := makeRequest(t, router, "POST", "/api/v1/health-surveys", reqBody)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var survey models.HealthSurvey
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &survey))
+	return survey
+}
+
+func openHealthTestRound(t *testing.T, router *gin.Engine, surveyID, teamID uint) models.HealthRound {
+	w := makeRequest(t, router, "POST", "/api/v1/health-rounds", models.OpenHealthRoundRequest{SurveyID: surveyID, TeamID: teamID})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var round models.HealthRound
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &round))
+	return round
+}
+
+func createHealthTestMember(t *testing.T, name, email string, teamID uint) models.Person {
+	person := createTestPerson(t, name, email, "")
+	assert.NoError(t, database.GetDB().Model(&person).Update("team_id", teamID).Error)
+	person.TeamID = &teamID
+	return person
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"coaching-backend/database"
+	"coaching-backend/importer"
+	"github.com/gin-gonic/gin"
+)
+
+const maxImportBytes = 10 << 20
+
+func ImportPersons(c *gin.Context) {
+	format, opts, ok := importRequest(c)
+	if !ok {
+		return
+	}
+
+	rows, err := importer.ParsePersons(c.Request.Body, format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := importer.ImportPersons(database.GetDB(), rows, opts)
+	respondImport(c, result, err)
+}
+
+func ImportTeams(c *gin.Context) {
+	format, opts, ok := importRequest(c)
+	if !ok {
+		return
+	}
+
+	rows, err := importer.ParseTeams(c.Request.Body, format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := importer.ImportTeams(database.GetDB(), rows, opts)
+	respondImport(c, result, err)
+}
+
+func importRequest(c *gin.Context) (string, importer.Options, bool) {
+	var opts importer.Options
+	opts.DryRun, _ = strconv.ParseBool(c.Query("dry_run"))
+	opts.Atomic, _ = strconv.ParseBool(c.Query("atomic"))
+
+	format := c.Query("format")
+	if format == "" {
+		mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+		switch mediaType {
+		case "text/csv":
+			format = importer.FormatCSV
+		case "application/json":
+			format = importer.FormatJSON
+		}
+	}
+	if format != importer.FormatCSV && format != importer.FormatJSON {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Send text/csv or application/json, or set format to csv or json"})
+		return "", opts, false
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes)
+	return format, opts, true
+}
+
+func respondImport(c *gin.Context, result importer.Result, err error) {
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import rows"})
+		return
+	}
+	if result.Atomic && !result.DryRun && !result.Committed {
+		c.JSON(http.StatusUnprocessableEntity, result)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"coaching-backend/database"
+	"coaching-backend/importer"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupImportTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic("Failed to connect to test database")
+	}
+
+	err = database.Migrate(db)
+	if err != nil {
+		panic("Failed to migrate test database")
+	}
+
+	database.DB = db
+
+	r := gin.New()
+
+	api := r.Group("/api/v1")
+	api.POST("/import/persons", ImportPersons)
+	api.POST("/import/teams", ImportTeams)
+
+	return r
+}
+
+func makeImportRequest(router *gin.Engine, url, contentType, body string) (*httptest.ResponseRecorder, importer.Result) {
+	req, _ := http.NewRequest("POST", url, strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var result importer.Result
+	json.Unmarshal(w.Body.Bytes(), &result)
+	return w, result
+}
+
+func TestImportPersons(t *testing.T) {
+	router := setupImportTestRouter()
+	csv := "name,email,team\nAnn,ann@example.com,Core\nBob,bob@example.com,Core\nBad,nope,\n"
+
+	t.Run("should validate without writing in dry-run mode", func(t *testing.T) {
+		w, result := makeImportRequest(router, "/api/v1/import/persons?dry_run=true", "text/csv", csv)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 2, result.Created)
+		assert.Equal(t, 1, result.Failed)
+		assert.False(t, result.Committed)
+
+		var persons int64
+		database.GetDB().Model(&models.Person{}).Count(&persons)
+		assert.Zero(t, persons)
+	})
+
+	t.Run("should reject the whole file in atomic mode", func(t *testing.T) {
+		w, result := makeImportRequest(router, "/api/v1/import/persons?atomic=true", "text/csv; charset=utf-8", csv)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Equal(t, 3, result.Errors[0].Row)
+	})
+
+	t.Run("should import valid rows", func(t *testing.T) {
+		w, result := makeImportRequest(router, "/api/v1/import/persons", "text/csv", csv)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, result.Committed)
+		assert.Equal(t, 2, result.Created)
+		assert.Equal(t, 1, result.TeamsCreated)
+	})
+
+	t.Run("should upsert JSON rows by email", func(t *testing.T) {
+		w, result := makeImportRequest(router, "/api/v1/import/persons", "application/json", `[{"name":"Ann Smith","email":"ann@example.com"}]`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 1, result.Updated)
+
+		var ann models.Person
+		database.GetDB().Where("email = ?", "ann@example.com").First(&ann)
+		assert.Equal(t, "Ann Smith", ann.Name)
+		assert.NotNil(t, ann.TeamID)
+	})
+
+	t.Run("should reject unreadable files", func(t *testing.T) {
+		w, _ := makeImportRequest(router, "/api/v1/import/persons", "text/plain", csv)
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+
+		w, _ = makeImportRequest(router, "/api/v1/import/persons?format=json", "text/plain", "not json")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestImportTeams(t *testing.T) {
+	router := setupImportTestRouter()
+
+	w, result := makeImportRequest(router, "/api/v1/import/teams", "application/json", `[{"name":"Core","logo":"core.png"},{"name":"Design"}]`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 2, result.Created)
+
+	var teams int64
+	database.GetDB().Model(&models.Team{}).Count(&teams)
+	assert.Equal(t, int64(2), teams)
+}
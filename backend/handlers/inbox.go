@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+)
+
+func GetNotifications(c *gin.Context) {
+	person, ok := loadPerson(c)
+	if !ok {
+		return
+	}
+
+	query := database.GetDB().Where("person_id = ?", person.ID)
+	if unread, _ := strconv.ParseBool(c.Query("unread")); unread {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var notifications []models.Notification
+	if err := query.Order("created_at desc, id desc").Find(&notifications).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, notifications)
+}
+
+func GetUnreadNotificationCount(c *gin.Context) {
+	person, ok := loadPerson(c)
+	if !ok {
+		return
+	}
+
+	var unread int64
+	if err := database.GetDB().Model(&models.Notification{}).
+		Where("person_id = ? AND read_at IS NULL", person.ID).Count(&unread).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"unread": unread})
+}
+
+func MarkNotificationsRead(c *gin.Context) {
+	person, ok := loadPerson(c)
+	if !ok {
+		return
+	}
+
+	var req models.MarkNotificationsReadRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	// Without ids every unread notification of the person is marked as read.
+	query := database.GetDB().Model(&models.Notification{}).Where("person_id = ? AND read_at IS NULL", person.ID)
+	if len(req.IDs) > 0 {
+		query = query.Where("id IN ?", req.IDs)
+	}
+	result := query.Update("read_at", time.Now())
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark notifications as read"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"updated": result.RowsAffected})
+}
+
+func DeleteNotification(c *gin.Context) {
+	person, ok := loadPerson(c)
+	if !ok {
+		return
+	}
+
+	notificationID, err := strconv.Atoi(c.Param("notificationId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+
+	result := database.GetDB().Where("id = ? AND person_id = ?", notificationID, person.ID).Delete(&models.Notification{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete notification"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification deleted successfully"})
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupInboxTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic("Failed to connect to test database")
+	}
+
+	err = database.Migrate(db)
+	if err != nil {
+		panic("Failed to migrate test database")
+	}
+
+	database.DB = db
+
+	r := gin.New()
+
+	api := r.Group("/api/v1")
+	api.POST("/feedbacks", CreateFeedback)
+	api.DELETE("/feedbacks/:id", DeleteFeedback)
+	api.POST("/feedbacks/:id/acknowledge", AcknowledgeFeedback)
+	api.POST("/assign", AssignToTeam)
+	api.POST("/persons/:id/remove-from-team", RemoveFromTeam)
+	api.GET("/persons/:id/notifications", GetNotifications)
+	api.GET("/persons/:id/notifications/unread-count", GetUnreadNotificationCount)
+	api.POST("/persons/:id/notifications/read", MarkNotificationsRead)
+	api.DELETE("/persons/:id/notifications/:notificationId", DeleteNotification)
+
+	return r
+}
+
+func getNotifications(t *testing.T, router *gin.Engine, personID uint, query string) []models.Notification {
+	w := makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/persons/%d/notifications%s", personID, query), nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var notifications []models.Notification
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &notifications))
+	return notifications
+}
+
+func getUnreadCount(t *testing.T, router *gin.Engine, personID uint) int {
+	w := makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/persons/%d/notifications/unread-count", personID), nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]int
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response["unread"]
+}
+
+func TestInboxNotifications(t *testing.T) {
+	router := setupInboxTestRouter()
+	team := createTestTeam(t, "Inbox Team", "")
+	author := createTestPerson(t, "Inbox Author", "inbox.author@example.com", "")
+	person := createTestPerson(t, "Inbox Person", "inbox.person@example.com", "")
+
+	t.Run("should notify about team membership changes", func(t *testing.T) {
+		w := makeRequest(t, router, "POST", "/api/v1/assign", models.AssignToTeamRequest{PersonID: person.ID, TeamID: team.ID})
+		assert.Equal(t, http.StatusOK, w.Code)
+		w = makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/persons/%d/remove-from-team", person.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		notifications := getNotifications(t, router, person.ID, "")
+		assert.Len(t, notifications, 2)
+		assert.ElementsMatch(t, []string{models.NotificationAddedToTeam, models.NotificationRemovedFromTeam},
+			[]string{notifications[0].Type, notifications[1].Type})
+		assert.Equal(t, team.ID, *notifications[0].TeamID)
+	})
+
+	t.Run("should notify about received feedback", func(t *testing.T) {
+		w := makeRequest(t, router, "POST", "/api/v1/feedbacks", models.CreateFeedbackRequest{
+			Content: "Thorough review", TargetType: "person", TargetID: person.ID, AuthorID: &author.ID,
+		})
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		notifications := getNotifications(t, router, person.ID, "?unread=true")
+		assert.Len(t, notifications, 3)
+		assert.Equal(t, 3, getUnreadCount(t, router, person.ID))
+		assert.Empty(t, getNotifications(t, router, author.ID, ""))
+	})
+
+	t.Run("should mark selected notifications as read", func(t *testing.T) {
+		notifications := getNotifications(t, router, person.ID, "")
+		url := fmt.Sprintf("/api/v1/persons/%d/notifications/read", person.ID)
+
+		w := makeRequest(t, router, "POST", url, models.MarkNotificationsReadRequest{IDs: []uint{notifications[0].ID}})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 2, getUnreadCount(t, router, person.ID))
+		assert.Len(t, getNotifications(t, router, person.ID, "?unread=true"), 2)
+
+		w = makeRequest(t, router, "POST", url, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 0, getUnreadCount(t, router, person.ID))
+
+		var response map[string]int
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, 2, response["updated"])
+	})
+
+	t.Run("should not touch another person's notifications", func(t *testing.T) {
+		notifications := getNotifications(t, router, person.ID, "")
+
+		w := makeRequest(t, router, "DELETE", fmt.Sprintf("/api/v1/persons/%d/notifications/%d", author.ID, notifications[0].ID), nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = makeRequest(t, router, "DELETE", fmt.Sprintf("/api/v1/persons/%d/notifications/%d", person.ID, notifications[0].ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Len(t, getNotifications(t, router, person.ID, ""), 2)
+	})
+
+	t.Run("should return error for non-existent person", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", "/api/v1/persons/999/notifications", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestAcknowledgeFeedback(t *testing.T) {
+	router := setupInboxTestRouter()
+	author := createTestPerson(t, "Ack Author", "ack.author@example.com", "")
+	person := createTestPerson(t, "Ack Person", "ack.person@example.com", "")
+
+	w := makeRequest(t, router, "POST", "/api/v1/feedbacks", models.CreateFeedbackRequest{
+		Content: "Clear write-up", TargetType: "person", TargetID: person.ID, AuthorID: &author.ID,
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var feedback models.Feedback
+	json.Unmarshal(w.Body.Bytes(), &feedback)
+	url := fmt.Sprintf("/api/v1/feedbacks/%d/acknowledge", feedback.ID)
+
+	t.Run("should notify the author", func(t *testing.T) {
+		w := makeRequest(t, router, "POST", url, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var acknowledged models.Feedback
+		json.Unmarshal(w.Body.Bytes(), &acknowledged)
+		assert.NotNil(t, acknowledged.AcknowledgedAt)
+
+		notifications := getNotifications(t, router, author.ID, "")
+		assert.Len(t, notifications, 1)
+		assert.Equal(t, models.NotificationFeedbackAcknowledged, notifications[0].Type)
+		assert.Equal(t, feedback.ID, *notifications[0].FeedbackID)
+	})
+
+	t.Run("should reject a second acknowledgement", func(t *testing.T) {
+		w := makeRequest(t, router, "POST", url, nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("should remove notifications with the feedback", func(t *testing.T) {
+		w := makeRequest(t, router, "DELETE", fmt.Sprintf("/api/v1/feedbacks/%d", feedback.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		assert.Empty(t, getNotifications(t, router, author.ID, ""))
+		assert.Empty(t, getNotifications(t, router, person.ID, ""))
+	})
+
+	t.Run("should return error for non-existent feedback", func(t *testing.T) {
+		w := makeRequest(t, router, "POST", "/api/v1/feedbacks/999/acknowledge", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
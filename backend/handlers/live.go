@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"coaching-backend/realtime"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	liveBuffer       = 64
+	liveMaxMessage   = 4096
+	liveWriteTimeout = 10 * time.Second
+)
+
+var (
+	liveIdleTimeout = 90 * time.Second
+	liveTicketTTL   = time.Minute
+)
+
+var (
+	errChannelForbidden = errors.New("You are not allowed to watch this channel")
+	errInvalidTicket    = errors.New("Invalid or expired ticket")
+
+	// liveFallbackKey signs tickets when no ADMIN_TOKEN is configured, so they
+	// are only valid on the process that issued them.
+	liveFallbackKey = randomKey()
+)
+
+type liveRequest struct {
+	Type    string `json:"type"`
+	Channel string `json:"channel"`
+}
+
+type liveTicketRequest struct {
+	PersonID uint `json:"person_id" binding:"required"`
+}
+
+// CreateLiveTicket issues a short-lived ticket that lets a browser open the
+// live dashboard as the given person. It is meant to be called by the app
+// server on behalf of a signed-in user, behind RequireAdmin.
+func CreateLiveTicket(adminToken string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req liveTicketRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		var person models.Person
+		if err := database.GetDB().First(&person, req.PersonID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Person not found"})
+			return
+		}
+
+		expiresAt := time.Now().Add(liveTicketTTL)
+		c.JSON(http.StatusCreated, gin.H{
+			"ticket":     signLiveTicket(adminToken, person.ID, expiresAt),
+			"expires_at": expiresAt.UTC(),
+		})
+	}
+}
+
+func LiveDashboard(adminToken string, allowedOrigins []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		admin := adminToken != "" && tokenMatches(bearerToken(c), adminToken)
+
+		var viewer models.Person
+		if ticket := c.Query("ticket"); ticket != "" {
+			id, err := verifyLiveTicket(adminToken, ticket, time.Now())
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+				return
+			}
+			if err := database.GetDB().First(&viewer, id).Error; err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": errInvalidTicket.Error()})
+				return
+			}
+		} else if !admin {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "A ticket is required"})
+			return
+		}
+
+		server := websocket.Server{
+			Handshake: func(config *websocket.Config, r *http.Request) error {
+				return checkLiveOrigin(config, r, allowedOrigins)
+			},
+			Handler: func(ws *websocket.Conn) {
+				serveLiveDashboard(ws, viewer, admin)
+			},
+		}
+		server.ServeHTTP(c.Writer, c.Request)
+	}
+}
+
+// checkLiveOrigin accepts browsers from the API's own host or an allowed
+// origin. Clients that send no Origin are not browsers and are let through.
+func checkLiveOrigin(config *websocket.Config, r *http.Request, allowedOrigins []string) error {
+	origin, err := websocket.Origin(config, r)
+	if err != nil {
+		return err
+	}
+	config.Origin = origin
+	if origin == nil || strings.EqualFold(origin.Host, r.Host) {
+		return nil
+	}
+	for _, allowed := range allowedOrigins {
+		if strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin.Scheme+"://"+origin.Host) {
+			return nil
+		}
+	}
+	return fmt.Errorf("origin %s is not allowed", origin)
+}
+
+func signLiveTicket(adminToken string, personID uint, expiresAt time.Time) string {
+	claims := fmt.Sprintf("%d.%d", personID, expiresAt.Unix())
+	return claims + "." + liveTicketMAC(adminToken, claims)
+}
+
+func verifyLiveTicket(adminToken, ticket string, now time.Time) (uint, error) {
+	parts := strings.Split(ticket, ".")
+	if len(parts) != 3 {
+		return 0, errInvalidTicket
+	}
+	claims := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(liveTicketMAC(adminToken, claims))) {
+		return 0, errInvalidTicket
+	}
+
+	id, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, errInvalidTicket
+	}
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || now.Unix() > expires {
+		return 0, errInvalidTicket
+	}
+	return uint(id), nil
+}
+
+func liveTicketMAC(adminToken, claims string) string {
+	key := liveFallbackKey
+	if adminToken != "" {
+		key = []byte(adminToken)
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("live-ticket." + claims))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func randomKey() []byte {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(err)
+	}
+	return key
+}
+
+func serveLiveDashboard(ws *websocket.Conn, viewer models.Person, admin bool) {
+	ws.MaxPayloadBytes = liveMaxMessage
+
+	hub := realtime.Default
+	client := hub.Register(realtime.Viewer{ID: viewer.ID, Name: viewer.Name}, liveBuffer)
+
+	written := make(chan struct{})
+	go func() {
+		defer close(written)
+		for msg := range client.Messages() {
+			ws.SetWriteDeadline(time.Now().Add(liveWriteTimeout))
+			if err := websocket.JSON.Send(ws, msg); err != nil {
+				break
+			}
+		}
+		ws.Close()
+	}()
+
+	hub.Send(client, realtime.Message{Type: realtime.MessageWelcome, Viewer: &client.Viewer})
+
+	for {
+		ws.SetReadDeadline(time.Now().Add(liveIdleTimeout))
+		var req liveRequest
+		if err := websocket.JSON.Receive(ws, &req); err != nil {
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+				hub.Send(client, realtime.Message{Type: realtime.MessageError, Error: "Messages must be JSON"})
+				continue
+			}
+			break
+		}
+
+		switch req.Type {
+		case realtime.MessagePing:
+			hub.Send(client, realtime.Message{Type: realtime.MessagePong})
+		case realtime.MessageSubscribe:
+			if err := authorizeChannel(viewer, admin, req.Channel); err != nil {
+				hub.Send(client, realtime.Message{Type: realtime.MessageError, Channel: req.Channel, Error: err.Error()})
+				continue
+			}
+			hub.Subscribe(client, req.Channel)
+		case realtime.MessageUnsubscribe:
+			hub.Unsubscribe(client, req.Channel)
+		default:
+			hub.Send(client, realtime.Message{Type: realtime.MessageError, Error: "Unknown message type"})
+		}
+	}
+
+	hub.Unregister(client)
+	<-written
+}
+
+func authorizeChannel(viewer models.Person, admin bool, channel string) error {
+	kind, id, err := realtime.ParseChannel(channel)
+	if err != nil {
+		return err
+	}
+
+	switch kind {
+	case realtime.ChannelTeam:
+		var team models.Team
+		if err := database.GetDB().First(&team, id).Error; err != nil {
+			return errors.New("Team not found")
+		}
+		if admin || (viewer.TeamID != nil && *viewer.TeamID == id) {
+			return nil
+		}
+		var managed int64
+		database.GetDB().Model(&models.Person{}).Where("team_id = ? AND manager_id = ?", id, viewer.ID).Count(&managed)
+		if managed > 0 {
+			return nil
+		}
+	case realtime.ChannelPerson:
+		var person models.Person
+		if err := database.GetDB().First(&person, id).Error; err != nil {
+			return errors.New("Person not found")
+		}
+		if admin || person.ID == viewer.ID {
+			return nil
+		}
+		if person.ManagerID != nil && *person.ManagerID == viewer.ID {
+			return nil
+		}
+		if person.TeamID != nil && viewer.TeamID != nil && *person.TeamID == *viewer.TeamID {
+			return nil
+		}
+	}
+	return errChannelForbidden
+}
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"coaching-backend/database"
+	"coaching-backend/events"
+	"coaching-backend/models"
+	"coaching-backend/realtime"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/websocket"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupLiveTestServer(t *testing.T, adminToken string) (*gin.Engine, *httptest.Server) {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic("Failed to connect to test database")
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		panic("Failed to access test database")
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	err = database.Migrate(db)
+	if err != nil {
+		panic("Failed to migrate test database")
+	}
+
+	database.DB = db
+
+	unsubscribe := events.Subscribe(realtime.Default.Handle)
+	t.Cleanup(unsubscribe)
+
+	r := gin.New()
+
+	api := r.Group("/api/v1")
+	api.POST("/feedbacks", CreateFeedback)
+	api.GET("/live", LiveDashboard(adminToken, []string{"https://dashboard.example.com"}))
+	api.POST("/live/tickets", RequireAdmin(adminToken), CreateLiveTicket(adminToken))
+
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	return r, server
+}
+
+func liveConfig(t *testing.T, server *httptest.Server, query string) *websocket.Config {
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/live" + query
+	config, err := websocket.NewConfig(url, server.URL)
+	if err != nil {
+		t.Fatalf("Failed to configure WebSocket: %v", err)
+	}
+	return config
+}
+
+func liveTicket(t *testing.T, router *gin.Engine, personID uint) string {
+	w := makeRequest(t, router, "POST", "/api/v1/live/tickets", liveTicketRequest{PersonID: personID})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Failed to create ticket: %s", w.Body.String())
+	}
+	var response map[string]string
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return "?ticket=" + response["ticket"]
+}
+
+func dialLive(t *testing.T, server *httptest.Server, query string) *websocket.Conn {
+	return dialLiveConfig(t, liveConfig(t, server, query))
+}
+
+func dialLiveConfig(t *testing.T, config *websocket.Config) *websocket.Conn {
+	ws, err := websocket.DialConfig(config)
+	if err != nil {
+		t.Fatalf("Failed to open WebSocket: %v", err)
+	}
+	t.Cleanup(func() { ws.Close() })
+
+	welcome := receiveLive(t, ws)
+	assert.Equal(t, realtime.MessageWelcome, welcome.Type)
+	return ws
+}
+
+func sendLive(t *testing.T, ws *websocket.Conn, msgType, channel string) {
+	assert.NoError(t, websocket.JSON.Send(ws, liveRequest{Type: msgType, Channel: channel}))
+}
+
+func receiveLive(t *testing.T, ws *websocket.Conn) realtime.Message {
+	ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var msg realtime.Message
+	if err := websocket.JSON.Receive(ws, &msg); err != nil {
+		t.Fatalf("Failed to receive WebSocket message: %v", err)
+	}
+	return msg
+}
+
+func TestLiveDashboard(t *testing.T) {
+	router, server := setupLiveTestServer(t, "")
+	team := createTestTeam(t, "Live Team", "")
+	lead := createTestPerson(t, "Live Lead", "live.lead@example.com", "")
+	member := createTestPerson(t, "Live Member", "live.member@example.com", "")
+	database.GetDB().Model(&member).Updates(map[string]interface{}{"team_id": team.ID, "manager_id": lead.ID})
+	outsider := createTestPerson(t, "Live Outsider", "live.outsider@example.com", "")
+	channel := fmt.Sprintf("team:%d", team.ID)
+
+	t.Run("should refuse connections without a valid ticket", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", "/api/v1/live", nil)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		w = makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/live?viewer_id=%d", member.ID), nil)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		forged := strings.Replace(liveTicket(t, router, outsider.ID), fmt.Sprintf("=%d.", outsider.ID), fmt.Sprintf("=%d.", member.ID), 1)
+		w = makeRequest(t, router, "GET", "/api/v1/live"+forged, nil)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		expired := signLiveTicket("", member.ID, time.Now().Add(-time.Second))
+		w = makeRequest(t, router, "GET", "/api/v1/live?ticket="+expired, nil)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("should refuse browsers from other origins", func(t *testing.T) {
+		config := liveConfig(t, server, liveTicket(t, router, member.ID))
+		config.Origin, _ = url.Parse("https://evil.example.com")
+		_, err := websocket.DialConfig(config)
+		assert.Error(t, err)
+
+		config = liveConfig(t, server, liveTicket(t, router, member.ID))
+		config.Origin, _ = url.Parse("https://dashboard.example.com")
+		dialLiveConfig(t, config)
+	})
+
+	t.Run("should refuse subscriptions outside the viewer's reach", func(t *testing.T) {
+		ws := dialLive(t, server, liveTicket(t, router, outsider.ID))
+
+		sendLive(t, ws, realtime.MessageSubscribe, channel)
+		msg := receiveLive(t, ws)
+		assert.Equal(t, realtime.MessageError, msg.Type)
+		assert.Equal(t, errChannelForbidden.Error(), msg.Error)
+
+		sendLive(t, ws, realtime.MessageSubscribe, fmt.Sprintf("person:%d", member.ID))
+		assert.Equal(t, realtime.MessageError, receiveLive(t, ws).Type)
+
+		sendLive(t, ws, realtime.MessageSubscribe, "goal:1")
+		assert.Equal(t, realtime.MessageError, receiveLive(t, ws).Type)
+	})
+
+	t.Run("should share presence and push team events", func(t *testing.T) {
+		memberWS := dialLive(t, server, liveTicket(t, router, member.ID))
+		sendLive(t, memberWS, realtime.MessageSubscribe, channel)
+		subscribed := receiveLive(t, memberWS)
+		assert.Equal(t, realtime.MessageSubscribed, subscribed.Type)
+		assert.Equal(t, []realtime.Viewer{{ID: member.ID, Name: member.Name}}, subscribed.Viewers)
+
+		leadWS := dialLive(t, server, liveTicket(t, router, lead.ID))
+		sendLive(t, leadWS, realtime.MessageSubscribe, channel)
+		assert.Len(t, receiveLive(t, leadWS).Viewers, 2)
+
+		presence := receiveLive(t, memberWS)
+		assert.Equal(t, realtime.MessagePresence, presence.Type)
+		assert.Len(t, presence.Viewers, 2)
+
+		w := makeRequest(t, router, "POST", "/api/v1/feedbacks", models.CreateFeedbackRequest{Content: "Strong sprint", TargetType: "person", TargetID: member.ID})
+		assert.Equal(t, http.StatusCreated, w.Code)
+		flushOutbox(t)
+
+		for _, ws := range []*websocket.Conn{memberWS, leadWS} {
+			msg := receiveLive(t, ws)
+			assert.Equal(t, realtime.MessageEvent, msg.Type)
+			assert.Equal(t, channel, msg.Channel)
+			assert.Equal(t, events.FeedbackCreated, msg.Event.Type)
+		}
+
+		sendLive(t, leadWS, realtime.MessageUnsubscribe, channel)
+		assert.Equal(t, realtime.MessageUnsubscribed, receiveLive(t, leadWS).Type)
+		presence = receiveLive(t, memberWS)
+		assert.Equal(t, []realtime.Viewer{{ID: member.ID, Name: member.Name}}, presence.Viewers)
+	})
+
+	t.Run("should answer pings and reject unknown messages", func(t *testing.T) {
+		ws := dialLive(t, server, liveTicket(t, router, member.ID))
+
+		sendLive(t, ws, realtime.MessagePing, "")
+		assert.Equal(t, realtime.MessagePong, receiveLive(t, ws).Type)
+
+		sendLive(t, ws, "shout", "")
+		assert.Equal(t, realtime.MessageError, receiveLive(t, ws).Type)
+
+		assert.NoError(t, websocket.Message.Send(ws, "not json"))
+		assert.Equal(t, "Messages must be JSON", receiveLive(t, ws).Error)
+	})
+}
+
+func TestLiveDashboardAdmin(t *testing.T) {
+	router, server := setupLiveTestServer(t, "s3cret")
+	team := createTestTeam(t, "Admin Live Team", "")
+	person := createTestPerson(t, "Admin Live Person", "admin.live@example.com", "")
+
+	t.Run("should only issue tickets to admins", func(t *testing.T) {
+		w := makeRequest(t, router, "POST", "/api/v1/live/tickets", liveTicketRequest{PersonID: person.ID})
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("should not accept the admin token in the query", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", "/api/v1/live?token=s3cret", nil)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	config := liveConfig(t, server, "")
+	config.Header = http.Header{"Authorization": {"Bearer s3cret"}}
+	ws := dialLiveConfig(t, config)
+	sendLive(t, ws, realtime.MessageSubscribe, fmt.Sprintf("team:%d", team.ID))
+
+	msg := receiveLive(t, ws)
+	assert.Equal(t, realtime.MessageSubscribed, msg.Type)
+	assert.Empty(t, msg.Viewers)
+}
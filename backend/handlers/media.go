@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+	"coaching-backend/database"
+	"coaching-backend/events"
+	"coaching-backend/models"
+	"coaching-backend/outbox"
+	"coaching-backend/storage"
+	"coaching-backend/thumbnail"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	mediaURLPrefix = "/api/v1/media/"
+	// Leaves room for the multipart envelope around the largest image.
+	maxUploadBytes = thumbnail.MaxBytes + 1<<20
+	// Uploaded keys contain a hash of the image, so a URL never changes meaning.
+	mediaCacheControl = "public, max-age=31536000, immutable"
+)
+
+var errImageTooLarge = fmt.Errorf("Image must be at most %d MB", thumbnail.MaxBytes>>20)
+
+func UploadPersonPicture(c *gin.Context) {
+	person, ok := loadPerson(c)
+	if !ok {
+		return
+	}
+	if person.ErasedAt != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Person has been erased"})
+		return
+	}
+
+	url, ok := storeUpload(c, personMediaPrefix(person.ID), person.Picture)
+	if !ok {
+		return
+	}
+	setPersonPicture(c, person, url)
+}
+
+func DeletePersonPicture(c *gin.Context) {
+	person, ok := loadPerson(c)
+	if !ok {
+		return
+	}
+	setPersonPicture(c, person, "")
+}
+
+func UploadTeamLogo(c *gin.Context) {
+	team, ok := loadTeam(c)
+	if !ok {
+		return
+	}
+
+	url, ok := storeUpload(c, teamMediaPrefix(team.ID), team.Logo)
+	if !ok {
+		return
+	}
+	setTeamLogo(c, team, url)
+}
+
+func DeleteTeamLogo(c *gin.Context) {
+	team, ok := loadTeam(c)
+	if !ok {
+		return
+	}
+	setTeamLogo(c, team, "")
+}
+
+func GetMedia(c *gin.Context) {
+	blob, err := storage.Default.Get(c.Request.Context(), strings.TrimPrefix(c.Param("key"), "/"))
+	if errors.Is(err, storage.ErrNotFound) || errors.Is(err, storage.ErrInvalidKey) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Media not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load media"})
+		return
+	}
+
+	sum := sha256.Sum256(blob.Data)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Header("Cache-Control", mediaCacheControl)
+	c.Header("ETag", etag)
+	c.Header("X-Content-Type-Options", "nosniff")
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, blob.ContentType, blob.Data)
+}
+
+func setPersonPicture(c *gin.Context, person models.Person, url string) {
+	previous := person.Picture
+	person.Picture = url
+
+	err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&person).Error; err != nil {
+			return err
+		}
+		return outbox.Add(tx, events.PersonUpdated, person)
+	})
+	if err != nil {
+		if url != previous {
+			deleteMedia(personMediaPrefix(person.ID), url)
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update person"})
+		return
+	}
+
+	outbox.Notify()
+	if url != previous {
+		deleteMedia(personMediaPrefix(person.ID), previous)
+	}
+	c.JSON(http.StatusOK, person)
+}
+
+func setTeamLogo(c *gin.Context, team models.Team, url string) {
+	previous := team.Logo
+	team.Logo = url
+
+	err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&team).Error; err != nil {
+			return err
+		}
+		return outbox.Add(tx, events.TeamUpdated, team)
+	})
+	if err != nil {
+		if url != previous {
+			deleteMedia(teamMediaPrefix(team.ID), url)
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update team"})
+		return
+	}
+
+	outbox.Notify()
+	if url != previous {
+		deleteMedia(teamMediaPrefix(team.ID), previous)
+	}
+	c.JSON(http.StatusOK, team)
+}
+
+// storeUpload stores every thumbnail of the multipart "file" field under
+// prefix and returns the URL of the largest one. current is the URL in use,
+// which a failed upload of the same image must not remove.
+func storeUpload(c *gin.Context, prefix, current string) (string, bool) {
+	if c.Request.ContentLength > maxUploadBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": errImageTooLarge.Error()})
+		return "", false
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadBytes)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": errImageTooLarge.Error()})
+			return "", false
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A multipart file field named file is required"})
+		return "", false
+	}
+	if header.Size > thumbnail.MaxBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": errImageTooLarge.Error()})
+		return "", false
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read upload"})
+		return "", false
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, thumbnail.MaxBytes+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read upload"})
+		return "", false
+	}
+
+	thumbnails, err := thumbnail.Generate(data)
+	switch {
+	case errors.Is(err, thumbnail.ErrUnsupportedType):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+		return "", false
+	case errors.Is(err, thumbnail.ErrTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		return "", false
+	case errors.Is(err, thumbnail.ErrInvalidImage):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return "", false
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process image"})
+		return "", false
+	}
+
+	sum := sha256.Sum256(data)
+	dir := prefix + "/" + hex.EncodeToString(sum[:8])
+	var key string
+	for _, t := range thumbnails {
+		key = fmt.Sprintf("%s/%d%s", dir, t.Size, t.Ext)
+		if err := storage.Default.Put(c.Request.Context(), key, t.Data, t.ContentType); err != nil {
+			log.Printf("media: failed to store %s: %v", key, err)
+			if !strings.HasPrefix(current, mediaURLPrefix+dir+"/") {
+				deleteMedia(prefix, mediaURLPrefix+key)
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store image"})
+			return "", false
+		}
+	}
+	return mediaURLPrefix + key, true
+}
+
+func personMediaPrefix(id uint) string {
+	return fmt.Sprintf("persons/%d", id)
+}
+
+func teamMediaPrefix(id uint) string {
+	return fmt.Sprintf("teams/%d", id)
+}
+
+// deleteMedia removes every thumbnail of an image uploaded under prefix. Other
+// URLs, such as external links or another entity's uploads, are left alone.
+func deleteMedia(prefix, url string) {
+	if !strings.HasPrefix(url, mediaURLPrefix+prefix+"/") {
+		return
+	}
+	key := strings.TrimPrefix(url, mediaURLPrefix)
+	if path.Clean(key) != key {
+		return
+	}
+	dir, ext := path.Dir(key), path.Ext(key)
+
+	for _, size := range thumbnail.Sizes {
+		key := fmt.Sprintf("%s/%d%s", dir, size, ext)
+		if err := storage.Default.Delete(context.Background(), key); err != nil {
+			log.Printf("media: failed to delete %s: %v", key, err)
+		}
+	}
+}
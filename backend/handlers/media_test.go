@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"coaching-backend/storage"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupMediaTestRouter(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic("Failed to connect to test database")
+	}
+
+	err = database.Migrate(db)
+	if err != nil {
+		panic("Failed to migrate test database")
+	}
+
+	database.DB = db
+
+	previous := storage.Default
+	storage.Default = storage.NewLocal(t.TempDir())
+	t.Cleanup(func() { storage.Default = previous })
+
+	r := gin.New()
+
+	api := r.Group("/api/v1")
+	api.PUT("/persons/:id", UpdatePerson)
+	api.DELETE("/persons/:id", DeletePerson)
+	api.PUT("/teams/:id", UpdateTeam)
+	api.POST("/persons/:id/picture", UploadPersonPicture)
+	api.DELETE("/persons/:id/picture", DeletePersonPicture)
+	api.POST("/teams/:id/logo", UploadTeamLogo)
+	api.DELETE("/teams/:id/logo", DeleteTeamLogo)
+	api.GET("/media/*key", GetMedia)
+
+	return r
+}
+
+func testPNG(width, height int, c color.Color) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	return buf.Bytes()
+}
+
+func uploadFile(t *testing.T, router *gin.Engine, url, field string, data []byte) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile(field, "upload.png")
+	assert.NoError(t, err)
+	part.Write(data)
+	writer.Close()
+
+	req, err := http.NewRequest("POST", url, &body)
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func mediaExists(url string) bool {
+	_, err := storage.Default.Get(context.Background(), strings.TrimPrefix(url, mediaURLPrefix))
+	return err == nil
+}
+
+func TestUploadPersonPicture(t *testing.T) {
+	router := setupMediaTestRouter(t)
+	person := createTestPerson(t, "Pictured Person", "pictured@example.com", "https://example.com/old.png")
+	url := fmt.Sprintf("/api/v1/persons/%d/picture", person.ID)
+
+	var picture string
+
+	t.Run("should store thumbnails and set the picture", func(t *testing.T) {
+		w := uploadFile(t, router, url, "file", testPNG(400, 300, color.RGBA{R: 255, A: 255}))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response models.Person
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Regexp(t, fmt.Sprintf(`^/api/v1/media/persons/%d/[0-9a-f]{16}/256\.png$`, person.ID), response.Picture)
+		picture = response.Picture
+
+		for _, size := range []string{"64", "128"} {
+			assert.True(t, mediaExists(strings.Replace(picture, "/256.png", "/"+size+".png", 1)), size)
+		}
+	})
+
+	t.Run("should serve the picture with caching headers", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", picture, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+		assert.Equal(t, "public, max-age=31536000, immutable", w.Header().Get("Cache-Control"))
+		assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+
+		img, err := png.Decode(w.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, 256, img.Bounds().Dx())
+
+		req, _ := http.NewRequest("GET", picture, nil)
+		req.Header.Set("If-None-Match", w.Header().Get("ETag"))
+		cached := httptest.NewRecorder()
+		router.ServeHTTP(cached, req)
+		assert.Equal(t, http.StatusNotModified, cached.Code)
+		assert.Empty(t, cached.Body.String())
+	})
+
+	t.Run("should replace the previous upload", func(t *testing.T) {
+		w := uploadFile(t, router, url, "file", testPNG(100, 100, color.RGBA{B: 255, A: 255}))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response models.Person
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NotEqual(t, picture, response.Picture)
+		assert.True(t, mediaExists(response.Picture))
+		assert.False(t, mediaExists(picture))
+		picture = response.Picture
+	})
+
+	t.Run("should keep the picture when the same image is uploaded again", func(t *testing.T) {
+		w := uploadFile(t, router, url, "file", testPNG(100, 100, color.RGBA{B: 255, A: 255}))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, mediaExists(picture))
+	})
+
+	t.Run("should validate uploads", func(t *testing.T) {
+		w := uploadFile(t, router, url, "file", []byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"))
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+
+		w = uploadFile(t, router, url, "file", testPNG(10, 10, color.Black)[:40])
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = uploadFile(t, router, url, "image", testPNG(10, 10, color.Black))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = uploadFile(t, router, url, "file", make([]byte, maxUploadBytes+1))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+		w = uploadFile(t, router, "/api/v1/persons/9999/picture", "file", testPNG(10, 10, color.Black))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = uploadFile(t, router, "/api/v1/persons/abc/picture", "file", testPNG(10, 10, color.Black))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		assert.True(t, mediaExists(picture))
+	})
+
+	t.Run("should keep the picture when it is omitted from an update", func(t *testing.T) {
+		w := makeRequest(t, router, "PUT", fmt.Sprintf("/api/v1/persons/%d", person.ID), gin.H{"name": "Renamed Person", "email": person.Email})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response models.Person
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, picture, response.Picture)
+		assert.True(t, mediaExists(picture))
+	})
+
+	t.Run("should not remove uploads of another person", func(t *testing.T) {
+		other := createTestPerson(t, "Other Person", "other@example.com", "")
+		otherURL := fmt.Sprintf("/api/v1/persons/%d", other.ID)
+
+		w := makeRequest(t, router, "PUT", otherURL, gin.H{"name": other.Name, "email": other.Email, "picture": picture})
+		assert.Equal(t, http.StatusOK, w.Code)
+		w = makeRequest(t, router, "PUT", otherURL, gin.H{"name": other.Name, "email": other.Email, "picture": ""})
+		assert.Equal(t, http.StatusOK, w.Code)
+		w = makeRequest(t, router, "PUT", otherURL, gin.H{"name": other.Name, "email": other.Email, "picture": strings.Replace(picture, "/persons/", fmt.Sprintf("/persons/%d/../", other.ID), 1)})
+		assert.Equal(t, http.StatusOK, w.Code)
+		w = makeRequest(t, router, "DELETE", otherURL, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		assert.True(t, mediaExists(picture))
+	})
+
+	t.Run("should remove uploads replaced by a URL", func(t *testing.T) {
+		w := makeRequest(t, router, "PUT", fmt.Sprintf("/api/v1/persons/%d", person.ID), gin.H{"name": person.Name, "email": person.Email, "picture": "https://example.com/new.png"})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.False(t, mediaExists(picture))
+	})
+
+	t.Run("should remove uploads with the person", func(t *testing.T) {
+		w := uploadFile(t, router, url, "file", testPNG(50, 50, color.White))
+		var response models.Person
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		w = makeRequest(t, router, "DELETE", fmt.Sprintf("/api/v1/persons/%d", person.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.False(t, mediaExists(response.Picture))
+	})
+}
+
+func TestUploadTeamLogo(t *testing.T) {
+	router := setupMediaTestRouter(t)
+	team := createTestTeam(t, "Logo Team", "")
+	url := fmt.Sprintf("/api/v1/teams/%d/logo", team.ID)
+
+	w := uploadFile(t, router, url, "file", testPNG(64, 64, color.RGBA{G: 255, A: 128}))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.Team
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.True(t, strings.HasPrefix(response.Logo, fmt.Sprintf("/api/v1/media/teams/%d/", team.ID)))
+	assert.True(t, mediaExists(response.Logo))
+
+	w = makeRequest(t, router, "PUT", fmt.Sprintf("/api/v1/teams/%d", team.ID), gin.H{"name": "Renamed Team"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	var renamed models.Team
+	json.Unmarshal(w.Body.Bytes(), &renamed)
+	assert.Equal(t, response.Logo, renamed.Logo)
+	assert.True(t, mediaExists(response.Logo))
+
+	w = makeRequest(t, router, "DELETE", url, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var cleared models.Team
+	database.GetDB().First(&cleared, team.ID)
+	assert.Empty(t, cleared.Logo)
+	assert.False(t, mediaExists(response.Logo))
+}
+
+func TestGetMedia(t *testing.T) {
+	router := setupMediaTestRouter(t)
+
+	w := makeRequest(t, router, "GET", "/api/v1/media/persons/1/missing/64.png", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = makeRequest(t, router, "GET", "/api/v1/media/persons/1/..%2F..%2Fsecret", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+	"coaching-backend/database"
+	"coaching-backend/events"
+	"coaching-backend/inbox"
+	"coaching-backend/models"
+	"coaching-backend/moderation"
+	"coaching-backend/outbox"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var errFeedbackNotPending = errors.New("Feedback is not awaiting review")
+
+func CreateModerationRule(c *gin.Context) {
+	var req models.ModerationRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule := models.ModerationRule{}
+	if !applyModerationRuleRequest(c, &rule, req) {
+		return
+	}
+
+	if err := database.GetDB().Create(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create moderation rule"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+func GetModerationRules(c *gin.Context) {
+	var rules []models.ModerationRule
+	if err := database.GetDB().Order("id").Find(&rules).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch moderation rules"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+func UpdateModerationRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid moderation rule ID"})
+		return
+	}
+
+	var req models.ModerationRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var rule models.ModerationRule
+	if err := database.GetDB().First(&rule, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Moderation rule not found"})
+		return
+	}
+
+	if !applyModerationRuleRequest(c, &rule, req) {
+		return
+	}
+
+	if err := database.GetDB().Save(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update moderation rule"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+func DeleteModerationRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid moderation rule ID"})
+		return
+	}
+
+	if err := database.GetDB().Delete(&models.ModerationRule{}, id).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete moderation rule"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Moderation rule deleted successfully"})
+}
+
+func GetModerationQueue(c *gin.Context) {
+	status := c.DefaultQuery("status", models.ModerationPending)
+	if status != models.ModerationPending && status != models.ModerationRejected && status != models.ModerationApproved {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be pending, approved or rejected"})
+		return
+	}
+
+	var feedbacks []models.Feedback
+	if err := database.GetDB().Preload("Answers").Where("moderation_status = ?", status).
+		Order("created_at").Find(&feedbacks).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch moderation queue"})
+		return
+	}
+
+	c.JSON(http.StatusOK, feedbacks)
+}
+
+func ApproveFeedback(c *gin.Context) {
+	decideModeration(c, models.ModerationApproved)
+}
+
+func RejectFeedback(c *gin.Context) {
+	decideModeration(c, models.ModerationRejected)
+}
+
+func decideModeration(c *gin.Context, status string) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid feedback ID"})
+		return
+	}
+
+	var feedback models.Feedback
+	if err := database.GetDB().First(&feedback, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feedback not found"})
+		return
+	}
+
+	now := time.Now()
+	republished := feedback.PublishedAt != nil
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		changes := map[string]interface{}{"moderation_status": status, "moderated_at": now}
+		if status == models.ModerationApproved && !republished {
+			changes["published_at"] = now
+			feedback.PublishedAt = &now
+		}
+		result := tx.Model(&models.Feedback{}).
+			Where("id = ? AND moderation_status = ?", feedback.ID, models.ModerationPending).
+			Updates(changes)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errFeedbackNotPending
+		}
+
+		feedback.ModerationStatus = status
+		feedback.ModeratedAt = &now
+		if status != models.ModerationApproved {
+			return nil
+		}
+		// Feedback that was edited after publishing has already been announced.
+		if republished {
+			return outbox.Add(tx, events.FeedbackUpdated, feedback)
+		}
+		if err := outbox.Add(tx, events.FeedbackCreated, feedback); err != nil {
+			return err
+		}
+		return inbox.FeedbackReceived(tx, feedback)
+	})
+	if errors.Is(err, errFeedbackNotPending) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to moderate feedback"})
+		return
+	}
+
+	outbox.Notify()
+	c.JSON(http.StatusOK, feedback)
+}
+
+func applyModerationRuleRequest(c *gin.Context, rule *models.ModerationRule, req models.ModerationRuleRequest) bool {
+	check := moderation.Rule{Name: req.Name, Kind: req.Kind, Pattern: req.Pattern, Action: req.Action}
+	if err := check.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+
+	rule.Name = req.Name
+	rule.Kind = req.Kind
+	rule.Pattern = req.Pattern
+	rule.Action = req.Action
+	rule.Enabled = req.Enabled == nil || *req.Enabled
+	return true
+}
+
+func moderateFeedback(feedback *models.Feedback) (moderation.Result, error) {
+	var stored []models.ModerationRule
+	if err := database.GetDB().Where("enabled = ?", true).Order("id").Find(&stored).Error; err != nil {
+		return moderation.Result{}, err
+	}
+
+	rules := make([]moderation.Rule, 0, len(stored))
+	for _, r := range stored {
+		rules = append(rules, moderation.Rule{Name: r.Name, Kind: r.Kind, Pattern: r.Pattern, Action: r.Action})
+	}
+
+	result := moderation.Apply(feedback.Content, rules)
+	feedback.Content = result.Content
+
+	for i := range feedback.Answers {
+		if feedback.Answers[i].AnswerType != models.AnswerTypeText {
+			continue
+		}
+		answer := moderation.Apply(feedback.Answers[i].Value, rules)
+		feedback.Answers[i].Value = answer.Content
+		result.Rejected = result.Rejected || answer.Rejected
+		result.Flagged = result.Flagged || answer.Flagged
+		for _, reason := range answer.Reasons {
+			if !containsString(result.Reasons, reason) {
+				result.Reasons = append(result.Reasons, reason)
+			}
+		}
+	}
+
+	// Only new feedback is approved automatically. An edit never publishes
+	// feedback that is awaiting review or was rejected.
+	switch {
+	case feedback.ModerationStatus == models.ModerationRejected:
+		return result, nil
+	case result.Flagged:
+		feedback.ModerationStatus = models.ModerationPending
+		feedback.ModeratedAt = nil
+	case feedback.ModerationStatus == "":
+		now := time.Now()
+		feedback.ModerationStatus = models.ModerationApproved
+		feedback.PublishedAt = &now
+	}
+	feedback.ModerationReasons = result.Reasons
+
+	return result, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
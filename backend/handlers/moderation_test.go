@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"coaching-backend/database"
+	"coaching-backend/events"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupModerationTestRouter(adminToken string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic("Failed to connect to test database")
+	}
+
+	err = database.Migrate(db)
+	if err != nil {
+		panic("Failed to migrate test database")
+	}
+
+	err = database.SeedModerationRules(db)
+	if err != nil {
+		panic("Failed to seed moderation rules")
+	}
+
+	database.DB = db
+
+	r := gin.New()
+
+	api := r.Group("/api/v1")
+	api.POST("/feedbacks", CreateFeedback)
+	api.GET("/feedbacks", GetFeedbacks)
+	api.GET("/feedbacks/:id", GetFeedback)
+	api.PUT("/feedbacks/:id", UpdateFeedback)
+	moderation := api.Group("/moderation", RequireAdmin(adminToken))
+	{
+		moderation.GET("/rules", GetModerationRules)
+		moderation.POST("/rules", CreateModerationRule)
+		moderation.PUT("/rules/:id", UpdateModerationRule)
+		moderation.DELETE("/rules/:id", DeleteModerationRule)
+		moderation.GET("/queue", GetModerationQueue)
+		moderation.POST("/queue/:id/approve", ApproveFeedback)
+		moderation.POST("/queue/:id/reject", RejectFeedback)
+	}
+
+	return r
+}
+
+func TestFeedbackModeration(t *testing.T) {
+	router := setupModerationTestRouter("")
+	team := createTestTeam(t, "Moderated Team", "")
+
+	post := func(content string) *httptest.ResponseRecorder {
+		return makeRequest(t, router, "POST", "/api/v1/feedbacks", models.CreateFeedbackRequest{
+			Content:    content,
+			TargetType: "team",
+			TargetID:   team.ID,
+		})
+	}
+
+	t.Run("should redact personal data", func(t *testing.T) {
+		w := post("Ping me on mod.person@example.com about the rollout")
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response models.Feedback
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Ping me on [redacted] about the rollout", response.Content)
+		assert.Equal(t, models.ModerationApproved, response.ModerationStatus)
+		assert.Equal(t, []string{"Email addresses (redact)"}, response.ModerationReasons)
+	})
+
+	t.Run("should hold abusive feedback for review", func(t *testing.T) {
+		w := post("That was a stupid decision")
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response models.Feedback
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, models.ModerationPending, response.ModerationStatus)
+
+		w = makeRequest(t, router, "GET", "/api/v1/feedbacks", nil)
+		var published []models.Feedback
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &published))
+		for _, f := range published {
+			assert.NotEqual(t, response.ID, f.ID)
+		}
+
+		w = makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/feedbacks/%d", response.ID), nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("should reject feedback breaking a reject rule", func(t *testing.T) {
+		w := makeRequest(t, router, "POST", "/api/v1/moderation/rules", models.ModerationRuleRequest{
+			Name: "No salaries", Kind: "regex", Pattern: `(?i)salary`, Action: "reject",
+		})
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		w = post("Your salary is public now")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, []interface{}{"No salaries (reject)"}, response["reasons"])
+	})
+
+	t.Run("should ignore disabled rules", func(t *testing.T) {
+		var rule models.ModerationRule
+		assert.NoError(t, database.GetDB().Where("name = ?", "No salaries").First(&rule).Error)
+
+		disabled := false
+		w := makeRequest(t, router, "PUT", fmt.Sprintf("/api/v1/moderation/rules/%d", rule.ID), models.ModerationRuleRequest{
+			Name: rule.Name, Kind: rule.Kind, Pattern: rule.Pattern, Action: rule.Action, Enabled: &disabled,
+		})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = post("Your salary review is next week")
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("should reject invalid rules", func(t *testing.T) {
+		w := makeRequest(t, router, "POST", "/api/v1/moderation/rules", models.ModerationRuleRequest{
+			Name: "Broken", Kind: "regex", Pattern: "(", Action: "flag",
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestModerationQueue(t *testing.T) {
+	router := setupModerationTestRouter("")
+	team := createTestTeam(t, "Queue Team", "")
+	member := createTestPerson(t, "Queue Member", "queue.member@example.com", "")
+	database.GetDB().Model(&member).Update("team_id", team.ID)
+
+	var flagged []models.Feedback
+	for _, content := range []string{"You are useless at planning", "Such an idiot mistake"} {
+		w := makeRequest(t, router, "POST", "/api/v1/feedbacks", models.CreateFeedbackRequest{Content: content, TargetType: "team", TargetID: team.ID})
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var f models.Feedback
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &f))
+		flagged = append(flagged, f)
+	}
+
+	w := makeRequest(t, router, "GET", "/api/v1/moderation/queue", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var queue []models.Feedback
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &queue))
+	assert.Len(t, queue, 2)
+
+	t.Run("should approve flagged feedback", func(t *testing.T) {
+		w := makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/moderation/queue/%d/approve", flagged[0].ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = makeRequest(t, router, "GET", "/api/v1/feedbacks", nil)
+		var published []models.Feedback
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &published))
+		assert.Len(t, published, 1)
+		assert.Equal(t, flagged[0].ID, published[0].ID)
+	})
+
+	t.Run("should reject flagged feedback", func(t *testing.T) {
+		w := makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/moderation/queue/%d/reject", flagged[1].ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = makeRequest(t, router, "GET", "/api/v1/moderation/queue?status=rejected", nil)
+		var rejected []models.Feedback
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &rejected))
+		assert.Len(t, rejected, 1)
+		assert.NotNil(t, rejected[0].ModeratedAt)
+	})
+
+	t.Run("should not decide twice", func(t *testing.T) {
+		w := makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/moderation/queue/%d/approve", flagged[1].ID), nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("should not publish edited feedback that is not approved", func(t *testing.T) {
+		w := makeRequest(t, router, "POST", "/api/v1/feedbacks", models.CreateFeedbackRequest{Content: "A dumb plan", TargetType: "team", TargetID: team.ID})
+		var pending models.Feedback
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &pending))
+
+		for id, status := range map[uint]string{flagged[1].ID: models.ModerationRejected, pending.ID: models.ModerationPending} {
+			w = makeRequest(t, router, "PUT", fmt.Sprintf("/api/v1/feedbacks/%d", id), models.UpdateFeedbackRequest{Content: "A friendly note"})
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response models.Feedback
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, status, response.ModerationStatus)
+		}
+
+		w = makeRequest(t, router, "GET", "/api/v1/feedbacks", nil)
+		var published []models.Feedback
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &published))
+		assert.Len(t, published, 1)
+	})
+
+	t.Run("should send approved feedback back to review when an edit is flagged", func(t *testing.T) {
+		w := makeRequest(t, router, "PUT", fmt.Sprintf("/api/v1/feedbacks/%d", flagged[0].ID), models.UpdateFeedbackRequest{Content: "Still a stupid plan"})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response models.Feedback
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, models.ModerationPending, response.ModerationStatus)
+		assert.Nil(t, response.ModeratedAt)
+	})
+
+	t.Run("should announce re-approved feedback as an update", func(t *testing.T) {
+		w := makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/moderation/queue/%d/approve", flagged[0].ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var created, updated, received int64
+		database.GetDB().Model(&models.OutboxEvent{}).Where("type = ?", events.FeedbackCreated).Count(&created)
+		database.GetDB().Model(&models.OutboxEvent{}).Where("type = ?", events.FeedbackUpdated).Count(&updated)
+		database.GetDB().Model(&models.Notification{}).Where("feedback_id = ?", flagged[0].ID).Count(&received)
+		assert.Equal(t, int64(1), created)
+		assert.Equal(t, int64(1), updated)
+		assert.Equal(t, int64(1), received)
+	})
+}
+
+func TestRequireAdmin(t *testing.T) {
+	router := setupModerationTestRouter("s3cret")
+
+	t.Run("should refuse requests without the token", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", "/api/v1/moderation/rules", nil)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("should accept the bearer token", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/v1/moderation/rules", nil)
+		req.Header.Set("Authorization", "Bearer s3cret")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var rules []models.ModerationRule
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &rules))
+		assert.Len(t, rules, len(models.DefaultModerationRules()))
+	})
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func GetNotificationPreferences(c *gin.Context) {
+	person, ok := loadPerson(c)
+	if !ok {
+		return
+	}
+
+	pref := models.NotificationPreference{PersonID: person.ID, EmailFrequency: models.EmailImmediate}
+	err := database.GetDB().Where("person_id = ?", person.ID).First(&pref).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notification preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, pref)
+}
+
+func UpdateNotificationPreferences(c *gin.Context) {
+	person, ok := loadPerson(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateNotificationPreferenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var pref models.NotificationPreference
+	err := database.GetDB().Where("person_id = ?", person.ID).First(&pref).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notification preferences"})
+		return
+	}
+
+	pref.PersonID = person.ID
+	pref.EmailFrequency = req.EmailFrequency
+	if err := database.GetDB().Save(&pref).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, pref)
+}
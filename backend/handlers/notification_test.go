@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupNotificationTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic("Failed to connect to test database")
+	}
+
+	err = database.Migrate(db)
+	if err != nil {
+		panic("Failed to migrate test database")
+	}
+
+	database.DB = db
+
+	r := gin.New()
+
+	api := r.Group("/api/v1")
+	api.GET("/persons/:id/notification-preferences", GetNotificationPreferences)
+	api.PUT("/persons/:id/notification-preferences", UpdateNotificationPreferences)
+
+	return r
+}
+
+func TestNotificationPreferences(t *testing.T) {
+	router := setupNotificationTestRouter()
+	person := createTestPerson(t, "Notified Person", "notified@example.com", "")
+	url := fmt.Sprintf("/api/v1/persons/%d/notification-preferences", person.ID)
+
+	t.Run("should default to immediate emails", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", url, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var pref models.NotificationPreference
+		json.Unmarshal(w.Body.Bytes(), &pref)
+		assert.Equal(t, person.ID, pref.PersonID)
+		assert.Equal(t, models.EmailImmediate, pref.EmailFrequency)
+	})
+
+	t.Run("should save and update the frequency", func(t *testing.T) {
+		w := makeRequest(t, router, "PUT", url, models.UpdateNotificationPreferenceRequest{EmailFrequency: models.EmailWeekly})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = makeRequest(t, router, "PUT", url, models.UpdateNotificationPreferenceRequest{EmailFrequency: models.EmailDaily})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var prefs []models.NotificationPreference
+		database.GetDB().Find(&prefs)
+		assert.Len(t, prefs, 1)
+		assert.Equal(t, models.EmailDaily, prefs[0].EmailFrequency)
+
+		w = makeRequest(t, router, "GET", url, nil)
+		var pref models.NotificationPreference
+		json.Unmarshal(w.Body.Bytes(), &pref)
+		assert.Equal(t, models.EmailDaily, pref.EmailFrequency)
+	})
+
+	t.Run("should reject unknown frequencies", func(t *testing.T) {
+		w := makeRequest(t, router, "PUT", url, models.UpdateNotificationPreferenceRequest{EmailFrequency: "hourly"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should return error for non-existent person", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", "/api/v1/persons/999/notification-preferences", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = makeRequest(t, router, "PUT", "/api/v1/persons/abc/notification-preferences", models.UpdateNotificationPreferenceRequest{EmailFrequency: models.EmailOff})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -1,11 +1,17 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"coaching-backend/database"
+	"coaching-backend/events"
+	"coaching-backend/inbox"
 	"coaching-backend/models"
+	"coaching-backend/outbox"
+	"coaching-backend/privacy"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 func CreatePerson(c *gin.Context) {
@@ -15,17 +21,33 @@ func CreatePerson(c *gin.Context) {
 		return
 	}
 
+	if req.ManagerID != nil {
+		var manager models.Person
+		if err := database.GetDB().First(&manager, *req.ManagerID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Manager not found"})
+			return
+		}
+	}
+
 	person := models.Person{
-		Name:    req.Name,
-		Email:   req.Email,
-		Picture: req.Picture,
+		Name:      req.Name,
+		Email:     req.Email,
+		Picture:   req.Picture,
+		ManagerID: req.ManagerID,
 	}
 
-	if err := database.GetDB().Create(&person).Error; err != nil {
+	err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&person).Error; err != nil {
+			return err
+		}
+		return outbox.Add(tx, events.PersonCreated, person)
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create person"})
 		return
 	}
 
+	outbox.Notify()
 	c.JSON(http.StatusCreated, person)
 }
 
@@ -62,7 +84,7 @@ func UpdatePerson(c *gin.Context) {
 		return
 	}
 
-	var req models.CreatePersonRequest
+	var req models.UpdatePersonRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -74,15 +96,60 @@ func UpdatePerson(c *gin.Context) {
 		return
 	}
 
+	if person.ErasedAt != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Person has been erased"})
+		return
+	}
+
+	if req.ManagerID != nil && *req.ManagerID != 0 {
+		if *req.ManagerID == person.ID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "A person cannot be their own manager"})
+			return
+		}
+		var manager models.Person
+		if err := database.GetDB().First(&manager, *req.ManagerID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Manager not found"})
+			return
+		}
+		cycle, err := reportsTo(database.GetDB(), manager.ID, person.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update person"})
+			return
+		}
+		if cycle {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "A person cannot be managed by one of their reports"})
+			return
+		}
+	}
+
+	previousPicture := person.Picture
 	person.Name = req.Name
 	person.Email = req.Email
-	person.Picture = req.Picture
+	if req.Picture != nil {
+		person.Picture = *req.Picture
+	}
+	if req.ManagerID != nil {
+		person.ManagerID = req.ManagerID
+		if *req.ManagerID == 0 {
+			person.ManagerID = nil
+		}
+	}
 
-	if err := database.GetDB().Save(&person).Error; err != nil {
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&person).Error; err != nil {
+			return err
+		}
+		return outbox.Add(tx, events.PersonUpdated, person)
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update person"})
 		return
 	}
 
+	outbox.Notify()
+	if person.Picture != previousPicture {
+		deleteMedia(personMediaPrefix(person.ID), previousPicture)
+	}
 	c.JSON(http.StatusOK, person)
 }
 
@@ -93,11 +160,34 @@ func DeletePerson(c *gin.Context) {
 		return
 	}
 
-	if err := database.GetDB().Delete(&models.Person{}, id).Error; err != nil {
+	var picture string
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		var person models.Person
+		if err := tx.First(&person, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		picture = person.Picture
+		if err := tx.Where("person_id = ?", person.ID).Delete(&models.Notification{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("person_id = ?", person.ID).Delete(&models.TeamMembershipChange{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&person).Error; err != nil {
+			return err
+		}
+		return outbox.Add(tx, events.PersonDeleted, gin.H{"id": person.ID, "team_id": person.TeamID})
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete person"})
 		return
 	}
 
+	outbox.Notify()
+	deleteMedia(personMediaPrefix(uint(id)), picture)
 	c.JSON(http.StatusOK, gin.H{"message": "Person deleted successfully"})
 }
 
@@ -120,12 +210,28 @@ func AssignToTeam(c *gin.Context) {
 		return
 	}
 
+	previousTeamID := person.TeamID
 	person.TeamID = &req.TeamID
-	if err := database.GetDB().Save(&person).Error; err != nil {
+	err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&person).Error; err != nil {
+			return err
+		}
+		person.Team = &team
+		if err := outbox.Add(tx, events.PersonAssignedToTeam, models.TeamAssignment{Person: person, PreviousTeamID: previousTeamID}); err != nil {
+			return err
+		}
+		if err := privacy.RecordAssignment(tx, person.ID, previousTeamID, team.ID); err != nil {
+			return err
+		}
+		return inbox.AddedToTeam(tx, person, team)
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to assign person to team"})
 		return
 	}
 
+	outbox.Notify()
+
 	if err := database.GetDB().Preload("Team").First(&person, req.PersonID).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch updated person"})
 		return
@@ -147,12 +253,30 @@ func RemoveFromTeam(c *gin.Context) {
 		return
 	}
 
+	previousTeamID := person.TeamID
 	person.TeamID = nil
-	if err := database.GetDB().Save(&person).Error; err != nil {
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&person).Error; err != nil {
+			return err
+		}
+		if previousTeamID == nil {
+			return nil
+		}
+		if err := outbox.Add(tx, events.PersonRemovedFromTeam, gin.H{"person": person, "team_id": *previousTeamID}); err != nil {
+			return err
+		}
+		if err := privacy.RecordMembership(tx, person.ID, *previousTeamID, models.MembershipRemoved); err != nil {
+			return err
+		}
+		return inbox.RemovedFromTeam(tx, person, *previousTeamID)
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove person from team"})
 		return
 	}
 
+	outbox.Notify()
+
 	if err := database.GetDB().Preload("Team").First(&person, id).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch updated person"})
 		return
@@ -160,3 +284,45 @@ func RemoveFromTeam(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Person removed from team successfully", "person": person})
 }
+
+func loadPerson(c *gin.Context) (models.Person, bool) {
+	var person models.Person
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid person ID"})
+		return person, false
+	}
+
+	if err := database.GetDB().First(&person, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Person not found"})
+		return person, false
+	}
+	return person, true
+}
+
+// reportsTo reports whether personID is managerID or reports to it, directly
+// or through other managers.
+func reportsTo(db *gorm.DB, personID, managerID uint) (bool, error) {
+	seen := map[uint]bool{}
+	for !seen[personID] {
+		if personID == managerID {
+			return true, nil
+		}
+		seen[personID] = true
+
+		var person models.Person
+		err := db.Select("id, manager_id").First(&person, personID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if person.ManagerID == nil {
+			return false, nil
+		}
+		personID = *person.ManagerID
+	}
+	return false, nil
+}
@@ -22,7 +22,7 @@ func setupTestDB() *gorm.DB {
 		panic("Failed to connect to test database")
 	}
 
-	err = db.AutoMigrate(&models.Person{}, &models.Team{}, &models.Feedback{})
+	err = database.Migrate(db)
 	if err != nil {
 		panic("Failed to migrate test database")
 	}
@@ -329,6 +329,43 @@ func TestUpdatePerson(t *testing.T) {
 
 		assert.Equal(t, http.StatusNotFound, w.Code)
 	})
+
+	t.Run("should keep the manager unless manager_id is given", func(t *testing.T) {
+		lead := createTestPerson(t, "Lead", "lead@example.com", "")
+		report := createTestPerson(t, "Report", "report@example.com", "")
+		url := fmt.Sprintf("/api/v1/persons/%d", report.ID)
+
+		w := makeRequest(t, router, "PUT", url, gin.H{"name": "Report", "email": "report@example.com", "manager_id": lead.ID})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = makeRequest(t, router, "PUT", url, gin.H{"name": "Renamed Report", "email": "report@example.com"})
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response models.Person
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, lead.ID, *response.ManagerID)
+
+		w = makeRequest(t, router, "PUT", url, gin.H{"name": "Renamed Report", "email": "report@example.com", "manager_id": 0})
+		assert.Equal(t, http.StatusOK, w.Code)
+		response = models.Person{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Nil(t, response.ManagerID)
+	})
+
+	t.Run("should reject manager cycles", func(t *testing.T) {
+		a := createTestPerson(t, "Chain A", "chain.a@example.com", "")
+		b := createTestPerson(t, "Chain B", "chain.b@example.com", "")
+		c := createTestPerson(t, "Chain C", "chain.c@example.com", "")
+		setManager := func(person, manager models.Person) int {
+			w := makeRequest(t, router, "PUT", fmt.Sprintf("/api/v1/persons/%d", person.ID), gin.H{"name": person.Name, "email": person.Email, "manager_id": manager.ID})
+			return w.Code
+		}
+
+		assert.Equal(t, http.StatusBadRequest, setManager(a, a))
+		assert.Equal(t, http.StatusOK, setManager(b, a))
+		assert.Equal(t, http.StatusBadRequest, setManager(a, b))
+		assert.Equal(t, http.StatusOK, setManager(c, b))
+		assert.Equal(t, http.StatusBadRequest, setManager(a, c))
+	})
 }
 
 func TestDeletePerson(t *testing.T) {
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"coaching-backend/outbox"
+	"coaching-backend/privacy"
+	"github.com/gin-gonic/gin"
+)
+
+func ExportPersonData(c *gin.Context) {
+	person, ok := loadPerson(c)
+	if !ok {
+		return
+	}
+
+	requestedBy := c.Query("requested_by")
+	if requestedBy == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "requested_by is required"})
+		return
+	}
+
+	archive, err := privacy.Export(database.GetDB(), person.ID, requestedBy, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export person data"})
+		return
+	}
+
+	var b bytes.Buffer
+	if err := archive.WriteZip(&b); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export person data"})
+		return
+	}
+
+	filename := fmt.Sprintf("person-%d-data-%s.zip", person.ID, archive.GeneratedAt.Format("2006-01-02"))
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/zip", b.Bytes())
+}
+
+func ErasePerson(c *gin.Context) {
+	person, ok := loadPerson(c)
+	if !ok {
+		return
+	}
+
+	var req models.ErasePersonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entry, err := privacy.Erase(database.GetDB(), person.ID, req, time.Now())
+	if errors.Is(err, privacy.ErrAlreadyErased) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to erase person"})
+		return
+	}
+
+	outbox.Notify()
+	deleteMedia(personMediaPrefix(person.ID), person.Picture)
+	c.JSON(http.StatusOK, entry)
+}
+
+func GetPrivacyAudit(c *gin.Context) {
+	query := database.GetDB().Order("created_at desc, id desc")
+	if personIDStr := c.Query("person_id"); personIDStr != "" {
+		personID, err := strconv.Atoi(personIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid person ID"})
+			return
+		}
+		query = query.Where("person_id = ?", personID)
+	}
+
+	var entries []models.PrivacyAuditEntry
+	if err := query.Find(&entries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch privacy audit"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupPrivacyTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic("Failed to connect to test database")
+	}
+
+	err = database.Migrate(db)
+	if err != nil {
+		panic("Failed to migrate test database")
+	}
+
+	database.DB = db
+
+	r := gin.New()
+
+	api := r.Group("/api/v1")
+	api.PUT("/persons/:id", UpdatePerson)
+	api.GET("/privacy/persons/:id/export", ExportPersonData)
+	api.POST("/privacy/persons/:id/erase", ErasePerson)
+	api.GET("/privacy/audit", GetPrivacyAudit)
+
+	return r
+}
+
+func TestExportPersonData(t *testing.T) {
+	router := setupPrivacyTestRouter()
+	person := createTestPerson(t, "Data Subject", "subject@example.com", "")
+
+	t.Run("should download a zip archive", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/privacy/persons/%d/export?requested_by=dpo", person.ID), nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+		assert.Regexp(t, fmt.Sprintf(`^attachment; filename=person-%d-data-\d{4}-\d{2}-\d{2}\.zip$`, person.ID), w.Header().Get("Content-Disposition"))
+
+		archive, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+		assert.NoError(t, err)
+		names := map[string]bool{}
+		for _, f := range archive.File {
+			names[f.Name] = true
+		}
+		assert.True(t, names["manifest.json"])
+		assert.True(t, names["profile.json"])
+		assert.True(t, names["feedback_given.json"])
+	})
+
+	t.Run("should require requested_by", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/privacy/persons/%d/export", person.ID), nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should return 404 for unknown persons", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", "/api/v1/privacy/persons/9999/export?requested_by=dpo", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestErasePerson(t *testing.T) {
+	router := setupPrivacyTestRouter()
+	person := createTestPerson(t, "Erase Me", "erase.me@example.com", "me.png")
+	other := createTestPerson(t, "Stays Around", "stays@example.com", "")
+	feedback := models.Feedback{Content: "Thanks", TargetType: "person", TargetID: person.ID, TargetName: person.Name, AuthorID: &other.ID}
+	assert.NoError(t, database.GetDB().Create(&feedback).Error)
+	url := fmt.Sprintf("/api/v1/privacy/persons/%d/erase", person.ID)
+
+	t.Run("should require requested_by", func(t *testing.T) {
+		w := makeRequest(t, router, "POST", url, gin.H{"reason": "no requester"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should anonymize the person and record the erasure", func(t *testing.T) {
+		w := makeRequest(t, router, "POST", url, models.ErasePersonRequest{RequestedBy: "dpo", Reason: "Requested by email"})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var entry models.PrivacyAuditEntry
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &entry))
+		assert.Equal(t, models.PrivacyActionErasure, entry.Action)
+		assert.Equal(t, int64(1), entry.Affected["feedback_target_names"])
+
+		var erased models.Person
+		database.GetDB().First(&erased, person.ID)
+		assert.Equal(t, models.ErasedPersonName, erased.Name)
+
+		var stored models.Feedback
+		database.GetDB().First(&stored, feedback.ID)
+		assert.Equal(t, models.ErasedPersonName, stored.TargetName)
+		assert.Equal(t, other.ID, *stored.AuthorID)
+	})
+
+	t.Run("should refuse to erase twice", func(t *testing.T) {
+		w := makeRequest(t, router, "POST", url, models.ErasePersonRequest{RequestedBy: "dpo"})
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("should refuse updates to erased persons", func(t *testing.T) {
+		w := makeRequest(t, router, "PUT", fmt.Sprintf("/api/v1/persons/%d", person.ID), models.CreatePersonRequest{Name: "Erase Me", Email: "erase.me@example.com"})
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("should list the audit trail", func(t *testing.T) {
+		makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/privacy/persons/%d/export?requested_by=auditor", other.ID), nil)
+
+		w := makeRequest(t, router, "GET", "/api/v1/privacy/audit", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var entries []models.PrivacyAuditEntry
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &entries))
+		assert.Len(t, entries, 2)
+		assert.Equal(t, models.PrivacyActionExport, entries[0].Action)
+
+		w = makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/privacy/audit?person_id=%d", person.ID), nil)
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &entries))
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "Requested by email", entries[0].Reason)
+
+		w = makeRequest(t, router, "GET", "/api/v1/privacy/audit?person_id=abc", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net/http"
+	"time"
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"coaching-backend/report"
+	"github.com/gin-gonic/gin"
+)
+
+func GetPersonReport(c *gin.Context) {
+	person, ok := loadPerson(c)
+	if !ok {
+		return
+	}
+
+	period := c.DefaultQuery("period", report.PeriodQuarter)
+	if !report.IsPeriod(period) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "period must be month or quarter"})
+		return
+	}
+
+	db := database.GetDB()
+	if person.TeamID != nil {
+		var team models.Team
+		if err := db.First(&team, *person.TeamID).Error; err == nil {
+			person.Team = &team
+		}
+	}
+
+	data := report.PersonReport{Person: person, Authors: map[uint]string{}, Period: period, GeneratedAt: time.Now()}
+	if person.ManagerID != nil {
+		var manager models.Person
+		if err := db.First(&manager, *person.ManagerID).Error; err == nil {
+			data.Manager = &manager
+		}
+	}
+
+	if err := db.Where("target_type = ? AND target_id = ? AND moderation_status = ?", "person", person.ID, models.ModerationApproved).
+		Order("created_at asc, id asc").Find(&data.Feedback).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch feedbacks"})
+		return
+	}
+
+	var authorIDs []uint
+	for _, f := range data.Feedback {
+		if f.AuthorID != nil {
+			authorIDs = append(authorIDs, *f.AuthorID)
+		}
+	}
+	if len(authorIDs) > 0 {
+		var authors []models.Person
+		if err := db.Select("id, name").Where("id IN ?", authorIDs).Find(&authors).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch feedback authors"})
+			return
+		}
+		for _, author := range authors {
+			data.Authors[author.ID] = author.Name
+		}
+	}
+
+	var b bytes.Buffer
+	if _, err := data.Render().WriteTo(&b); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render report"})
+		return
+	}
+
+	filename := fmt.Sprintf("feedback-report-%d-%s.pdf", person.ID, data.GeneratedAt.Format("2006-01-02"))
+	c.Header("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": filename}))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/pdf", b.Bytes())
+}
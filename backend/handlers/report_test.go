@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupReportTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic("Failed to connect to test database")
+	}
+
+	err = database.Migrate(db)
+	if err != nil {
+		panic("Failed to migrate test database")
+	}
+
+	database.DB = db
+
+	r := gin.New()
+
+	api := r.Group("/api/v1")
+	api.GET("/persons/:id/report.pdf", GetPersonReport)
+
+	return r
+}
+
+func TestGetPersonReport(t *testing.T) {
+	router := setupReportTestRouter()
+	team := createTestTeam(t, "Report Team", "")
+	manager := createTestPerson(t, "Report Manager", "report.manager@example.com", "")
+	person := createTestPerson(t, "Report Person", "report.person@example.com", "")
+	database.GetDB().Model(&person).Updates(map[string]interface{}{"team_id": team.ID, "manager_id": manager.ID})
+
+	four := 4
+	feedbacks := []models.Feedback{
+		{Content: "Great pairing session", TargetType: "person", TargetID: person.ID, TargetName: person.Name, AuthorID: &manager.ID, Category: "collaboration", Rating: &four, Sentiment: "positive"},
+		{Content: "Pending comment", TargetType: "person", TargetID: person.ID, TargetName: person.Name, ModerationStatus: models.ModerationPending},
+		{Content: "Team level note", TargetType: "team", TargetID: team.ID, TargetName: team.Name},
+	}
+	assert.NoError(t, database.GetDB().Create(&feedbacks).Error)
+
+	t.Run("should render a PDF report", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/persons/%d/report.pdf", person.ID), nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+		assert.Regexp(t, fmt.Sprintf(`^inline; filename=feedback-report-%d-\d{4}-\d{2}-\d{2}\.pdf$`, person.ID), w.Header().Get("Content-Disposition"))
+		assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+
+		body := w.Body.String()
+		assert.True(t, strings.HasPrefix(body, "%PDF-"))
+		assert.Contains(t, body, "(Current team: Report Team)")
+		assert.Contains(t, body, "(Manager: Report Manager)")
+		assert.Contains(t, body, "(Great pairing session)")
+		assert.Contains(t, body, "Report Manager \xb7 rating 4/5")
+		assert.NotContains(t, body, "Pending comment")
+		assert.NotContains(t, body, "Team level note")
+	})
+
+	t.Run("should group by month when requested", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/persons/%d/report.pdf?period=month", person.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "(Rating trend by month)")
+	})
+
+	t.Run("should reject invalid requests", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/persons/%d/report.pdf?period=week", person.ID), nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = makeRequest(t, router, "GET", "/api/v1/persons/abc/report.pdf", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = makeRequest(t, router, "GET", "/api/v1/persons/9999/report.pdf", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
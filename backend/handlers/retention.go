@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"coaching-backend/retention"
+	"github.com/gin-gonic/gin"
+)
+
+const retentionRunsLimit = 50
+
+func CreateRetentionRule(c *gin.Context) {
+	var req models.RetentionRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule := models.RetentionRule{}
+	applyRetentionRuleRequest(&rule, req)
+
+	if err := database.GetDB().Create(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create retention rule"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+func GetRetentionRules(c *gin.Context) {
+	var rules []models.RetentionRule
+	if err := database.GetDB().Order("id").Find(&rules).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch retention rules"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+func UpdateRetentionRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid retention rule ID"})
+		return
+	}
+
+	var req models.RetentionRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var rule models.RetentionRule
+	if err := database.GetDB().First(&rule, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Retention rule not found"})
+		return
+	}
+
+	applyRetentionRuleRequest(&rule, req)
+
+	if err := database.GetDB().Save(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update retention rule"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+func DeleteRetentionRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid retention rule ID"})
+		return
+	}
+
+	if err := database.GetDB().Delete(&models.RetentionRule{}, id).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete retention rule"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Retention rule deleted successfully"})
+}
+
+func applyRetentionRuleRequest(rule *models.RetentionRule, req models.RetentionRuleRequest) {
+	rule.Name = req.Name
+	rule.TargetType = req.TargetType
+	rule.Category = req.Category
+	rule.Months = req.Months
+	rule.Action = req.Action
+	rule.Enabled = req.Enabled == nil || *req.Enabled
+}
+
+func RunRetention(c *gin.Context) {
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+
+	run, err := retention.Apply(database.GetDB(), time.Now(), dryRun, models.RetentionTriggerManual)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to apply retention rules", "run": run})
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
+
+func GetRetentionRuns(c *gin.Context) {
+	var runs []models.RetentionRun
+	if err := database.GetDB().Order("started_at desc, id desc").Limit(retentionRunsLimit).Find(&runs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch retention runs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, runs)
+}
+
+func GetRetentionMetrics(c *gin.Context) {
+	metrics, err := retention.Metrics(database.GetDB())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch retention metrics"})
+		return
+	}
+
+	c.JSON(http.StatusOK, metrics)
+}
+
+func GetLegalHolds(c *gin.Context) {
+	var feedbacks []models.Feedback
+	if err := database.GetDB().Where("legal_hold_at IS NOT NULL").Order("legal_hold_at desc, id desc").Find(&feedbacks).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch legal holds"})
+		return
+	}
+
+	c.JSON(http.StatusOK, feedbacks)
+}
+
+func PlaceLegalHold(c *gin.Context) {
+	var req models.LegalHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	setLegalHold(c, map[string]interface{}{"legal_hold_at": time.Now(), "legal_hold_reason": req.Reason})
+}
+
+func ReleaseLegalHold(c *gin.Context) {
+	setLegalHold(c, map[string]interface{}{"legal_hold_at": nil, "legal_hold_reason": ""})
+}
+
+func setLegalHold(c *gin.Context, updates map[string]interface{}) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid feedback ID"})
+		return
+	}
+
+	var feedback models.Feedback
+	if err := database.GetDB().First(&feedback, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feedback not found"})
+		return
+	}
+
+	if err := database.GetDB().Model(&feedback).Updates(updates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update legal hold"})
+		return
+	}
+
+	c.JSON(http.StatusOK, feedback)
+}
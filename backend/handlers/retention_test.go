@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupRetentionTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic("Failed to connect to test database")
+	}
+
+	err = database.Migrate(db)
+	if err != nil {
+		panic("Failed to migrate test database")
+	}
+
+	database.DB = db
+
+	r := gin.New()
+
+	api := r.Group("/api/v1")
+	api.PUT("/feedbacks/:id", UpdateFeedback)
+	api.DELETE("/feedbacks/:id", DeleteFeedback)
+	api.GET("/retention/rules", GetRetentionRules)
+	api.POST("/retention/rules", CreateRetentionRule)
+	api.PUT("/retention/rules/:id", UpdateRetentionRule)
+	api.DELETE("/retention/rules/:id", DeleteRetentionRule)
+	api.POST("/retention/run", RunRetention)
+	api.GET("/retention/runs", GetRetentionRuns)
+	api.GET("/retention/metrics", GetRetentionMetrics)
+	api.GET("/retention/holds", GetLegalHolds)
+	api.PUT("/retention/holds/:id", PlaceLegalHold)
+	api.DELETE("/retention/holds/:id", ReleaseLegalHold)
+
+	return r
+}
+
+func TestRetentionRules(t *testing.T) {
+	router := setupRetentionTestRouter()
+
+	t.Run("should create a rule enabled by default", func(t *testing.T) {
+		w := makeRequest(t, router, "POST", "/api/v1/retention/rules", gin.H{"name": "Team notes", "target_type": "team", "months": 12, "action": "delete"})
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var rule models.RetentionRule
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &rule))
+		assert.True(t, rule.Enabled)
+		assert.Equal(t, 12, rule.Months)
+	})
+
+	t.Run("should validate rules", func(t *testing.T) {
+		for _, body := range []gin.H{
+			{"name": "No months", "action": "delete"},
+			{"name": "Bad action", "months": 3, "action": "archive"},
+			{"name": "Bad target", "target_type": "goal", "months": 3, "action": "delete"},
+		} {
+			w := makeRequest(t, router, "POST", "/api/v1/retention/rules", body)
+			assert.Equal(t, http.StatusBadRequest, w.Code, body["name"])
+		}
+	})
+
+	t.Run("should update, list and delete rules", func(t *testing.T) {
+		rule := models.RetentionRule{Name: "Kudos", Category: "kudos", Months: 6, Action: models.RetentionDelete, Enabled: true}
+		database.GetDB().Create(&rule)
+
+		w := makeRequest(t, router, "PUT", fmt.Sprintf("/api/v1/retention/rules/%d", rule.ID), gin.H{"name": "Kudos", "category": "kudos", "months": 9, "action": "anonymize", "enabled": false})
+		assert.Equal(t, http.StatusOK, w.Code)
+		database.GetDB().First(&rule, rule.ID)
+		assert.Equal(t, 9, rule.Months)
+		assert.False(t, rule.Enabled)
+
+		w = makeRequest(t, router, "PUT", "/api/v1/retention/rules/9999", gin.H{"name": "Missing", "months": 1, "action": "delete"})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = makeRequest(t, router, "DELETE", fmt.Sprintf("/api/v1/retention/rules/%d", rule.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = makeRequest(t, router, "GET", "/api/v1/retention/rules", nil)
+		var rules []models.RetentionRule
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &rules))
+		assert.Len(t, rules, 1)
+	})
+}
+
+func TestRunRetention(t *testing.T) {
+	router := setupRetentionTestRouter()
+	database.GetDB().Create(&models.RetentionRule{Name: "Everything", Months: 12, Action: models.RetentionDelete, Enabled: true})
+
+	old := time.Now().AddDate(-2, 0, 0)
+	expired := models.Feedback{Content: "Expired", TargetType: "team", TargetID: 1, TargetName: "Core", CreatedAt: old}
+	held := models.Feedback{Content: "Evidence", TargetType: "team", TargetID: 1, TargetName: "Core", CreatedAt: old}
+	database.GetDB().Create(&expired)
+	database.GetDB().Create(&held)
+
+	t.Run("should place a legal hold", func(t *testing.T) {
+		w := makeRequest(t, router, "PUT", fmt.Sprintf("/api/v1/retention/holds/%d", held.ID), gin.H{"reason": "Pending dispute"})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = makeRequest(t, router, "PUT", fmt.Sprintf("/api/v1/retention/holds/%d", held.ID), gin.H{})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = makeRequest(t, router, "PUT", "/api/v1/retention/holds/9999", gin.H{"reason": "Missing"})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = makeRequest(t, router, "GET", "/api/v1/retention/holds", nil)
+		var holds []models.Feedback
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &holds))
+		assert.Len(t, holds, 1)
+		assert.Equal(t, "Pending dispute", holds[0].LegalHoldReason)
+	})
+
+	t.Run("should refuse to delete held feedback", func(t *testing.T) {
+		w := makeRequest(t, router, "DELETE", fmt.Sprintf("/api/v1/feedbacks/%d", held.ID), nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("should refuse to edit held feedback", func(t *testing.T) {
+		w := makeRequest(t, router, "PUT", fmt.Sprintf("/api/v1/feedbacks/%d", held.ID), models.UpdateFeedbackRequest{Content: "Rewritten"})
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		var feedback models.Feedback
+		database.GetDB().First(&feedback, held.ID)
+		assert.Equal(t, "Evidence", feedback.Content)
+		assert.NotNil(t, feedback.LegalHoldAt)
+	})
+
+	t.Run("should report a dry run", func(t *testing.T) {
+		w := makeRequest(t, router, "POST", "/api/v1/retention/run?dry_run=true", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var run models.RetentionRun
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &run))
+		assert.True(t, run.DryRun)
+		assert.Equal(t, int64(1), run.Deleted)
+		assert.Equal(t, int64(1), run.Held)
+		assert.Equal(t, []uint{expired.ID}, run.Rules[0].SampleIDs)
+		assert.NoError(t, database.GetDB().First(&models.Feedback{}, expired.ID).Error)
+	})
+
+	t.Run("should apply the rules and keep held feedback", func(t *testing.T) {
+		w := makeRequest(t, router, "POST", "/api/v1/retention/run", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		assert.Error(t, database.GetDB().First(&models.Feedback{}, expired.ID).Error)
+		assert.NoError(t, database.GetDB().First(&models.Feedback{}, held.ID).Error)
+
+		w = makeRequest(t, router, "GET", "/api/v1/retention/runs", nil)
+		var runs []models.RetentionRun
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &runs))
+		assert.Len(t, runs, 2)
+		assert.False(t, runs[0].DryRun)
+		assert.Equal(t, models.RetentionTriggerManual, runs[0].Trigger)
+
+		w = makeRequest(t, router, "GET", "/api/v1/retention/metrics", nil)
+		var metrics models.RetentionMetrics
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &metrics))
+		assert.Equal(t, int64(1), metrics.Runs)
+		assert.Equal(t, int64(1), metrics.Deleted)
+		assert.Equal(t, int64(1), metrics.LegalHolds)
+	})
+
+	t.Run("should release a legal hold", func(t *testing.T) {
+		w := makeRequest(t, router, "DELETE", fmt.Sprintf("/api/v1/retention/holds/%d", held.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var feedback models.Feedback
+		database.GetDB().First(&feedback, held.ID)
+		assert.Nil(t, feedback.LegalHoldAt)
+		assert.Empty(t, feedback.LegalHoldReason)
+
+		w = makeRequest(t, router, "POST", "/api/v1/retention/run", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Error(t, database.GetDB().First(&models.Feedback{}, held.ID).Error)
+	})
+}
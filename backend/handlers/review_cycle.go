@@ -0,0 +1,592 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+	"coaching-backend/database"
+	"coaching-backend/encryption"
+	"coaching-backend/events"
+	"coaching-backend/inbox"
+	"coaching-backend/models"
+	"coaching-backend/outbox"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var errAssignmentSubmitted = errors.New("Review has already been submitted")
+
+func CreateReviewCycle(c *gin.Context) {
+	var req models.CreateReviewCycleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !req.EndsAt.After(req.StartsAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ends_at must be after starts_at"})
+		return
+	}
+
+	if len(req.ParticipantIDs) == 0 && len(req.TeamIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "participant_ids or team_ids is required"})
+		return
+	}
+
+	if req.TemplateID != nil {
+		var template models.FeedbackTemplate
+		if err := database.GetDB().First(&template, *req.TemplateID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+			return
+		}
+	}
+
+	var participants []models.Person
+	query := database.GetDB().Where("id IN ?", append([]uint{0}, req.ParticipantIDs...))
+	if len(req.TeamIDs) > 0 {
+		query = query.Or("team_id IN ?", req.TeamIDs)
+	}
+	if err := query.Order("id").Find(&participants).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create review cycle"})
+		return
+	}
+
+	found := make(map[uint]bool, len(participants))
+	for _, p := range participants {
+		found[p.ID] = true
+	}
+	for _, id := range req.ParticipantIDs {
+		if !found[id] {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Participant not found"})
+			return
+		}
+	}
+	if len(participants) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Review cycle has no participants"})
+		return
+	}
+
+	var reports []models.Person
+	participantIDs := make([]uint, 0, len(participants))
+	for _, p := range participants {
+		participantIDs = append(participantIDs, p.ID)
+	}
+	if err := database.GetDB().Where("manager_id IN ?", participantIDs).Find(&reports).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create review cycle"})
+		return
+	}
+
+	cycle := models.ReviewCycle{
+		Name:       req.Name,
+		StartsAt:   req.StartsAt,
+		EndsAt:     req.EndsAt,
+		Status:     models.CycleStatusOpen,
+		TemplateID: req.TemplateID,
+	}
+
+	err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&cycle).Error; err != nil {
+			return err
+		}
+
+		members := make([]models.ReviewParticipant, 0, len(participants))
+		for _, p := range participants {
+			members = append(members, models.ReviewParticipant{CycleID: cycle.ID, PersonID: p.ID})
+		}
+		if err := tx.Create(&members).Error; err != nil {
+			return err
+		}
+		cycle.Participants = members
+
+		assignments := generateReviewAssignments(cycle.ID, participants, reports)
+		return tx.Create(&assignments).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create review cycle"})
+		return
+	}
+
+	summary, err := summarizeReviewCycle(cycle)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch review cycle"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, summary)
+}
+
+func GetReviewCycles(c *gin.Context) {
+	if err := closeEndedReviewCycles(database.GetDB()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch review cycles"})
+		return
+	}
+
+	var cycles []models.ReviewCycle
+	if err := database.GetDB().Order("starts_at desc").Find(&cycles).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch review cycles"})
+		return
+	}
+
+	summaries := make([]models.ReviewCycleSummary, 0, len(cycles))
+	for _, cycle := range cycles {
+		summary, err := summarizeReviewCycle(cycle)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch review cycles"})
+			return
+		}
+		summaries = append(summaries, summary)
+	}
+
+	c.JSON(http.StatusOK, summaries)
+}
+
+func GetReviewCycle(c *gin.Context) {
+	cycle, ok := loadReviewCycle(c)
+	if !ok {
+		return
+	}
+
+	if err := database.GetDB().Preload("Person").Where("cycle_id = ?", cycle.ID).Find(&cycle.Participants).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch review cycle"})
+		return
+	}
+
+	summary, err := summarizeReviewCycle(cycle)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch review cycle"})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+func CloseReviewCycle(c *gin.Context) {
+	cycle, ok := loadReviewCycle(c)
+	if !ok {
+		return
+	}
+
+	if cycle.Status == models.CycleStatusClosed {
+		c.JSON(http.StatusConflict, gin.H{"error": "Review cycle is already closed"})
+		return
+	}
+
+	now := time.Now()
+	cycle.Status = models.CycleStatusClosed
+	cycle.ClosedAt = &now
+	if err := database.GetDB().Save(&cycle).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to close review cycle"})
+		return
+	}
+
+	c.JSON(http.StatusOK, cycle)
+}
+
+func DeleteReviewCycle(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review cycle ID"})
+		return
+	}
+
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("cycle_id = ?", id).Delete(&models.ReviewAssignment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("cycle_id = ?", id).Delete(&models.ReviewParticipant{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.ReviewCycle{}, id).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete review cycle"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Review cycle deleted successfully"})
+}
+
+func GetReviewAssignments(c *gin.Context) {
+	cycle, ok := loadReviewCycle(c)
+	if !ok {
+		return
+	}
+
+	query := database.GetDB().Preload("Reviewer").Preload("Reviewee").Where("cycle_id = ?", cycle.ID)
+	for _, filter := range []string{"reviewer_id", "reviewee_id"} {
+		if value := c.Query(filter); value != "" {
+			id, err := strconv.Atoi(value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + filter})
+				return
+			}
+			query = query.Where(filter+" = ?", id)
+		}
+	}
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var assignments []models.ReviewAssignment
+	if err := query.Order("reviewee_id, id").Find(&assignments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch review assignments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, assignments)
+}
+
+func SubmitReview(c *gin.Context) {
+	cycle, ok := loadReviewCycle(c)
+	if !ok {
+		return
+	}
+
+	assignmentID, err := strconv.Atoi(c.Param("assignmentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+
+	var req models.SubmitReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	now := time.Now()
+	if cycle.Status == models.CycleStatusClosed {
+		c.JSON(http.StatusConflict, gin.H{"error": "Review cycle is closed"})
+		return
+	}
+	if now.Before(cycle.StartsAt) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Review cycle has not started yet"})
+		return
+	}
+
+	var assignment models.ReviewAssignment
+	if err := database.GetDB().Preload("Reviewee").Where("cycle_id = ?", cycle.ID).First(&assignment, assignmentID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Review assignment not found"})
+		return
+	}
+	if assignment.Status == models.AssignmentStatusSubmitted {
+		c.JSON(http.StatusConflict, gin.H{"error": errAssignmentSubmitted.Error()})
+		return
+	}
+
+	content := req.Content
+	var answers []models.FeedbackAnswer
+	if cycle.TemplateID != nil {
+		var template models.FeedbackTemplate
+		if err := database.GetDB().Preload("Questions", orderQuestions).First(&template, *cycle.TemplateID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+			return
+		}
+		answers, err = buildTemplateAnswers(template, req.Answers)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		content = renderFeedbackContent(req.Content, answers)
+	} else if len(req.Answers) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This review cycle does not use a template"})
+		return
+	}
+	if content == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Content or at least one answer is required"})
+		return
+	}
+
+	feedback := models.Feedback{
+		Content:         content,
+		TargetType:      "person",
+		TargetID:        assignment.RevieweeID,
+		TargetName:      assignment.Reviewee.Name,
+		TargetNameIndex: encryption.BlindIndex(assignment.Reviewee.Name),
+		TemplateID:      cycle.TemplateID,
+		Answers:         answers,
+		AuthorID:        &assignment.ReviewerID,
+	}
+
+	moderated, err := moderateFeedback(&feedback)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit review"})
+		return
+	}
+	if moderated.Rejected {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Review was rejected by moderation", "reasons": moderated.Reasons})
+		return
+	}
+	applySentiment(&feedback)
+
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&feedback).Error; err != nil {
+			return err
+		}
+		result := tx.Model(&models.ReviewAssignment{}).
+			Where("id = ? AND status = ?", assignment.ID, models.AssignmentStatusPending).
+			Updates(map[string]interface{}{
+				"status":       models.AssignmentStatusSubmitted,
+				"feedback_id":  feedback.ID,
+				"submitted_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errAssignmentSubmitted
+		}
+		if feedback.ModerationStatus != models.ModerationApproved {
+			return nil
+		}
+		if err := outbox.Add(tx, events.FeedbackCreated, feedback); err != nil {
+			return err
+		}
+		return inbox.FeedbackReceived(tx, feedback)
+	})
+	if errors.Is(err, errAssignmentSubmitted) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit review"})
+		return
+	}
+
+	assignment.Status = models.AssignmentStatusSubmitted
+	assignment.FeedbackID = &feedback.ID
+	assignment.SubmittedAt = &now
+	outbox.Notify()
+	c.JSON(http.StatusOK, assignment)
+}
+
+func GetReviewReport(c *gin.Context) {
+	cycle, ok := loadReviewCycle(c)
+	if !ok {
+		return
+	}
+
+	personID, err := strconv.Atoi(c.Param("personId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid person ID"})
+		return
+	}
+
+	var person models.Person
+	if err := database.GetDB().Preload("Team").First(&person, personID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Person not found"})
+		return
+	}
+
+	var assignments []models.ReviewAssignment
+	if err := database.GetDB().Where("cycle_id = ? AND reviewee_id = ?", cycle.ID, person.ID).Find(&assignments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build review report"})
+		return
+	}
+	if len(assignments) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Person is not part of this review cycle"})
+		return
+	}
+
+	relationships := make(map[uint]string)
+	feedbackIDs := make([]uint, 0, len(assignments))
+	for _, a := range assignments {
+		if a.FeedbackID != nil {
+			relationships[*a.FeedbackID] = a.Relationship
+			feedbackIDs = append(feedbackIDs, *a.FeedbackID)
+		}
+	}
+
+	var feedbacks []models.Feedback
+	if err := database.GetDB().Preload("Answers").
+		Where("id IN ? AND moderation_status = ?", append([]uint{0}, feedbackIDs...), models.ModerationApproved).
+		Order("id").Find(&feedbacks).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build review report"})
+		return
+	}
+
+	c.JSON(http.StatusOK, buildReviewReport(cycle.ID, person, assignments, feedbacks, relationships))
+}
+
+func loadReviewCycle(c *gin.Context) (models.ReviewCycle, bool) {
+	var cycle models.ReviewCycle
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review cycle ID"})
+		return cycle, false
+	}
+
+	if err := closeEndedReviewCycles(database.GetDB()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch review cycle"})
+		return cycle, false
+	}
+
+	if err := database.GetDB().First(&cycle, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Review cycle not found"})
+		return cycle, false
+	}
+
+	return cycle, true
+}
+
+func closeEndedReviewCycles(db *gorm.DB) error {
+	now := time.Now()
+	return db.Model(&models.ReviewCycle{}).
+		Where("status = ? AND ends_at < ?", models.CycleStatusOpen, now).
+		Updates(map[string]interface{}{"status": models.CycleStatusClosed, "closed_at": now}).Error
+}
+
+func summarizeReviewCycle(cycle models.ReviewCycle) (models.ReviewCycleSummary, error) {
+	var total, submitted int64
+	db := database.GetDB().Model(&models.ReviewAssignment{}).Where("cycle_id = ?", cycle.ID)
+	if err := db.Count(&total).Error; err != nil {
+		return models.ReviewCycleSummary{}, err
+	}
+	db = database.GetDB().Model(&models.ReviewAssignment{}).Where("cycle_id = ? AND status = ?", cycle.ID, models.AssignmentStatusSubmitted)
+	if err := db.Count(&submitted).Error; err != nil {
+		return models.ReviewCycleSummary{}, err
+	}
+
+	return models.ReviewCycleSummary{
+		ReviewCycle: cycle,
+		Progress:    newReviewProgress(int(total), int(submitted)),
+	}, nil
+}
+
+func newReviewProgress(total, submitted int) models.ReviewProgress {
+	progress := models.ReviewProgress{Total: total, Submitted: submitted}
+	if total > 0 {
+		progress.Percent = float64(submitted) * 100 / float64(total)
+	}
+	return progress
+}
+
+func generateReviewAssignments(cycleID uint, participants, reports []models.Person) []models.ReviewAssignment {
+	var assignments []models.ReviewAssignment
+	seen := make(map[[2]uint]bool)
+	add := func(reviewerID, revieweeID uint, relationship string) {
+		key := [2]uint{reviewerID, revieweeID}
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		assignments = append(assignments, models.ReviewAssignment{
+			CycleID:      cycleID,
+			ReviewerID:   reviewerID,
+			RevieweeID:   revieweeID,
+			Relationship: relationship,
+			Status:       models.AssignmentStatusPending,
+		})
+	}
+
+	for _, p := range participants {
+		add(p.ID, p.ID, models.RelationshipSelf)
+
+		if p.ManagerID != nil && *p.ManagerID != p.ID {
+			add(*p.ManagerID, p.ID, models.RelationshipManager)
+		}
+
+		for _, r := range reports {
+			if r.ManagerID != nil && *r.ManagerID == p.ID && r.ID != p.ID {
+				add(r.ID, p.ID, models.RelationshipReport)
+			}
+		}
+
+		if p.TeamID == nil {
+			continue
+		}
+		for _, peer := range participants {
+			if peer.ID == p.ID || peer.TeamID == nil || *peer.TeamID != *p.TeamID {
+				continue
+			}
+			add(peer.ID, p.ID, models.RelationshipPeer)
+		}
+	}
+
+	return assignments
+}
+
+func buildReviewReport(cycleID uint, person models.Person, assignments []models.ReviewAssignment, feedbacks []models.Feedback, relationships map[uint]string) models.ReviewReport {
+	report := models.ReviewReport{
+		CycleID:   cycleID,
+		Person:    person,
+		Progress:  make(map[string]models.ReviewProgress),
+		Questions: []models.QuestionSummary{},
+		Comments:  []models.ReviewResponse{},
+	}
+
+	counts := make(map[string][2]int)
+	for _, a := range assignments {
+		count := counts[a.Relationship]
+		count[0]++
+		if a.Status == models.AssignmentStatusSubmitted {
+			count[1]++
+		}
+		counts[a.Relationship] = count
+	}
+	for relationship, count := range counts {
+		report.Progress[relationship] = newReviewProgress(count[0], count[1])
+	}
+
+	type ratingTotal struct {
+		sum   int
+		count int
+	}
+	questionIndex := make(map[uint]int)
+	ratings := make(map[uint]map[string]*ratingTotal)
+
+	for _, f := range feedbacks {
+		relationship := relationships[f.ID]
+		if len(f.Answers) == 0 {
+			report.Comments = append(report.Comments, models.ReviewResponse{Relationship: relationship, Value: f.Content})
+			continue
+		}
+
+		for _, a := range f.Answers {
+			idx, ok := questionIndex[a.QuestionID]
+			if !ok {
+				idx = len(report.Questions)
+				questionIndex[a.QuestionID] = idx
+				report.Questions = append(report.Questions, models.QuestionSummary{Prompt: a.Prompt, AnswerType: a.AnswerType})
+			}
+
+			if a.AnswerType != models.AnswerTypeRating {
+				report.Questions[idx].Responses = append(report.Questions[idx].Responses, models.ReviewResponse{Relationship: relationship, Value: a.Value})
+				continue
+			}
+
+			value, err := strconv.Atoi(a.Value)
+			if err != nil {
+				continue
+			}
+			if ratings[a.QuestionID] == nil {
+				ratings[a.QuestionID] = make(map[string]*ratingTotal)
+			}
+			groups := []string{relationship}
+			if relationship != models.RelationshipSelf {
+				groups = append(groups, "others")
+			}
+			for _, group := range groups {
+				total := ratings[a.QuestionID][group]
+				if total == nil {
+					total = &ratingTotal{}
+					ratings[a.QuestionID][group] = total
+				}
+				total.sum += value
+				total.count++
+			}
+		}
+	}
+
+	for questionID, groups := range ratings {
+		averages := make(map[string]float64, len(groups))
+		for group, total := range groups {
+			averages[group] = float64(total.sum) / float64(total.count)
+		}
+		report.Questions[questionIndex[questionID]].AverageRating = averages
+	}
+
+	return report
+}
@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupReviewCycleTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic("Failed to connect to test database")
+	}
+
+	err = database.Migrate(db)
+	if err != nil {
+		panic("Failed to migrate test database")
+	}
+
+	database.DB = db
+
+	r := gin.New()
+
+	api := r.Group("/api/v1")
+	reviewCycles := api.Group("/review-cycles")
+	{
+		reviewCycles.POST("", CreateReviewCycle)
+		reviewCycles.GET("", GetReviewCycles)
+		reviewCycles.GET("/:id", GetReviewCycle)
+		reviewCycles.DELETE("/:id", DeleteReviewCycle)
+		reviewCycles.POST("/:id/close", CloseReviewCycle)
+		reviewCycles.GET("/:id/assignments", GetReviewAssignments)
+		reviewCycles.POST("/:id/assignments/:assignmentId/submit", SubmitReview)
+		reviewCycles.GET("/:id/report/:personId", GetReviewReport)
+	}
+
+	return r
+}
+
+type reviewCycleTestOrg struct {
+	team    models.Team
+	manager models.Person
+	alice   models.Person
+	bob     models.Person
+}
+
+func createReviewCycleTestOrg(t *testing.T) reviewCycleTestOrg {
+	team := createTestTeam(t, "Payments", "")
+	manager := createTestPerson(t, "Manager", "rc.manager@example.com", "")
+	alice := createTestPerson(t, "Alice", "rc.alice@example.com", "")
+	bob := createTestPerson(t, "Bob", "rc.bob@example.com", "")
+
+	for _, p := range []*models.Person{&alice, &bob} {
+		p.TeamID = &team.ID
+		p.ManagerID = &manager.ID
+		assert.NoError(t, database.GetDB().Save(p).Error)
+	}
+
+	return reviewCycleTestOrg{team: team, manager: manager, alice: alice, bob: bob}
+}
+
+func TestCreateReviewCycle(t *testing.T) {
+	router := setupReviewCycleTestRouter()
+	org := createReviewCycleTestOrg(t)
+
+	t.Run("should generate self, peer, manager and report assignments", func(t *testing.T) {
+		reqBody := models.CreateReviewCycleRequest{
+			Name:           "Q3 360",
+			StartsAt:       time.Now().Add(-time.Hour),
+			EndsAt:         time.Now().Add(14 * 24 * time.Hour),
+			ParticipantIDs: []uint{org.manager.ID},
+			TeamIDs:        []uint{org.team.ID},
+		}
+
+		w := makeRequest(t, router, "POST", "/api/v1/review-cycles", reqBody)
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var cycle models.ReviewCycleSummary
+		err := json.Unmarshal(w.Body.Bytes(), &cycle)
+		assert.NoError(t, err)
+		assert.Len(t, cycle.Participants, 3)
+		assert.Equal(t, 0, cycle.Progress.Submitted)
+
+		w = makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/review-cycles/%d/assignments?reviewee_id=%d", cycle.ID, org.alice.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var assignments []models.ReviewAssignment
+		err = json.Unmarshal(w.Body.Bytes(), &assignments)
+		assert.NoError(t, err)
+
+		relationships := map[string]uint{}
+		for _, a := range assignments {
+			relationships[a.Relationship] = a.ReviewerID
+		}
+		assert.Equal(t, map[string]uint{
+			models.RelationshipSelf:    org.alice.ID,
+			models.RelationshipPeer:    org.bob.ID,
+			models.RelationshipManager: org.manager.ID,
+		}, relationships)
+
+		w = makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/review-cycles/%d/assignments?reviewee_id=%d&status=pending", cycle.ID, org.manager.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		err = json.Unmarshal(w.Body.Bytes(), &assignments)
+		assert.NoError(t, err)
+		assert.Len(t, assignments, 3)
+	})
+
+	t.Run("should reject an empty date window", func(t *testing.T) {
+		now := time.Now()
+		reqBody := models.CreateReviewCycleRequest{
+			Name:           "Broken",
+			StartsAt:       now,
+			EndsAt:         now,
+			ParticipantIDs: []uint{org.alice.ID},
+		}
+
+		w := makeRequest(t, router, "POST", "/api/v1/review-cycles", reqBody)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should return error for unknown participant", func(t *testing.T) {
+		reqBody := models.CreateReviewCycleRequest{
+			Name:           "Broken",
+			StartsAt:       time.Now(),
+			EndsAt:         time.Now().Add(time.Hour),
+			ParticipantIDs: []uint{999},
+		}
+
+		w := makeRequest(t, router, "POST", "/api/v1/review-cycles", reqBody)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestSubmitReview(t *testing.T) {
+	router := setupReviewCycleTestRouter()
+	org := createReviewCycleTestOrg(t)
+
+	template := models.FeedbackTemplate{
+		Name: "360",
+		Questions: []models.TemplateQuestion{
+			{Position: 1, Prompt: "Collaboration", AnswerType: models.AnswerTypeRating, Required: true},
+			{Position: 2, Prompt: "Strengths", AnswerType: models.AnswerTypeText},
+		},
+	}
+	assert.NoError(t, database.GetDB().Create(&template).Error)
+
+	reqBody := models.CreateReviewCycleRequest{
+		Name:           "Q4 360",
+		StartsAt:       time.Now().Add(-time.Hour),
+		EndsAt:         time.Now().Add(time.Hour),
+		TemplateID:     &template.ID,
+		ParticipantIDs: []uint{org.alice.ID, org.bob.ID},
+	}
+	w := makeRequest(t, router, "POST", "/api/v1/review-cycles", reqBody)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var cycle models.ReviewCycleSummary
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &cycle))
+
+	var assignments []models.ReviewAssignment
+	assert.NoError(t, database.GetDB().Where("cycle_id = ? AND reviewee_id = ?", cycle.ID, org.alice.ID).Find(&assignments).Error)
+	byRelationship := map[string]models.ReviewAssignment{}
+	for _, a := range assignments {
+		byRelationship[a.Relationship] = a
+	}
+
+	submit := func(rating string) *models.SubmitReviewRequest {
+		return &models.SubmitReviewRequest{
+			Answers: []models.AnswerRequest{
+				{QuestionID: template.Questions[0].ID, Value: rating},
+				{QuestionID: template.Questions[1].ID, Value: "Pairs generously"},
+			},
+		}
+	}
+	submitURL := func(a models.ReviewAssignment) string {
+		return fmt.Sprintf("/api/v1/review-cycles/%d/assignments/%d/submit", cycle.ID, a.ID)
+	}
+
+	t.Run("should record submissions as feedback", func(t *testing.T) {
+		w := makeRequest(t, router, "POST", submitURL(byRelationship[models.RelationshipSelf]), submit("3"))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = makeRequest(t, router, "POST", submitURL(byRelationship[models.RelationshipPeer]), submit("5"))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var submitted models.ReviewAssignment
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &submitted))
+		assert.Equal(t, models.AssignmentStatusSubmitted, submitted.Status)
+
+		var feedback models.Feedback
+		assert.NoError(t, database.GetDB().First(&feedback, *submitted.FeedbackID).Error)
+		assert.Equal(t, org.alice.ID, feedback.TargetID)
+		assert.Equal(t, org.bob.ID, *feedback.AuthorID)
+	})
+
+	t.Run("should reject a second submission", func(t *testing.T) {
+		w := makeRequest(t, router, "POST", submitURL(byRelationship[models.RelationshipSelf]), submit("4"))
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("should aggregate a report per person", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/review-cycles/%d/report/%d", cycle.ID, org.alice.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var report models.ReviewReport
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		assert.Equal(t, 1, report.Progress[models.RelationshipPeer].Submitted)
+		assert.Len(t, report.Questions, 2)
+		assert.Equal(t, 3.0, report.Questions[0].AverageRating[models.RelationshipSelf])
+		assert.Equal(t, 5.0, report.Questions[0].AverageRating["others"])
+		assert.Len(t, report.Questions[1].Responses, 2)
+	})
+
+	t.Run("should leave feedback awaiting moderation out of the report", func(t *testing.T) {
+		var peer models.ReviewAssignment
+		assert.NoError(t, database.GetDB().First(&peer, byRelationship[models.RelationshipPeer].ID).Error)
+		database.GetDB().Model(&models.Feedback{}).Where("id = ?", *peer.FeedbackID).Update("moderation_status", models.ModerationPending)
+
+		w := makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/review-cycles/%d/report/%d", cycle.ID, org.alice.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var report models.ReviewReport
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		assert.NotContains(t, report.Questions[0].AverageRating, "others")
+		assert.Len(t, report.Questions[1].Responses, 1)
+	})
+
+	t.Run("should lock submissions once closed", func(t *testing.T) {
+		w := makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/review-cycles/%d/close", cycle.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = makeRequest(t, router, "POST", submitURL(byRelationship[models.RelationshipManager]), submit("4"))
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestReviewCycleClosesAfterWindow(t *testing.T) {
+	router := setupReviewCycleTestRouter()
+	org := createReviewCycleTestOrg(t)
+
+	cycle := models.ReviewCycle{
+		Name:     "Past",
+		StartsAt: time.Now().Add(-48 * time.Hour),
+		EndsAt:   time.Now().Add(-24 * time.Hour),
+		Status:   models.CycleStatusOpen,
+	}
+	assert.NoError(t, database.GetDB().Create(&cycle).Error)
+	assignment := models.ReviewAssignment{
+		CycleID:      cycle.ID,
+		ReviewerID:   org.alice.ID,
+		RevieweeID:   org.alice.ID,
+		Relationship: models.RelationshipSelf,
+		Status:       models.AssignmentStatusPending,
+	}
+	assert.NoError(t, database.GetDB().Create(&assignment).Error)
+
+	w := makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/review-cycles/%d/assignments/%d/submit", cycle.ID, assignment.ID), models.SubmitReviewRequest{Content: "Late"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/review-cycles/%d", cycle.ID), nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.ReviewCycleSummary
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, models.CycleStatusClosed, response.Status)
+}
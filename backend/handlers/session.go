@@ -0,0 +1,304 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func CreateSession(c *gin.Context) {
+	var req models.CreateSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.CoachID == req.CoacheeID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Coach and coachee must be different people"})
+		return
+	}
+
+	var coach models.Person
+	if err := database.GetDB().First(&coach, req.CoachID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Coach not found"})
+		return
+	}
+
+	var coachee models.Person
+	if err := database.GetDB().First(&coachee, req.CoacheeID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Coachee not found"})
+		return
+	}
+
+	session := models.CoachingSession{
+		CoachID:         req.CoachID,
+		CoacheeID:       req.CoacheeID,
+		ScheduledAt:     req.ScheduledAt,
+		DurationMinutes: req.DurationMinutes,
+		Agenda:          req.Agenda,
+		Status:          models.SessionStatusScheduled,
+	}
+
+	if err := database.GetDB().Create(&session).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
+		return
+	}
+
+	session.Coach = &coach
+	session.Coachee = &coachee
+	c.JSON(http.StatusCreated, session)
+}
+
+func GetSessions(c *gin.Context) {
+	query, ok := sessionListQuery(c, database.GetDB())
+	if !ok {
+		return
+	}
+
+	var sessions []models.CoachingSession
+	if err := query.Find(&sessions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, redactSessions(c, sessions))
+}
+
+func GetSession(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	var session models.CoachingSession
+	if err := database.GetDB().Preload("Coach").Preload("Coachee").Preload("Feedbacks").First(&session, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, redactSessions(c, []models.CoachingSession{session})[0])
+}
+
+func UpdateSession(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	var req models.UpdateSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var session models.CoachingSession
+	if err := database.GetDB().First(&session, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+
+	session.ScheduledAt = req.ScheduledAt
+	session.DurationMinutes = req.DurationMinutes
+	session.Agenda = req.Agenda
+	session.SharedNotes = req.SharedNotes
+	session.Outcomes = req.Outcomes
+	session.Status = req.Status
+	if isSessionCoach(c, session) {
+		session.PrivateNotes = req.PrivateNotes
+	}
+
+	if err := database.GetDB().Save(&session).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, redactSessions(c, []models.CoachingSession{session})[0])
+}
+
+func DeleteSession(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM session_feedbacks WHERE coaching_session_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.CoachingSession{}, id).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Session deleted successfully"})
+}
+
+func GetPersonSessions(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid person ID"})
+		return
+	}
+
+	var person models.Person
+	if err := database.GetDB().First(&person, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Person not found"})
+		return
+	}
+
+	query, ok := sessionListQuery(c, database.GetDB().Where("coach_id = ? OR coachee_id = ?", person.ID, person.ID))
+	if !ok {
+		return
+	}
+
+	var sessions []models.CoachingSession
+	if err := query.Find(&sessions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, redactSessions(c, sessions))
+}
+
+func GetTeamSessions(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	var team models.Team
+	if err := database.GetDB().First(&team, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+		return
+	}
+
+	members := database.GetDB().Model(&models.Person{}).Select("id").Where("team_id = ?", team.ID)
+	query, ok := sessionListQuery(c, database.GetDB().Where("coachee_id IN (?)", members))
+	if !ok {
+		return
+	}
+
+	var sessions []models.CoachingSession
+	if err := query.Find(&sessions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, redactSessions(c, sessions))
+}
+
+func AttachSessionFeedback(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	var req models.AttachFeedbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var session models.CoachingSession
+	if err := database.GetDB().First(&session, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+
+	var feedback models.Feedback
+	if err := database.GetDB().First(&feedback, req.FeedbackID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feedback not found"})
+		return
+	}
+
+	if err := database.GetDB().Model(&session).Association("Feedbacks").Append(&feedback); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to attach feedback"})
+		return
+	}
+
+	if err := database.GetDB().Preload("Feedbacks").First(&session, id).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch updated session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, redactSessions(c, []models.CoachingSession{session})[0])
+}
+
+func DetachSessionFeedback(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	feedbackID, err := strconv.Atoi(c.Param("feedbackId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid feedback ID"})
+		return
+	}
+
+	var session models.CoachingSession
+	if err := database.GetDB().First(&session, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+
+	if err := database.GetDB().Model(&session).Association("Feedbacks").Delete(&models.Feedback{ID: uint(feedbackID)}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to detach feedback"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Feedback detached from session successfully"})
+}
+
+func sessionListQuery(c *gin.Context, query *gorm.DB) (*gorm.DB, bool) {
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	for _, bound := range []struct {
+		param string
+		clause string
+	}{
+		{"from", "scheduled_at >= ?"},
+		{"to", "scheduled_at < ?"},
+	} {
+		value := c.Query(bound.param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + bound.param + ", expected RFC3339"})
+			return nil, false
+		}
+		query = query.Where(bound.clause, t)
+	}
+
+	return query.Preload("Coach").Preload("Coachee").Order("scheduled_at desc"), true
+}
+
+func isSessionCoach(c *gin.Context, session models.CoachingSession) bool {
+	viewer, ok := viewerID(c)
+	return ok && viewer == session.CoachID
+}
+
+func redactSessions(c *gin.Context, sessions []models.CoachingSession) []models.CoachingSession {
+	for i := range sessions {
+		if !isSessionCoach(c, sessions[i]) {
+			sessions[i].PrivateNotes = ""
+		}
+	}
+	return sessions
+}
@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupSessionTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic("Failed to connect to test database")
+	}
+
+	err = database.Migrate(db)
+	if err != nil {
+		panic("Failed to migrate test database")
+	}
+
+	database.DB = db
+
+	r := gin.New()
+
+	api := r.Group("/api/v1")
+	sessions := api.Group("/sessions", IdentifyViewer(""))
+	{
+		sessions.POST("", CreateSession)
+		sessions.GET("", GetSessions)
+		sessions.GET("/:id", GetSession)
+		sessions.PUT("/:id", UpdateSession)
+		sessions.DELETE("/:id", DeleteSession)
+		sessions.POST("/:id/feedbacks", AttachSessionFeedback)
+		sessions.DELETE("/:id/feedbacks/:feedbackId", DetachSessionFeedback)
+	}
+	api.GET("/persons/:id/sessions", IdentifyViewer(""), GetPersonSessions)
+	api.GET("/teams/:id/sessions", IdentifyViewer(""), GetTeamSessions)
+
+	return r
+}
+
+func TestCreateSession(t *testing.T) {
+	router := setupSessionTestRouter()
+	coach := createTestPerson(t, "Coach", "session.coach1@example.com", "")
+	coachee := createTestPerson(t, "Coachee", "session.coachee1@example.com", "")
+
+	t.Run("should create session successfully", func(t *testing.T) {
+		reqBody := models.CreateSessionRequest{
+			CoachID:         coach.ID,
+			CoacheeID:       coachee.ID,
+			ScheduledAt:     time.Now().Add(24 * time.Hour),
+			DurationMinutes: 30,
+			Agenda:          "Career goals",
+		}
+
+		w := makeRequest(t, router, "POST", "/api/v1/sessions", reqBody)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response models.CoachingSession
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.NotZero(t, response.ID)
+		assert.Equal(t, models.SessionStatusScheduled, response.Status)
+		assert.Equal(t, "Coach", response.Coach.Name)
+		assert.Equal(t, "Coachee", response.Coachee.Name)
+	})
+
+	t.Run("should reject coaching yourself", func(t *testing.T) {
+		reqBody := models.CreateSessionRequest{
+			CoachID:     coach.ID,
+			CoacheeID:   coach.ID,
+			ScheduledAt: time.Now(),
+		}
+
+		w := makeRequest(t, router, "POST", "/api/v1/sessions", reqBody)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should return error for non-existent coachee", func(t *testing.T) {
+		reqBody := models.CreateSessionRequest{
+			CoachID:     coach.ID,
+			CoacheeID:   999,
+			ScheduledAt: time.Now(),
+		}
+
+		w := makeRequest(t, router, "POST", "/api/v1/sessions", reqBody)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestSessionPrivateNotes(t *testing.T) {
+	router := setupSessionTestRouter()
+	coach := createTestPerson(t, "Coach", "session.coach2@example.com", "")
+	coachee := createTestPerson(t, "Coachee", "session.coachee2@example.com", "")
+	session := createSessionTestSession(t, coach.ID, coachee.ID, time.Now())
+
+	update := models.UpdateSessionRequest{
+		ScheduledAt:  session.ScheduledAt,
+		SharedNotes:  "Agreed to pair on reviews",
+		PrivateNotes: "Seems stressed about the deadline",
+		Outcomes:     "Shadow the tech lead",
+		Status:       models.SessionStatusCompleted,
+	}
+
+	url := fmt.Sprintf("/api/v1/sessions/%d", session.ID)
+
+	t.Run("should ignore private notes from the coachee", func(t *testing.T) {
+		w := viewerRequest(t, router, "PUT", url, update, coachee.ID)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var stored models.CoachingSession
+		assert.NoError(t, database.GetDB().First(&stored, session.ID).Error)
+		assert.Empty(t, stored.PrivateNotes)
+		assert.Equal(t, "Agreed to pair on reviews", stored.SharedNotes)
+	})
+
+	t.Run("should only show private notes to the coach", func(t *testing.T) {
+		w := viewerRequest(t, router, "PUT", url, update, coach.ID)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = viewerRequest(t, router, "GET", url, nil, coach.ID)
+		var response models.CoachingSession
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Seems stressed about the deadline", response.PrivateNotes)
+		assert.Equal(t, models.SessionStatusCompleted, response.Status)
+
+		w = viewerRequest(t, router, "GET", url, nil, coachee.ID)
+		response = models.CoachingSession{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Empty(t, response.PrivateNotes)
+		assert.Equal(t, "Shadow the tech lead", response.Outcomes)
+	})
+
+	t.Run("should not trust a viewer_id parameter", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", fmt.Sprintf("%s?viewer_id=%d", url, coach.ID), nil)
+		var response models.CoachingSession
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Empty(t, response.PrivateNotes)
+
+		w = makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/persons/%d/sessions?viewer_id=%d", coach.ID, coach.ID), nil)
+		var sessions []models.CoachingSession
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &sessions))
+		assert.Len(t, sessions, 1)
+		assert.Empty(t, sessions[0].PrivateNotes)
+	})
+
+	t.Run("should reject invalid tickets", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", url, nil)
+		req.Header.Set(ViewerTicketHeader, signLiveTicket("", coach.ID, time.Now().Add(-time.Second)))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+// viewerRequest sends a request as the given person, with a ticket like the
+// one CreateLiveTicket issues.
+func viewerRequest(t *testing.T, router *gin.Engine, method, url string, body interface{}, personID uint) *httptest.ResponseRecorder {
+	var reqBody bytes.Buffer
+	if body != nil {
+		assert.NoError(t, json.NewEncoder(&reqBody).Encode(body))
+	}
+	req, err := http.NewRequest(method, url, &reqBody)
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(ViewerTicketHeader, signLiveTicket("", personID, time.Now().Add(liveTicketTTL)))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestListSessions(t *testing.T) {
+	router := setupSessionTestRouter()
+	team := createTestTeam(t, "Mobile", "")
+	coach := createTestPerson(t, "Coach", "session.coach3@example.com", "")
+	member := createTestPerson(t, "Member", "session.member3@example.com", "")
+	outsider := createTestPerson(t, "Outsider", "session.outsider3@example.com", "")
+	assert.NoError(t, database.GetDB().Model(&member).Update("team_id", team.ID).Error)
+
+	now := time.Now()
+	createSessionTestSession(t, coach.ID, member.ID, now.Add(-48*time.Hour))
+	createSessionTestSession(t, coach.ID, member.ID, now.Add(48*time.Hour))
+	createSessionTestSession(t, coach.ID, outsider.ID, now)
+
+	t.Run("should list sessions for a person as coach or coachee", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/persons/%d/sessions", coach.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response []models.CoachingSession
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response, 3)
+		assert.True(t, response[0].ScheduledAt.After(response[1].ScheduledAt))
+	})
+
+	t.Run("should list sessions for team members", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/teams/%d/sessions", team.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response []models.CoachingSession
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response, 2)
+	})
+
+	t.Run("should filter by date window", func(t *testing.T) {
+		from := now.Add(time.Hour).UTC().Format(time.RFC3339)
+		w := makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/persons/%d/sessions?from=%s", member.ID, from), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response []models.CoachingSession
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response, 1)
+	})
+
+	t.Run("should reject invalid date", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", "/api/v1/sessions?from=yesterday", nil)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestSessionFeedbacks(t *testing.T) {
+	router := setupSessionTestRouter()
+	coach := createTestPerson(t, "Coach", "session.coach4@example.com", "")
+	coachee := createTestPerson(t, "Coachee", "session.coachee4@example.com", "")
+	session := createSessionTestSession(t, coach.ID, coachee.ID, time.Now())
+	feedback := models.Feedback{Content: "Improve estimates", TargetType: "person", TargetID: coachee.ID, TargetName: coachee.Name}
+	assert.NoError(t, database.GetDB().Create(&feedback).Error)
+
+	w := makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/sessions/%d/feedbacks", session.ID), models.AttachFeedbackRequest{FeedbackID: feedback.ID})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.CoachingSession
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Feedbacks, 1)
+	assert.Equal(t, "Improve estimates", response.Feedbacks[0].Content)
+
+	w = makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/sessions/%d/feedbacks", session.ID), models.AttachFeedbackRequest{FeedbackID: 999})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = makeRequest(t, router, "DELETE", fmt.Sprintf("/api/v1/sessions/%d/feedbacks/%d", session.ID, feedback.ID), nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/sessions/%d", session.ID), nil)
+	response = models.CoachingSession{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Empty(t, response.Feedbacks)
+}
+
+func createSessionTestSession(t *testing.T, coachID, coacheeID uint, scheduledAt time.Time) models.CoachingSession {
+	session := models.CoachingSession{
+		CoachID:     coachID,
+		CoacheeID:   coacheeID,
+		ScheduledAt: scheduledAt,
+		Status:      models.SessionStatusScheduled,
+	}
+
+	err := database.GetDB().Create(&session).Error
+	assert.NoError(t, err)
+
+	return session
+}
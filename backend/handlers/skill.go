@@ -0,0 +1,347 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func CreateSkill(c *gin.Context) {
+	var req models.CreateSkillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	skill := models.Skill{
+		Name:        req.Name,
+		Category:    req.Category,
+		Description: req.Description,
+	}
+
+	if err := database.GetDB().Create(&skill).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create skill"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, skill)
+}
+
+func GetSkills(c *gin.Context) {
+	query := database.GetDB().Order("category, name")
+	if category := c.Query("category"); category != "" {
+		query = query.Where("category = ?", category)
+	}
+
+	var skills []models.Skill
+	if err := query.Find(&skills).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch skills"})
+		return
+	}
+
+	c.JSON(http.StatusOK, skills)
+}
+
+func GetSkill(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid skill ID"})
+		return
+	}
+
+	var skill models.Skill
+	if err := database.GetDB().First(&skill, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Skill not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, skill)
+}
+
+func UpdateSkill(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid skill ID"})
+		return
+	}
+
+	var req models.CreateSkillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var skill models.Skill
+	if err := database.GetDB().First(&skill, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Skill not found"})
+		return
+	}
+
+	skill.Name = req.Name
+	skill.Category = req.Category
+	skill.Description = req.Description
+
+	if err := database.GetDB().Save(&skill).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update skill"})
+		return
+	}
+
+	c.JSON(http.StatusOK, skill)
+}
+
+func DeleteSkill(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid skill ID"})
+		return
+	}
+
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("skill_id = ?", id).Delete(&models.SkillAssessment{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Skill{}, id).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete skill"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Skill deleted successfully"})
+}
+
+func AssessSkill(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid person ID"})
+		return
+	}
+
+	var req models.AssessSkillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Level < models.SkillLevelMin || req.Level > models.SkillLevelMax {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("level must be between %d and %d", models.SkillLevelMin, models.SkillLevelMax)})
+		return
+	}
+
+	var person models.Person
+	if err := database.GetDB().First(&person, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Person not found"})
+		return
+	}
+
+	var skill models.Skill
+	if err := database.GetDB().First(&skill, req.SkillID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Skill not found"})
+		return
+	}
+
+	assessorID := req.AssessorID
+	switch req.Source {
+	case models.AssessmentSourceSelf:
+		if assessorID != nil && *assessorID != person.ID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Self assessments must be made by the person themselves"})
+			return
+		}
+		assessorID = &person.ID
+	case models.AssessmentSourceManager:
+		if person.ManagerID == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Person has no manager to assess them"})
+			return
+		}
+		if assessorID != nil && *assessorID != *person.ManagerID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only the person's manager can make a manager assessment"})
+			return
+		}
+		assessorID = person.ManagerID
+	}
+
+	assessment := models.SkillAssessment{
+		PersonID:   person.ID,
+		SkillID:    skill.ID,
+		Level:      req.Level,
+		Source:     req.Source,
+		AssessorID: assessorID,
+		Note:       req.Note,
+	}
+
+	if err := database.GetDB().Create(&assessment).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record assessment"})
+		return
+	}
+
+	assessment.Skill = &skill
+	c.JSON(http.StatusCreated, assessment)
+}
+
+func GetPersonSkills(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid person ID"})
+		return
+	}
+
+	var person models.Person
+	if err := database.GetDB().First(&person, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Person not found"})
+		return
+	}
+
+	var assessments []models.SkillAssessment
+	if err := database.GetDB().Preload("Skill").Where("person_id = ?", person.ID).
+		Order("created_at, id").Find(&assessments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch skills"})
+		return
+	}
+
+	skills := []models.PersonSkill{}
+	index := make(map[uint]int)
+	for _, a := range assessments {
+		idx, ok := index[a.SkillID]
+		if !ok {
+			idx = len(skills)
+			index[a.SkillID] = idx
+			skills = append(skills, models.PersonSkill{Skill: *a.Skill})
+		}
+
+		level, assessedAt := a.Level, a.CreatedAt
+		if a.Source == models.AssessmentSourceManager {
+			skills[idx].ManagerLevel = &level
+			skills[idx].ManagerAssessedAt = &assessedAt
+		} else {
+			skills[idx].SelfLevel = &level
+			skills[idx].SelfAssessedAt = &assessedAt
+		}
+	}
+
+	c.JSON(http.StatusOK, skills)
+}
+
+func GetPersonSkillHistory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid person ID"})
+		return
+	}
+
+	skillID, err := strconv.Atoi(c.Param("skillId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid skill ID"})
+		return
+	}
+
+	var assessments []models.SkillAssessment
+	if err := database.GetDB().Where("person_id = ? AND skill_id = ?", id, skillID).
+		Order("created_at, id").Find(&assessments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch skill history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, assessments)
+}
+
+func GetTeamSkills(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	source := c.DefaultQuery("source", "effective")
+	if source != "effective" && source != models.AssessmentSourceSelf && source != models.AssessmentSourceManager {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "source must be effective, self or manager"})
+		return
+	}
+
+	var team models.Team
+	if err := database.GetDB().Preload("Members", func(db *gorm.DB) *gorm.DB { return db.Order("name") }).First(&team, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+		return
+	}
+
+	memberIDs := make([]uint, 0, len(team.Members))
+	for _, m := range team.Members {
+		memberIDs = append(memberIDs, m.ID)
+	}
+
+	var assessments []models.SkillAssessment
+	if err := database.GetDB().Preload("Skill").Where("person_id IN ?", append([]uint{0}, memberIDs...)).
+		Order("created_at, id").Find(&assessments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch team skills"})
+		return
+	}
+
+	c.JSON(http.StatusOK, buildSkillHeatmap(team, assessments, source))
+}
+
+func buildSkillHeatmap(team models.Team, assessments []models.SkillAssessment, source string) models.SkillHeatmap {
+	type key struct{ person, skill uint }
+	self := make(map[key]int)
+	manager := make(map[key]int)
+	skills := make(map[uint]models.Skill)
+	var skillOrder []uint
+
+	for _, a := range assessments {
+		if _, ok := skills[a.SkillID]; !ok {
+			skills[a.SkillID] = *a.Skill
+			skillOrder = append(skillOrder, a.SkillID)
+		}
+		k := key{a.PersonID, a.SkillID}
+		if a.Source == models.AssessmentSourceManager {
+			manager[k] = a.Level
+		} else {
+			self[k] = a.Level
+		}
+	}
+
+	heatmap := models.SkillHeatmap{
+		TeamID:  team.ID,
+		Source:  source,
+		Skills:  []models.SkillHeatmapSkill{},
+		Members: []models.SkillHeatmapMember{},
+	}
+
+	totals := make(map[uint][2]int)
+	for _, m := range team.Members {
+		member := models.SkillHeatmapMember{PersonID: m.ID, Name: m.Name, Levels: make(map[uint]int)}
+		for _, skillID := range skillOrder {
+			k := key{m.ID, skillID}
+			level, ok := 0, false
+			switch source {
+			case models.AssessmentSourceSelf:
+				level, ok = self[k]
+			case models.AssessmentSourceManager:
+				level, ok = manager[k]
+			default:
+				if level, ok = manager[k]; !ok {
+					level, ok = self[k]
+				}
+			}
+			if !ok {
+				continue
+			}
+			member.Levels[skillID] = level
+			total := totals[skillID]
+			totals[skillID] = [2]int{total[0] + level, total[1] + 1}
+		}
+		heatmap.Members = append(heatmap.Members, member)
+	}
+
+	for _, skillID := range skillOrder {
+		entry := models.SkillHeatmapSkill{Skill: skills[skillID], Rated: totals[skillID][1]}
+		if entry.Rated > 0 {
+			entry.Average = float64(totals[skillID][0]) / float64(entry.Rated)
+		}
+		heatmap.Skills = append(heatmap.Skills, entry)
+	}
+
+	return heatmap
+}
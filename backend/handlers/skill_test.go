@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupSkillTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic("Failed to connect to test database")
+	}
+
+	err = database.Migrate(db)
+	if err != nil {
+		panic("Failed to migrate test database")
+	}
+
+	database.DB = db
+
+	r := gin.New()
+
+	api := r.Group("/api/v1")
+	skills := api.Group("/skills")
+	{
+		skills.POST("", CreateSkill)
+		skills.GET("", GetSkills)
+		skills.GET("/:id", GetSkill)
+		skills.PUT("/:id", UpdateSkill)
+		skills.DELETE("/:id", DeleteSkill)
+	}
+	api.GET("/persons/:id/skills", GetPersonSkills)
+	api.POST("/persons/:id/skills", AssessSkill)
+	api.GET("/persons/:id/skills/:skillId/history", GetPersonSkillHistory)
+	api.GET("/teams/:id/skills", GetTeamSkills)
+
+	return r
+}
+
+func TestAssessSkill(t *testing.T) {
+	router := setupSkillTestRouter()
+	manager := createTestPerson(t, "Manager", "skill.manager1@example.com", "")
+	report := createTestPerson(t, "Report", "skill.report1@example.com", "")
+	assert.NoError(t, database.GetDB().Model(&report).Update("manager_id", manager.ID).Error)
+	skill := createSkillTestSkill(t, "Go")
+
+	t.Run("should record a self assessment", func(t *testing.T) {
+		reqBody := models.AssessSkillRequest{SkillID: skill.ID, Level: 3, Source: models.AssessmentSourceSelf}
+
+		w := makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/persons/%d/skills", report.ID), reqBody)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response models.SkillAssessment
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, report.ID, *response.AssessorID)
+	})
+
+	t.Run("should attribute manager assessment to the manager", func(t *testing.T) {
+		reqBody := models.AssessSkillRequest{SkillID: skill.ID, Level: 4, Source: models.AssessmentSourceManager}
+
+		w := makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/persons/%d/skills", report.ID), reqBody)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response models.SkillAssessment
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, manager.ID, *response.AssessorID)
+	})
+
+	t.Run("should reject manager assessment from someone else", func(t *testing.T) {
+		reqBody := models.AssessSkillRequest{SkillID: skill.ID, Level: 4, Source: models.AssessmentSourceManager, AssessorID: &report.ID}
+
+		w := makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/persons/%d/skills", report.ID), reqBody)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("should reject manager assessment for person without manager", func(t *testing.T) {
+		reqBody := models.AssessSkillRequest{SkillID: skill.ID, Level: 4, Source: models.AssessmentSourceManager}
+
+		w := makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/persons/%d/skills", manager.ID), reqBody)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should reject level out of range", func(t *testing.T) {
+		reqBody := models.AssessSkillRequest{SkillID: skill.ID, Level: 6, Source: models.AssessmentSourceSelf}
+
+		w := makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/persons/%d/skills", report.ID), reqBody)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestGetPersonSkills(t *testing.T) {
+	router := setupSkillTestRouter()
+	manager := createTestPerson(t, "Manager", "skill.manager2@example.com", "")
+	report := createTestPerson(t, "Report", "skill.report2@example.com", "")
+	assert.NoError(t, database.GetDB().Model(&report).Update("manager_id", manager.ID).Error)
+	skill := createSkillTestSkill(t, "Testing")
+
+	for _, a := range []models.AssessSkillRequest{
+		{SkillID: skill.ID, Level: 2, Source: models.AssessmentSourceSelf},
+		{SkillID: skill.ID, Level: 3, Source: models.AssessmentSourceManager},
+		{SkillID: skill.ID, Level: 4, Source: models.AssessmentSourceSelf},
+	} {
+		w := makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/persons/%d/skills", report.ID), a)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	}
+
+	t.Run("should return latest level per source", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/persons/%d/skills", report.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response []models.PersonSkill
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response, 1)
+		assert.Equal(t, 4, *response[0].SelfLevel)
+		assert.Equal(t, 3, *response[0].ManagerLevel)
+	})
+
+	t.Run("should return full history", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/persons/%d/skills/%d/history", report.ID, skill.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response []models.SkillAssessment
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response, 3)
+		assert.Equal(t, 2, response[0].Level)
+		assert.Equal(t, 4, response[2].Level)
+	})
+}
+
+func TestGetTeamSkills(t *testing.T) {
+	router := setupSkillTestRouter()
+	team := createTestTeam(t, "Platform", "")
+	lead := createTestPerson(t, "Lead", "skill.lead3@example.com", "")
+	alice := createTestPerson(t, "Alice", "skill.alice3@example.com", "")
+	bob := createTestPerson(t, "Bob", "skill.bob3@example.com", "")
+	assert.NoError(t, database.GetDB().Model(&models.Person{}).Where("id IN ?", []uint{alice.ID, bob.ID}).
+		Updates(map[string]interface{}{"team_id": team.ID, "manager_id": lead.ID}).Error)
+	skill := createSkillTestSkill(t, "Kubernetes")
+
+	for _, a := range []struct {
+		person uint
+		req    models.AssessSkillRequest
+	}{
+		{alice.ID, models.AssessSkillRequest{SkillID: skill.ID, Level: 5, Source: models.AssessmentSourceSelf}},
+		{alice.ID, models.AssessSkillRequest{SkillID: skill.ID, Level: 3, Source: models.AssessmentSourceManager}},
+		{bob.ID, models.AssessSkillRequest{SkillID: skill.ID, Level: 2, Source: models.AssessmentSourceSelf}},
+	} {
+		w := makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/persons/%d/skills", a.person), a.req)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	}
+
+	t.Run("should prefer manager levels in effective heatmap", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/teams/%d/skills", team.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var heatmap models.SkillHeatmap
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &heatmap))
+		assert.Len(t, heatmap.Members, 2)
+		assert.Equal(t, 3, heatmap.Members[0].Levels[skill.ID])
+		assert.Equal(t, 2, heatmap.Members[1].Levels[skill.ID])
+		assert.Len(t, heatmap.Skills, 1)
+		assert.Equal(t, 2.5, heatmap.Skills[0].Average)
+		assert.Equal(t, 2, heatmap.Skills[0].Rated)
+	})
+
+	t.Run("should only use self levels when asked", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/teams/%d/skills?source=self", team.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var heatmap models.SkillHeatmap
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &heatmap))
+		assert.Equal(t, 3.5, heatmap.Skills[0].Average)
+	})
+
+	t.Run("should reject unknown source", func(t *testing.T) {
+		w := makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/teams/%d/skills?source=peer", team.ID), nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func createSkillTestSkill(t *testing.T, name string) models.Skill {
+	skill := models.Skill{Name: name, Category: "engineering"}
+
+	err := database.GetDB().Create(&skill).Error
+	assert.NoError(t, err)
+
+	return skill
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"coaching-backend/events"
+	"coaching-backend/outbox"
+	"coaching-backend/stream"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	streamBuffer      = 64
+	streamReplayLimit = 500
+	streamRetryMs     = 3000
+)
+
+var streamHeartbeat = 15 * time.Second
+
+func Stream(c *gin.Context) {
+	filter, ok := parseStreamFilter(c)
+	if !ok {
+		return
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	var last uint64
+	if lastEventID != "" {
+		var err error
+		if last, err = strconv.ParseUint(lastEventID, 10, 64); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Last-Event-ID"})
+			return
+		}
+	}
+
+	live, unsubscribe := stream.Default.Subscribe(streamBuffer)
+	defer unsubscribe()
+
+	var replay []events.Event
+	if lastEventID != "" {
+		var err error
+		if replay, err = outbox.Published(uint(last), streamReplayLimit); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to replay events"})
+			return
+		}
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	w := c.Writer
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", streamRetryMs); err != nil {
+		return
+	}
+
+	for _, event := range replay {
+		last = uint64(event.Sequence)
+		if filter.Matches(event) && writeStreamEvent(w, event) != nil {
+			return
+		}
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, open := <-live:
+			if !open {
+				return
+			}
+			if event.Sequence != 0 && uint64(event.Sequence) <= last {
+				continue
+			}
+			last = uint64(event.Sequence)
+			if !filter.Matches(event) {
+				continue
+			}
+			if writeStreamEvent(w, event) != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+		}
+		w.Flush()
+	}
+}
+
+func writeStreamEvent(w io.Writer, event events.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Sequence, event.Type, data)
+	return err
+}
+
+func parseStreamFilter(c *gin.Context) (stream.Filter, bool) {
+	var filter stream.Filter
+
+	if types := c.Query("types"); types != "" {
+		for _, t := range strings.Split(types, ",") {
+			t = strings.TrimSpace(t)
+			if !events.IsKnown(t) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unknown event type %q", t)})
+				return filter, false
+			}
+			filter.Types = append(filter.Types, t)
+		}
+	}
+
+	if targetType := c.Query("target_type"); targetType != "" {
+		if targetType != "person" && targetType != "team" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "target_type must be person or team"})
+			return filter, false
+		}
+		targetID, err := strconv.Atoi(c.Query("target_id"))
+		if err != nil || targetID < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid target ID"})
+			return filter, false
+		}
+		filter.TargetType = targetType
+		filter.TargetID = uint(targetID)
+	}
+
+	if teamID := c.Query("team_id"); teamID != "" {
+		id, err := strconv.Atoi(teamID)
+		if err != nil || id < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+			return filter, false
+		}
+		filter.TeamID = uint(id)
+	}
+
+	return filter, true
+}
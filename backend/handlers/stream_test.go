@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"coaching-backend/database"
+	"coaching-backend/events"
+	"coaching-backend/models"
+	"coaching-backend/outbox"
+	"coaching-backend/stream"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type sseMessage struct {
+	ID      string
+	Event   string
+	Data    string
+	Comment string
+}
+
+func setupStreamTestServer(t *testing.T) (*gin.Engine, *httptest.Server) {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic("Failed to connect to test database")
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		panic("Failed to access test database")
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	err = database.Migrate(db)
+	if err != nil {
+		panic("Failed to migrate test database")
+	}
+
+	database.DB = db
+
+	unsubscribe := events.Subscribe(stream.Default.Handle)
+	t.Cleanup(unsubscribe)
+
+	r := gin.New()
+
+	api := r.Group("/api/v1")
+	api.POST("/feedbacks", CreateFeedback)
+	api.POST("/teams", CreateTeam)
+	api.GET("/stream", Stream)
+
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	return r, server
+}
+
+func openStream(t *testing.T, server *httptest.Server, query, lastEventID string) <-chan sseMessage {
+	req, err := http.NewRequest("GET", server.URL+"/api/v1/stream"+query, nil)
+	assert.NoError(t, err)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to open stream: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	messages := make(chan sseMessage, 16)
+	reader := bufio.NewReader(resp.Body)
+	readMessage := func() (sseMessage, bool) {
+		var msg sseMessage
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return msg, false
+			}
+			line = strings.TrimRight(line, "\n")
+			switch {
+			case line == "":
+				return msg, true
+			case strings.HasPrefix(line, ":"):
+				msg.Comment = strings.TrimSpace(line[1:])
+			case strings.HasPrefix(line, "id: "):
+				msg.ID = line[4:]
+			case strings.HasPrefix(line, "event: "):
+				msg.Event = line[7:]
+			case strings.HasPrefix(line, "data: "):
+				msg.Data = line[6:]
+			}
+		}
+	}
+
+	// The retry hint is written once the client is subscribed.
+	if _, ok := readMessage(); !ok {
+		t.Fatal("Stream closed before it was ready")
+	}
+
+	go func() {
+		defer close(messages)
+		for {
+			msg, ok := readMessage()
+			if !ok {
+				return
+			}
+			messages <- msg
+		}
+	}()
+
+	return messages
+}
+
+func nextStreamMessage(t *testing.T, messages <-chan sseMessage) sseMessage {
+	select {
+	case msg := <-messages:
+		return msg
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for stream message")
+	}
+	return sseMessage{}
+}
+
+func flushOutbox(t *testing.T) {
+	_, err := outbox.Flush()
+	assert.NoError(t, err)
+}
+
+func TestStream(t *testing.T) {
+	router, server := setupStreamTestServer(t)
+	team := createTestTeam(t, "Stream Team", "")
+	member := createTestPerson(t, "Stream Member", "stream.member@example.com", "")
+	database.GetDB().Model(&member).Update("team_id", team.ID)
+	outsider := createTestPerson(t, "Stream Outsider", "stream.outsider@example.com", "")
+
+	postFeedback := func(targetType string, targetID uint, content string) {
+		w := makeRequest(t, router, "POST", "/api/v1/feedbacks", models.CreateFeedbackRequest{Content: content, TargetType: targetType, TargetID: targetID})
+		assert.Equal(t, http.StatusCreated, w.Code)
+		flushOutbox(t)
+	}
+
+	t.Run("should push events matching the team filter", func(t *testing.T) {
+		messages := openStream(t, server, "?team_id="+fmt.Sprint(team.ID), "")
+
+		postFeedback("person", outsider.ID, "Not for this team")
+		postFeedback("person", member.ID, "Great pairing session")
+
+		msg := nextStreamMessage(t, messages)
+		assert.Equal(t, events.FeedbackCreated, msg.Event)
+		assert.NotEmpty(t, msg.ID)
+
+		var event struct {
+			Type string          `json:"type"`
+			Data models.Feedback `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal([]byte(msg.Data), &event))
+		assert.Equal(t, "Great pairing session", event.Data.Content)
+	})
+
+	t.Run("should filter by target and type", func(t *testing.T) {
+		messages := openStream(t, server, "?target_type=person&target_id="+fmt.Sprint(outsider.ID)+"&types=feedback.created", "")
+
+		w := makeRequest(t, router, "POST", "/api/v1/teams", models.CreateTeamRequest{Name: "Ignored Team"})
+		assert.Equal(t, http.StatusCreated, w.Code)
+		flushOutbox(t)
+		postFeedback("person", outsider.ID, "Clear write-up")
+
+		msg := nextStreamMessage(t, messages)
+		assert.Equal(t, events.FeedbackCreated, msg.Event)
+		assert.Contains(t, msg.Data, "Clear write-up")
+	})
+
+	t.Run("should resume after Last-Event-ID", func(t *testing.T) {
+		var rows []models.OutboxEvent
+		database.GetDB().Where("type = ?", events.FeedbackCreated).Order("id").Find(&rows)
+		assert.True(t, len(rows) >= 3)
+
+		messages := openStream(t, server, "?types=feedback.created", fmt.Sprint(rows[0].ID))
+
+		for _, row := range rows[1:] {
+			msg := nextStreamMessage(t, messages)
+			assert.Equal(t, fmt.Sprint(row.ID), msg.ID)
+		}
+
+		postFeedback("team", team.ID, "Live after replay")
+		msg := nextStreamMessage(t, messages)
+		assert.Contains(t, msg.Data, "Live after replay")
+	})
+
+	t.Run("should send heartbeats", func(t *testing.T) {
+		previous := streamHeartbeat
+		streamHeartbeat = 20 * time.Millisecond
+		defer func() { streamHeartbeat = previous }()
+
+		messages := openStream(t, server, "", "")
+
+		msg := nextStreamMessage(t, messages)
+		assert.Equal(t, "keepalive", msg.Comment)
+	})
+}
+
+func TestStreamValidation(t *testing.T) {
+	router, _ := setupStreamTestServer(t)
+
+	for _, query := range []string{"?types=feedback.liked", "?target_type=goal&target_id=1", "?target_type=person", "?team_id=abc"} {
+		w := makeRequest(t, router, "GET", "/api/v1/stream"+query, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+
+	req, _ := http.NewRequest("GET", "/api/v1/stream", nil)
+	req.Header.Set("Last-Event-ID", "not-a-number")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
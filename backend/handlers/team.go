@@ -1,11 +1,15 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"coaching-backend/database"
+	"coaching-backend/events"
 	"coaching-backend/models"
+	"coaching-backend/outbox"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 func CreateTeam(c *gin.Context) {
@@ -20,11 +24,18 @@ func CreateTeam(c *gin.Context) {
 		Logo: req.Logo,
 	}
 
-	if err := database.GetDB().Create(&team).Error; err != nil {
+	err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&team).Error; err != nil {
+			return err
+		}
+		return outbox.Add(tx, events.TeamCreated, team)
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create team"})
 		return
 	}
 
+	outbox.Notify()
 	c.JSON(http.StatusCreated, team)
 }
 
@@ -61,7 +72,7 @@ func UpdateTeam(c *gin.Context) {
 		return
 	}
 
-	var req models.CreateTeamRequest
+	var req models.UpdateTeamRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -73,14 +84,27 @@ func UpdateTeam(c *gin.Context) {
 		return
 	}
 
+	previousLogo := team.Logo
 	team.Name = req.Name
-	team.Logo = req.Logo
+	if req.Logo != nil {
+		team.Logo = *req.Logo
+	}
 
-	if err := database.GetDB().Save(&team).Error; err != nil {
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&team).Error; err != nil {
+			return err
+		}
+		return outbox.Add(tx, events.TeamUpdated, team)
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update team"})
 		return
 	}
 
+	outbox.Notify()
+	if team.Logo != previousLogo {
+		deleteMedia(teamMediaPrefix(team.ID), previousLogo)
+	}
 	c.JSON(http.StatusOK, team)
 }
 
@@ -91,10 +115,45 @@ func DeleteTeam(c *gin.Context) {
 		return
 	}
 
-	if err := database.GetDB().Delete(&models.Team{}, id).Error; err != nil {
+	var logo string
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		var team models.Team
+		if err := tx.Select("id, logo").First(&team, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		logo = team.Logo
+
+		result := tx.Delete(&models.Team{}, id)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return outbox.Add(tx, events.TeamDeleted, gin.H{"id": id})
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete team"})
 		return
 	}
 
+	outbox.Notify()
+	deleteMedia(teamMediaPrefix(uint(id)), logo)
 	c.JSON(http.StatusOK, gin.H{"message": "Team deleted successfully"})
 }
+
+func loadTeam(c *gin.Context) (models.Team, bool) {
+	var team models.Team
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return team, false
+	}
+
+	if err := database.GetDB().First(&team, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+		return team, false
+	}
+	return team, true
+}
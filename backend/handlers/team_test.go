@@ -24,7 +24,7 @@ func setupTeamTestRouter() *gin.Engine {
 		panic("Failed to connect to test database")
 	}
 
-	err = db.AutoMigrate(&models.Person{}, &models.Team{}, &models.Feedback{})
+	err = database.Migrate(db)
 	if err != nil {
 		panic("Failed to migrate test database")
 	}
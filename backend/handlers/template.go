@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func CreateTemplate(c *gin.Context) {
+	var req models.CreateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	questions, err := buildTemplateQuestions(req.Questions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	for _, q := range questions {
+		if q.ID != 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Question IDs can only be given when updating a template"})
+			return
+		}
+	}
+	if !templateNameAvailable(c, req.Name, 0) {
+		return
+	}
+
+	template := models.FeedbackTemplate{
+		Name:        req.Name,
+		Description: req.Description,
+		Questions:   questions,
+	}
+
+	if err := database.GetDB().Create(&template).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create template"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+func GetTemplates(c *gin.Context) {
+	var templates []models.FeedbackTemplate
+	if err := database.GetDB().Preload("Questions", orderQuestions).Order("name").Find(&templates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch templates"})
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+func GetTemplate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+
+	var template models.FeedbackTemplate
+	if err := database.GetDB().Preload("Questions", orderQuestions).First(&template, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+func UpdateTemplate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+
+	var req models.CreateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	questions, err := buildTemplateQuestions(req.Questions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var template models.FeedbackTemplate
+	if err := database.GetDB().Preload("Questions").First(&template, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+		return
+	}
+	if !templateNameAvailable(c, req.Name, template.ID) {
+		return
+	}
+
+	// Questions keep their IDs, which open feedback requests and review cycles
+	// answer against. Questions left out of the request are removed.
+	existing := make(map[uint]bool, len(template.Questions))
+	for _, q := range template.Questions {
+		existing[q.ID] = true
+	}
+	for i := range questions {
+		if questions[i].ID != 0 && !existing[questions[i].ID] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("question %d does not belong to template %q", questions[i].ID, template.Name)})
+			return
+		}
+		delete(existing, questions[i].ID)
+		questions[i].TemplateID = template.ID
+	}
+	removed := make([]uint, 0, len(existing))
+	for id := range existing {
+		removed = append(removed, id)
+	}
+
+	template.Name = req.Name
+	template.Description = req.Description
+	template.Questions = nil
+
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&template).Error; err != nil {
+			return err
+		}
+		if len(removed) > 0 {
+			if err := tx.Where("template_id = ? AND id IN ?", template.ID, removed).Delete(&models.TemplateQuestion{}).Error; err != nil {
+				return err
+			}
+		}
+		for i := range questions {
+			if err := tx.Save(&questions[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update template"})
+		return
+	}
+
+	template.Questions = questions
+	c.JSON(http.StatusOK, template)
+}
+
+func DeleteTemplate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("template_id = ?", id).Delete(&models.TemplateQuestion{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.FeedbackTemplate{}, id).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete template"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Template deleted successfully"})
+}
+
+func templateNameAvailable(c *gin.Context, name string, id uint) bool {
+	var count int64
+	if err := database.GetDB().Model(&models.FeedbackTemplate{}).Where("name = ? AND id <> ?", name, id).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check template name"})
+		return false
+	}
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("A template named %q already exists", name)})
+		return false
+	}
+	return true
+}
+
+func orderQuestions(db *gorm.DB) *gorm.DB {
+	return db.Order("position")
+}
+
+func buildTemplateQuestions(reqs []models.TemplateQuestionRequest) ([]models.TemplateQuestion, error) {
+	questions := make([]models.TemplateQuestion, 0, len(reqs))
+	for i, q := range reqs {
+		if q.AnswerType == models.AnswerTypeChoice && len(q.Options) == 0 {
+			return nil, fmt.Errorf("question %d: choice questions need at least one option", i+1)
+		}
+		if q.AnswerType != models.AnswerTypeChoice && len(q.Options) > 0 {
+			return nil, fmt.Errorf("question %d: options are only allowed on choice questions", i+1)
+		}
+		questions = append(questions, models.TemplateQuestion{
+			ID:         q.ID,
+			Position:   i + 1,
+			Prompt:     q.Prompt,
+			AnswerType: q.AnswerType,
+			Required:   q.Required,
+			Options:    q.Options,
+		})
+	}
+	return questions, nil
+}
+
+func buildTemplateAnswers(template models.FeedbackTemplate, reqs []models.AnswerRequest) ([]models.FeedbackAnswer, error) {
+	values := make(map[uint]string, len(reqs))
+	for _, a := range reqs {
+		if _, dup := values[a.QuestionID]; dup {
+			return nil, fmt.Errorf("question %d answered more than once", a.QuestionID)
+		}
+		values[a.QuestionID] = strings.TrimSpace(a.Value)
+	}
+
+	answers := make([]models.FeedbackAnswer, 0, len(template.Questions))
+	for _, q := range template.Questions {
+		value, ok := values[q.ID]
+		delete(values, q.ID)
+		if !ok || value == "" {
+			if q.Required {
+				return nil, fmt.Errorf("question %q is required", q.Prompt)
+			}
+			continue
+		}
+		if err := validateAnswer(q, value); err != nil {
+			return nil, err
+		}
+		answers = append(answers, models.FeedbackAnswer{
+			QuestionID: q.ID,
+			Prompt:     q.Prompt,
+			AnswerType: q.AnswerType,
+			Value:      value,
+		})
+	}
+
+	for id := range values {
+		return nil, fmt.Errorf("question %d does not belong to template %q", id, template.Name)
+	}
+
+	return answers, nil
+}
+
+func validateAnswer(q models.TemplateQuestion, value string) error {
+	switch q.AnswerType {
+	case models.AnswerTypeRating:
+		rating, err := strconv.Atoi(value)
+		if err != nil || rating < models.RatingMin || rating > models.RatingMax {
+			return fmt.Errorf("question %q expects a rating between %d and %d", q.Prompt, models.RatingMin, models.RatingMax)
+		}
+	case models.AnswerTypeYesNo:
+		if value != "yes" && value != "no" {
+			return fmt.Errorf("question %q expects yes or no", q.Prompt)
+		}
+	case models.AnswerTypeChoice:
+		for _, option := range q.Options {
+			if option == value {
+				return nil
+			}
+		}
+		return fmt.Errorf("question %q expects one of: %s", q.Prompt, strings.Join(q.Options, ", "))
+	}
+	return nil
+}
+
+func renderFeedbackContent(content string, answers []models.FeedbackAnswer) string {
+	var b strings.Builder
+	if content = strings.TrimSpace(content); content != "" {
+		b.WriteString(content)
+	}
+	for _, a := range answers {
+		if b.Len() > 0 {
+			b.WriteString("\n\n")
+		}
+		b.WriteString(a.Prompt)
+		b.WriteString(":\n")
+		b.WriteString(a.Value)
+	}
+	return b.String()
+}
@@ -0,0 +1,404 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTemplateTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic("Failed to connect to test database")
+	}
+
+	err = database.Migrate(db)
+	if err != nil {
+		panic("Failed to migrate test database")
+	}
+
+	database.DB = db
+
+	r := gin.New()
+
+	api := r.Group("/api/v1")
+	templates := api.Group("/feedback-templates")
+	{
+		templates.POST("", CreateTemplate)
+		templates.GET("", GetTemplates)
+		templates.GET("/:id", GetTemplate)
+		templates.PUT("/:id", UpdateTemplate)
+		templates.DELETE("/:id", DeleteTemplate)
+	}
+	api.POST("/feedbacks", CreateFeedback)
+	api.GET("/feedbacks/:id", GetFeedback)
+	api.PUT("/feedbacks/:id", UpdateFeedback)
+
+	return r
+}
+
+func TestCreateTemplate(t *testing.T) {
+	router := setupTemplateTestRouter()
+
+	t.Run("should create template with ordered questions", func(t *testing.T) {
+		reqBody := models.CreateTemplateRequest{
+			Name: "Quarterly check-in",
+			Questions: []models.TemplateQuestionRequest{
+				{Prompt: "What went well?", AnswerType: "text", Required: true},
+				{Prompt: "Overall rating", AnswerType: "rating", Required: true},
+				{Prompt: "Focus area", AnswerType: "choice", Options: []string{"delivery", "communication"}},
+			},
+		}
+
+		w := makeRequest(t, router, "POST", "/api/v1/feedback-templates", reqBody)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response models.FeedbackTemplate
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.NotZero(t, response.ID)
+		assert.Len(t, response.Questions, 3)
+		assert.Equal(t, 1, response.Questions[0].Position)
+		assert.Equal(t, 3, response.Questions[2].Position)
+		assert.Equal(t, []string{"delivery", "communication"}, response.Questions[2].Options)
+	})
+
+	t.Run("should reject choice question without options", func(t *testing.T) {
+		reqBody := models.CreateTemplateRequest{
+			Name: "Broken",
+			Questions: []models.TemplateQuestionRequest{
+				{Prompt: "Pick one", AnswerType: "choice"},
+			},
+		}
+
+		w := makeRequest(t, router, "POST", "/api/v1/feedback-templates", reqBody)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should reject unknown answer type", func(t *testing.T) {
+		reqBody := models.CreateTemplateRequest{
+			Name: "Broken",
+			Questions: []models.TemplateQuestionRequest{
+				{Prompt: "Draw it", AnswerType: "drawing"},
+			},
+		}
+
+		w := makeRequest(t, router, "POST", "/api/v1/feedback-templates", reqBody)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should reject a duplicate name", func(t *testing.T) {
+		reqBody := models.CreateTemplateRequest{
+			Name: "Quarterly check-in",
+			Questions: []models.TemplateQuestionRequest{
+				{Prompt: "Anything", AnswerType: "text"},
+			},
+		}
+
+		w := makeRequest(t, router, "POST", "/api/v1/feedback-templates", reqBody)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestUpdateTemplate(t *testing.T) {
+	router := setupTemplateTestRouter()
+
+	t.Run("should replace questions", func(t *testing.T) {
+		template := createTemplateTestTemplate(t)
+
+		reqBody := models.CreateTemplateRequest{
+			Name: "SBI v2",
+			Questions: []models.TemplateQuestionRequest{
+				{Prompt: "Situation", AnswerType: "text", Required: true},
+			},
+		}
+
+		w := makeRequest(t, router, "PUT", fmt.Sprintf("/api/v1/feedback-templates/%d", template.ID), reqBody)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/feedback-templates/%d", template.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response models.FeedbackTemplate
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "SBI v2", response.Name)
+		assert.Len(t, response.Questions, 1)
+	})
+
+	t.Run("should keep the IDs of questions it updates", func(t *testing.T) {
+		template := createTemplateTestTemplate(t)
+		situation, rating := template.Questions[0], template.Questions[2]
+
+		reqBody := models.CreateTemplateRequest{
+			Name: template.Name,
+			Questions: []models.TemplateQuestionRequest{
+				{ID: rating.ID, Prompt: "Overall rating", AnswerType: "rating", Required: true},
+				{ID: situation.ID, Prompt: "Situation", AnswerType: "text", Required: true},
+				{Prompt: "Impact", AnswerType: "text"},
+			},
+		}
+
+		w := makeRequest(t, router, "PUT", fmt.Sprintf("/api/v1/feedback-templates/%d", template.ID), reqBody)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/feedback-templates/%d", template.ID), nil)
+		var response models.FeedbackTemplate
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Questions, 3)
+		assert.Equal(t, rating.ID, response.Questions[0].ID)
+		assert.Equal(t, "Overall rating", response.Questions[0].Prompt)
+		assert.True(t, response.Questions[0].Required)
+		assert.Equal(t, situation.ID, response.Questions[1].ID)
+		assert.Equal(t, 2, response.Questions[1].Position)
+		assert.Equal(t, "Impact", response.Questions[2].Prompt)
+
+		var behavior int64
+		database.GetDB().Model(&models.TemplateQuestion{}).Where("id = ?", template.Questions[1].ID).Count(&behavior)
+		assert.Zero(t, behavior)
+	})
+
+	t.Run("should reject questions of another template", func(t *testing.T) {
+		template := createTemplateTestTemplate(t)
+		other := models.FeedbackTemplate{
+			Name:      "Other template",
+			Questions: []models.TemplateQuestion{{Position: 1, Prompt: "Anything", AnswerType: models.AnswerTypeText}},
+		}
+		assert.NoError(t, database.GetDB().Create(&other).Error)
+
+		reqBody := models.CreateTemplateRequest{
+			Name: template.Name,
+			Questions: []models.TemplateQuestionRequest{
+				{ID: other.Questions[0].ID, Prompt: "Situation", AnswerType: "text"},
+			},
+		}
+
+		w := makeRequest(t, router, "PUT", fmt.Sprintf("/api/v1/feedback-templates/%d", template.ID), reqBody)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should reject a name used by another template", func(t *testing.T) {
+		template := createTemplateTestTemplate(t)
+
+		reqBody := models.CreateTemplateRequest{
+			Name: "Other template",
+			Questions: []models.TemplateQuestionRequest{
+				{Prompt: "Situation", AnswerType: "text"},
+			},
+		}
+
+		w := makeRequest(t, router, "PUT", fmt.Sprintf("/api/v1/feedback-templates/%d", template.ID), reqBody)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("should return error for non-existent template", func(t *testing.T) {
+		reqBody := models.CreateTemplateRequest{
+			Name: "Missing",
+			Questions: []models.TemplateQuestionRequest{
+				{Prompt: "Anything", AnswerType: "text"},
+			},
+		}
+
+		w := makeRequest(t, router, "PUT", "/api/v1/feedback-templates/999", reqBody)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestDeleteTemplate(t *testing.T) {
+	router := setupTemplateTestRouter()
+
+	template := createTemplateTestTemplate(t)
+
+	w := makeRequest(t, router, "DELETE", fmt.Sprintf("/api/v1/feedback-templates/%d", template.ID), nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/feedback-templates/%d", template.ID), nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCreateFeedbackWithTemplate(t *testing.T) {
+	router := setupTemplateTestRouter()
+	template := createTemplateTestTemplate(t)
+	person := createTestPerson(t, "Template Target", "template.target@example.com", "")
+
+	t.Run("should store answers and render content", func(t *testing.T) {
+		reqBody := models.CreateFeedbackRequest{
+			TargetType: "person",
+			TargetID:   person.ID,
+			TemplateID: &template.ID,
+			Answers: []models.AnswerRequest{
+				{QuestionID: template.Questions[0].ID, Value: "Sprint demo"},
+				{QuestionID: template.Questions[1].ID, Value: "Explained trade-offs clearly"},
+				{QuestionID: template.Questions[2].ID, Value: "4"},
+			},
+		}
+
+		w := makeRequest(t, router, "POST", "/api/v1/feedbacks", reqBody)
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var created models.Feedback
+		err := json.Unmarshal(w.Body.Bytes(), &created)
+		assert.NoError(t, err)
+		assert.Equal(t, "Situation:\nSprint demo\n\nBehavior:\nExplained trade-offs clearly\n\nRating:\n4", created.Content)
+
+		w = makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/feedbacks/%d", created.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var fetched models.Feedback
+		err = json.Unmarshal(w.Body.Bytes(), &fetched)
+		assert.NoError(t, err)
+		assert.Equal(t, template.ID, *fetched.TemplateID)
+		assert.Len(t, fetched.Answers, 3)
+	})
+
+	t.Run("should reject missing required answer", func(t *testing.T) {
+		reqBody := models.CreateFeedbackRequest{
+			TargetType: "person",
+			TargetID:   person.ID,
+			TemplateID: &template.ID,
+			Answers: []models.AnswerRequest{
+				{QuestionID: template.Questions[0].ID, Value: "Sprint demo"},
+			},
+		}
+
+		w := makeRequest(t, router, "POST", "/api/v1/feedbacks", reqBody)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]string
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Contains(t, response["error"], "Behavior")
+	})
+
+	t.Run("should reject out of range rating", func(t *testing.T) {
+		reqBody := models.CreateFeedbackRequest{
+			TargetType: "person",
+			TargetID:   person.ID,
+			TemplateID: &template.ID,
+			Answers: []models.AnswerRequest{
+				{QuestionID: template.Questions[0].ID, Value: "Sprint demo"},
+				{QuestionID: template.Questions[1].ID, Value: "Explained trade-offs clearly"},
+				{QuestionID: template.Questions[2].ID, Value: "9"},
+			},
+		}
+
+		w := makeRequest(t, router, "POST", "/api/v1/feedbacks", reqBody)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should reject answers without template", func(t *testing.T) {
+		reqBody := models.CreateFeedbackRequest{
+			Content:    "Plain feedback",
+			TargetType: "person",
+			TargetID:   person.ID,
+			Answers: []models.AnswerRequest{
+				{QuestionID: template.Questions[0].ID, Value: "Sprint demo"},
+			},
+		}
+
+		w := makeRequest(t, router, "POST", "/api/v1/feedbacks", reqBody)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should return error for non-existent template", func(t *testing.T) {
+		missing := uint(999)
+		reqBody := models.CreateFeedbackRequest{
+			TargetType: "person",
+			TargetID:   person.ID,
+			TemplateID: &missing,
+		}
+
+		w := makeRequest(t, router, "POST", "/api/v1/feedbacks", reqBody)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestUpdateFeedbackWithTemplate(t *testing.T) {
+	router := setupTemplateTestRouter()
+	template := createTemplateTestTemplate(t)
+	person := createTestPerson(t, "Template Editee", "template.editee@example.com", "")
+
+	w := makeRequest(t, router, "POST", "/api/v1/feedbacks", models.CreateFeedbackRequest{
+		TargetType: "person",
+		TargetID:   person.ID,
+		TemplateID: &template.ID,
+		Answers: []models.AnswerRequest{
+			{QuestionID: template.Questions[0].ID, Value: "Sprint demo"},
+			{QuestionID: template.Questions[1].ID, Value: "Explained trade-offs clearly"},
+		},
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var created models.Feedback
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	url := fmt.Sprintf("/api/v1/feedbacks/%d", created.ID)
+
+	t.Run("should re-render content from new answers", func(t *testing.T) {
+		w := makeRequest(t, router, "PUT", url, models.UpdateFeedbackRequest{
+			Content: "Follow-up",
+			Answers: []models.AnswerRequest{
+				{QuestionID: template.Questions[0].ID, Value: "Planning"},
+				{QuestionID: template.Questions[1].ID, Value: "Kept scope tight"},
+				{QuestionID: template.Questions[2].ID, Value: "5"},
+			},
+		})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = makeRequest(t, router, "GET", url, nil)
+		var fetched models.Feedback
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &fetched))
+		assert.Equal(t, "Follow-up\n\nSituation:\nPlanning\n\nBehavior:\nKept scope tight\n\nRating:\n5", fetched.Content)
+		assert.Len(t, fetched.Answers, 3)
+		assert.Equal(t, "Planning", fetched.Answers[0].Value)
+	})
+
+	t.Run("should reject free text edits", func(t *testing.T) {
+		w := makeRequest(t, router, "PUT", url, models.UpdateFeedbackRequest{Content: "Rewritten"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should validate answers", func(t *testing.T) {
+		w := makeRequest(t, router, "PUT", url, models.UpdateFeedbackRequest{
+			Answers: []models.AnswerRequest{{QuestionID: template.Questions[0].ID, Value: "Only a situation"}},
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var answers int64
+		database.GetDB().Model(&models.FeedbackAnswer{}).Where("feedback_id = ?", created.ID).Count(&answers)
+		assert.Equal(t, int64(3), answers)
+	})
+}
+
+func createTemplateTestTemplate(t *testing.T) models.FeedbackTemplate {
+	template := models.FeedbackTemplate{
+		Name: "SBI " + t.Name(),
+		Questions: []models.TemplateQuestion{
+			{Position: 1, Prompt: "Situation", AnswerType: models.AnswerTypeText, Required: true},
+			{Position: 2, Prompt: "Behavior", AnswerType: models.AnswerTypeText, Required: true},
+			{Position: 3, Prompt: "Rating", AnswerType: models.AnswerTypeRating},
+		},
+	}
+
+	err := database.GetDB().Create(&template).Error
+	assert.NoError(t, err)
+
+	return template
+}
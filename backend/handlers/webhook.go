@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"coaching-backend/database"
+	"coaching-backend/events"
+	"coaching-backend/models"
+	"coaching-backend/webhooks"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const maxWebhookDeliveries = 100
+
+func CreateWebhook(c *gin.Context) {
+	var req models.WebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sub := models.WebhookSubscription{}
+	if !applyWebhookRequest(c, &sub, req) {
+		return
+	}
+
+	if err := database.GetDB().Create(&sub).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create webhook"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, sub)
+}
+
+func GetWebhooks(c *gin.Context) {
+	var subs []models.WebhookSubscription
+	if err := database.GetDB().Order("created_at desc").Find(&subs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch webhooks"})
+		return
+	}
+
+	c.JSON(http.StatusOK, subs)
+}
+
+func GetWebhook(c *gin.Context) {
+	sub, ok := loadWebhook(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, sub)
+}
+
+func UpdateWebhook(c *gin.Context) {
+	sub, ok := loadWebhook(c)
+	if !ok {
+		return
+	}
+
+	var req models.WebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !applyWebhookRequest(c, &sub, req) {
+		return
+	}
+
+	if err := database.GetDB().Save(&sub).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update webhook"})
+		return
+	}
+
+	c.JSON(http.StatusOK, sub)
+}
+
+func DeleteWebhook(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook ID"})
+		return
+	}
+
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("subscription_id = ?", id).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.WebhookSubscription{}, id).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete webhook"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted successfully"})
+}
+
+func GetWebhookDeliveries(c *gin.Context) {
+	sub, ok := loadWebhook(c)
+	if !ok {
+		return
+	}
+
+	var deliveries []models.WebhookDelivery
+	if err := database.GetDB().Where("subscription_id = ?", sub.ID).
+		Order("created_at desc").Order("id desc").Limit(maxWebhookDeliveries).Find(&deliveries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch webhook deliveries"})
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+func TestWebhook(c *gin.Context) {
+	sub, ok := loadWebhook(c)
+	if !ok {
+		return
+	}
+
+	event := events.New(webhooks.TestEvent, gin.H{"webhook_id": sub.ID, "message": "This is a test delivery"})
+	body, err := json.Marshal(event)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encode test event"})
+		return
+	}
+
+	delivery, _ := webhooks.Default.Attempt(sub, event, body, 1)
+	c.JSON(http.StatusOK, delivery)
+}
+
+func loadWebhook(c *gin.Context) (models.WebhookSubscription, bool) {
+	var sub models.WebhookSubscription
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook ID"})
+		return sub, false
+	}
+
+	if err := database.GetDB().First(&sub, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+		return sub, false
+	}
+
+	return sub, true
+}
+
+func applyWebhookRequest(c *gin.Context, sub *models.WebhookSubscription, req models.WebhookSubscriptionRequest) bool {
+	for _, e := range req.Events {
+		if e != "*" && !events.IsKnown(e) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unknown event type %q", e)})
+			return false
+		}
+	}
+
+	sub.URL = req.URL
+	sub.Secret = req.Secret
+	sub.Events = req.Events
+	if sub.Events == nil {
+		sub.Events = []string{}
+	}
+	sub.Active = req.Active == nil || *req.Active
+	return true
+}
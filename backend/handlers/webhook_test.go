@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"coaching-backend/database"
+	"coaching-backend/events"
+	"coaching-backend/models"
+	"coaching-backend/outbox"
+	"coaching-backend/webhooks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type webhookReceiver struct {
+	mu       sync.Mutex
+	payloads []events.Event
+	server   *httptest.Server
+}
+
+func newWebhookReceiver(t *testing.T, secret string) *webhookReceiver {
+	receiver := &webhookReceiver{}
+	receiver.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(webhooks.TimestampHeader), 10, 64)
+		if r.Header.Get(webhooks.SignatureHeader) != webhooks.Sign(secret, timestamp, body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var event events.Event
+		if err := json.Unmarshal(body, &event); err != nil {
+			t.Errorf("Failed to decode webhook payload: %v", err)
+		}
+		receiver.mu.Lock()
+		receiver.payloads = append(receiver.payloads, event)
+		receiver.mu.Unlock()
+	}))
+	t.Cleanup(receiver.server.Close)
+	return receiver
+}
+
+func (r *webhookReceiver) types() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var types []string
+	for _, p := range r.payloads {
+		types = append(types, p.Type)
+	}
+	return types
+}
+
+func setupWebhookTestRouter(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		panic("Failed to connect to test database")
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		panic("Failed to access test database")
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	err = database.Migrate(db)
+	if err != nil {
+		panic("Failed to migrate test database")
+	}
+
+	err = database.SeedModerationRules(db)
+	if err != nil {
+		panic("Failed to seed moderation rules")
+	}
+
+	database.DB = db
+
+	unsubscribe := events.Subscribe(webhooks.Default.Handle)
+	t.Cleanup(func() {
+		unsubscribe()
+		webhooks.Default.Wait()
+	})
+
+	r := gin.New()
+
+	api := r.Group("/api/v1")
+	api.POST("/persons", CreatePerson)
+	api.DELETE("/persons/:id", DeletePerson)
+	api.POST("/persons/:id/remove-from-team", RemoveFromTeam)
+	api.POST("/teams", CreateTeam)
+	api.POST("/assign", AssignToTeam)
+	api.POST("/feedbacks", CreateFeedback)
+	hooks := api.Group("/webhooks")
+	{
+		hooks.POST("", CreateWebhook)
+		hooks.GET("", GetWebhooks)
+		hooks.GET("/:id", GetWebhook)
+		hooks.PUT("/:id", UpdateWebhook)
+		hooks.DELETE("/:id", DeleteWebhook)
+		hooks.GET("/:id/deliveries", GetWebhookDeliveries)
+		hooks.POST("/:id/test", TestWebhook)
+	}
+
+	return r
+}
+
+func createTestWebhook(t *testing.T, router *gin.Engine, url string, eventTypes ...string) models.WebhookSubscription {
+	w := makeRequest(t, router, "POST", "/api/v1/webhooks", models.WebhookSubscriptionRequest{
+		URL:    url,
+		Secret: "webhook-test-secret",
+		Events: eventTypes,
+	})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var sub models.WebhookSubscription
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &sub))
+	return sub
+}
+
+func TestWebhookSubscriptions(t *testing.T) {
+	router := setupWebhookTestRouter(t)
+
+	t.Run("should create a subscription without exposing the secret", func(t *testing.T) {
+		w := makeRequest(t, router, "POST", "/api/v1/webhooks", models.WebhookSubscriptionRequest{
+			URL:    "https://example.com/hooks",
+			Secret: "webhook-test-secret",
+			Events: []string{events.FeedbackCreated},
+		})
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.NotContains(t, w.Body.String(), "webhook-test-secret")
+
+		var sub models.WebhookSubscription
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &sub))
+		assert.True(t, sub.Active)
+		assert.Equal(t, []string{events.FeedbackCreated}, sub.Events)
+	})
+
+	t.Run("should reject unknown event types", func(t *testing.T) {
+		w := makeRequest(t, router, "POST", "/api/v1/webhooks", models.WebhookSubscriptionRequest{
+			URL:    "https://example.com/hooks",
+			Secret: "webhook-test-secret",
+			Events: []string{"feedback.liked"},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should reject invalid URLs and short secrets", func(t *testing.T) {
+		w := makeRequest(t, router, "POST", "/api/v1/webhooks", models.WebhookSubscriptionRequest{URL: "not a url", Secret: "webhook-test-secret"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = makeRequest(t, router, "POST", "/api/v1/webhooks", models.WebhookSubscriptionRequest{URL: "https://example.com", Secret: "short"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should deactivate a subscription", func(t *testing.T) {
+		sub := createTestWebhook(t, router, "https://example.com/other")
+		inactive := false
+
+		w := makeRequest(t, router, "PUT", fmt.Sprintf("/api/v1/webhooks/%d", sub.ID), models.WebhookSubscriptionRequest{
+			URL:    sub.URL,
+			Secret: "webhook-test-secret",
+			Active: &inactive,
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var updated models.WebhookSubscription
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+		assert.False(t, updated.Active)
+	})
+
+	t.Run("should delete a subscription", func(t *testing.T) {
+		sub := createTestWebhook(t, router, "https://example.com/gone")
+
+		w := makeRequest(t, router, "DELETE", fmt.Sprintf("/api/v1/webhooks/%d", sub.ID), nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/webhooks/%d", sub.ID), nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestTestWebhook(t *testing.T) {
+	router := setupWebhookTestRouter(t)
+	receiver := newWebhookReceiver(t, "webhook-test-secret")
+	sub := createTestWebhook(t, router, receiver.server.URL, events.TeamCreated)
+
+	w := makeRequest(t, router, "POST", fmt.Sprintf("/api/v1/webhooks/%d/test", sub.ID), nil)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var delivery models.WebhookDelivery
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &delivery))
+	assert.True(t, delivery.Success)
+	assert.Equal(t, webhooks.TestEvent, delivery.EventType)
+	assert.Equal(t, []string{webhooks.TestEvent}, receiver.types())
+
+	w = makeRequest(t, router, "GET", fmt.Sprintf("/api/v1/webhooks/%d/deliveries", sub.ID), nil)
+	var deliveries []models.WebhookDelivery
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &deliveries))
+	assert.Len(t, deliveries, 1)
+	assert.Equal(t, http.StatusOK, deliveries[0].StatusCode)
+}
+
+func TestWebhookEvents(t *testing.T) {
+	router := setupWebhookTestRouter(t)
+	all := newWebhookReceiver(t, "webhook-test-secret")
+	feedbackOnly := newWebhookReceiver(t, "webhook-test-secret")
+	createTestWebhook(t, router, all.server.URL)
+	createTestWebhook(t, router, feedbackOnly.server.URL, events.FeedbackCreated)
+
+	send := func(method, url string, body interface{}) *httptest.ResponseRecorder {
+		w := makeRequest(t, router, method, url, body)
+		_, err := outbox.Flush()
+		assert.NoError(t, err)
+		webhooks.Default.Wait()
+		return w
+	}
+
+	w := send("POST", "/api/v1/teams", models.CreateTeamRequest{Name: "Webhook Team"})
+	var team models.Team
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &team))
+
+	w = send("POST", "/api/v1/persons", models.CreatePersonRequest{Name: "Hook Person", Email: "hook.person@example.com"})
+	var person models.Person
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &person))
+
+	send("POST", "/api/v1/assign", models.AssignToTeamRequest{PersonID: person.ID, TeamID: team.ID})
+	send("POST", fmt.Sprintf("/api/v1/persons/%d/remove-from-team", person.ID), nil)
+	send("POST", "/api/v1/feedbacks", models.CreateFeedbackRequest{Content: "Great demo", TargetType: "person", TargetID: person.ID})
+	send("POST", "/api/v1/feedbacks", models.CreateFeedbackRequest{Content: "A stupid idea", TargetType: "person", TargetID: person.ID})
+	send("DELETE", fmt.Sprintf("/api/v1/persons/%d", person.ID), nil)
+
+	assert.Equal(t, []string{
+		events.TeamCreated,
+		events.PersonCreated,
+		events.PersonAssignedToTeam,
+		events.PersonRemovedFromTeam,
+		events.FeedbackCreated,
+		events.PersonDeleted,
+	}, all.types())
+	assert.Equal(t, []string{events.FeedbackCreated}, feedbackOnly.types())
+}
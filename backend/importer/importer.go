@@ -0,0 +1,325 @@
+package importer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"strings"
+	"coaching-backend/events"
+	"coaching-backend/inbox"
+	"coaching-backend/models"
+	"coaching-backend/outbox"
+	"coaching-backend/privacy"
+	"gorm.io/gorm"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+var errRollback = errors.New("import rolled back")
+
+type Options struct {
+	DryRun bool
+	Atomic bool
+}
+
+type PersonRow struct {
+	Name    string `json:"name"`
+	Email   string `json:"email"`
+	Picture string `json:"picture"`
+	Team    string `json:"team"`
+}
+
+type TeamRow struct {
+	Name string `json:"name"`
+	Logo string `json:"logo"`
+}
+
+type RowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+type Result struct {
+	Rows         int        `json:"rows"`
+	Created      int        `json:"created"`
+	Updated      int        `json:"updated"`
+	Unchanged    int        `json:"unchanged"`
+	Failed       int        `json:"failed"`
+	TeamsCreated int        `json:"teams_created,omitempty"`
+	DryRun       bool       `json:"dry_run"`
+	Atomic       bool       `json:"atomic"`
+	Committed    bool       `json:"committed"`
+	Errors       []RowError `json:"errors"`
+}
+
+type tally struct {
+	created, updated, unchanged, teamsCreated int
+}
+
+func ParsePersons(r io.Reader, format string) ([]PersonRow, error) {
+	var rows []PersonRow
+	switch format {
+	case FormatJSON:
+		return rows, decodeJSON(r, &rows)
+	case FormatCSV:
+		records, err := readCSV(r, "name", "email", "picture", "team")
+		for _, record := range records {
+			rows = append(rows, PersonRow{Name: record["name"], Email: record["email"], Picture: record["picture"], Team: record["team"]})
+		}
+		return rows, err
+	}
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+func ParseTeams(r io.Reader, format string) ([]TeamRow, error) {
+	var rows []TeamRow
+	switch format {
+	case FormatJSON:
+		return rows, decodeJSON(r, &rows)
+	case FormatCSV:
+		records, err := readCSV(r, "name", "logo")
+		for _, record := range records {
+			rows = append(rows, TeamRow{Name: record["name"], Logo: record["logo"]})
+		}
+		return rows, err
+	}
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+func ImportPersons(db *gorm.DB, rows []PersonRow, opts Options) (Result, error) {
+	seen := map[string]int{}
+	return run(db, len(rows), opts, func(tx *gorm.DB, i int, t *tally) error {
+		row := rows[i]
+		row.Name = strings.TrimSpace(row.Name)
+		row.Email = strings.TrimSpace(row.Email)
+		row.Team = strings.TrimSpace(row.Team)
+
+		if row.Name == "" {
+			return errors.New("name is required")
+		}
+		if address, err := mail.ParseAddress(row.Email); err != nil || address.Address != row.Email {
+			return fmt.Errorf("invalid email %q", row.Email)
+		}
+		key := strings.ToLower(row.Email)
+		if first, ok := seen[key]; ok {
+			return fmt.Errorf("duplicate email %q, first seen in row %d", row.Email, first)
+		}
+		seen[key] = i + 1
+
+		return importPerson(tx, row, t)
+	})
+}
+
+func ImportTeams(db *gorm.DB, rows []TeamRow, opts Options) (Result, error) {
+	seen := map[string]int{}
+	return run(db, len(rows), opts, func(tx *gorm.DB, i int, t *tally) error {
+		row := rows[i]
+		row.Name = strings.TrimSpace(row.Name)
+		if row.Name == "" {
+			return errors.New("name is required")
+		}
+		if first, ok := seen[row.Name]; ok {
+			return fmt.Errorf("duplicate team %q, first seen in row %d", row.Name, first)
+		}
+		seen[row.Name] = i + 1
+
+		var team models.Team
+		err := tx.Where("name = ?", row.Name).First(&team).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			t.created++
+			return createTeam(tx, &models.Team{Name: row.Name, Logo: row.Logo})
+		}
+		if err != nil {
+			return err
+		}
+
+		if row.Logo == "" || row.Logo == team.Logo {
+			t.unchanged++
+			return nil
+		}
+		team.Logo = row.Logo
+		if err := tx.Save(&team).Error; err != nil {
+			return err
+		}
+		t.updated++
+		return outbox.Add(tx, events.TeamUpdated, team)
+	})
+}
+
+// Every row runs in its own savepoint so a failing row never leaves partial writes behind.
+// The outer transaction is rolled back for dry runs and for atomic imports with errors.
+func run(db *gorm.DB, count int, opts Options, importRow func(tx *gorm.DB, i int, t *tally) error) (Result, error) {
+	result := Result{Rows: count, DryRun: opts.DryRun, Atomic: opts.Atomic, Errors: []RowError{}}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for i := 0; i < count; i++ {
+			var t tally
+			err := tx.Transaction(func(rowTx *gorm.DB) error {
+				return importRow(rowTx, i, &t)
+			})
+			if err != nil {
+				result.Failed++
+				result.Errors = append(result.Errors, RowError{Row: i + 1, Error: err.Error()})
+				continue
+			}
+			result.Created += t.created
+			result.Updated += t.updated
+			result.Unchanged += t.unchanged
+			result.TeamsCreated += t.teamsCreated
+		}
+
+		if opts.DryRun || (opts.Atomic && result.Failed > 0) {
+			return errRollback
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errRollback) {
+		return result, err
+	}
+
+	result.Committed = err == nil
+	if result.Committed {
+		outbox.Notify()
+	}
+	return result, nil
+}
+
+func importPerson(tx *gorm.DB, row PersonRow, t *tally) error {
+	var team *models.Team
+	if row.Team != "" {
+		team = &models.Team{}
+		err := tx.Where("name = ?", row.Team).First(team).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			*team = models.Team{Name: row.Team}
+			if err := createTeam(tx, team); err != nil {
+				return err
+			}
+			t.teamsCreated++
+		} else if err != nil {
+			return err
+		}
+	}
+
+	var person models.Person
+	err := tx.Where("LOWER(email) = ?", strings.ToLower(row.Email)).First(&person).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		person = models.Person{Name: row.Name, Email: row.Email, Picture: row.Picture}
+		if team != nil {
+			person.TeamID = &team.ID
+		}
+		if err := tx.Create(&person).Error; err != nil {
+			return err
+		}
+		t.created++
+		if err := outbox.Add(tx, events.PersonCreated, person); err != nil {
+			return err
+		}
+		return assigned(tx, person, team, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	joined := team != nil && (person.TeamID == nil || *person.TeamID != team.ID)
+	changed := joined || person.Name != row.Name || (row.Picture != "" && row.Picture != person.Picture)
+	if !changed {
+		t.unchanged++
+		return nil
+	}
+
+	person.Name = row.Name
+	if row.Picture != "" {
+		person.Picture = row.Picture
+	}
+	previousTeamID := person.TeamID
+	if joined {
+		person.TeamID = &team.ID
+	}
+	if err := tx.Save(&person).Error; err != nil {
+		return err
+	}
+	t.updated++
+	if err := outbox.Add(tx, events.PersonUpdated, person); err != nil {
+		return err
+	}
+	if !joined {
+		return nil
+	}
+	return assigned(tx, person, team, previousTeamID)
+}
+
+func assigned(tx *gorm.DB, person models.Person, team *models.Team, previousTeamID *uint) error {
+	if team == nil {
+		return nil
+	}
+	person.Team = team
+	if err := outbox.Add(tx, events.PersonAssignedToTeam, models.TeamAssignment{Person: person, PreviousTeamID: previousTeamID}); err != nil {
+		return err
+	}
+	if err := privacy.RecordAssignment(tx, person.ID, previousTeamID, team.ID); err != nil {
+		return err
+	}
+	return inbox.AddedToTeam(tx, person, *team)
+}
+
+func createTeam(tx *gorm.DB, team *models.Team) error {
+	if err := tx.Create(team).Error; err != nil {
+		return err
+	}
+	return outbox.Add(tx, events.TeamCreated, team)
+}
+
+func decodeJSON(r io.Reader, v interface{}) error {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("invalid JSON: %v", err)
+	}
+	return nil
+}
+
+func readCSV(r io.Reader, columns ...string) ([]map[string]string, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("CSV is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %v", err)
+	}
+
+	known := map[string]bool{}
+	for _, column := range columns {
+		known[column] = true
+	}
+	for i, name := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !known[header[i]] {
+			return nil, fmt.Errorf("unknown CSV column %q, expected %s", name, strings.Join(columns, ", "))
+		}
+	}
+
+	var records []map[string]string
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %v", err)
+		}
+		record := map[string]string{}
+		for i, value := range fields {
+			record[header[i]] = value
+		}
+		records = append(records, record)
+	}
+}
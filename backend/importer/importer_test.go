@@ -0,0 +1,162 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"coaching-backend/database"
+	"coaching-backend/events"
+	"coaching-backend/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTestDB(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	database.DB = db
+}
+
+func count(t *testing.T, model interface{}) int64 {
+	var n int64
+	assert.NoError(t, database.GetDB().Model(model).Count(&n).Error)
+	return n
+}
+
+func TestParsePersons(t *testing.T) {
+	t.Run("should read CSV with columns in any order", func(t *testing.T) {
+		rows, err := ParsePersons(strings.NewReader("\ufeffEmail,Name,Team\nann@example.com,Ann,Core\n\"bob@example.com\",\"Bob, Jr\",\n"), FormatCSV)
+		assert.NoError(t, err)
+		assert.Equal(t, []PersonRow{
+			{Name: "Ann", Email: "ann@example.com", Team: "Core"},
+			{Name: "Bob, Jr", Email: "bob@example.com"},
+		}, rows)
+	})
+
+	t.Run("should read JSON arrays", func(t *testing.T) {
+		rows, err := ParsePersons(strings.NewReader(`[{"name":"Ann","email":"ann@example.com","picture":"a.png"}]`), FormatJSON)
+		assert.NoError(t, err)
+		assert.Equal(t, "a.png", rows[0].Picture)
+	})
+
+	t.Run("should reject unknown columns and fields", func(t *testing.T) {
+		_, err := ParsePersons(strings.NewReader("name,email,salary\n"), FormatCSV)
+		assert.ErrorContains(t, err, "salary")
+
+		_, err = ParsePersons(strings.NewReader(`[{"name":"Ann","salary":1}]`), FormatJSON)
+		assert.Error(t, err)
+
+		_, err = ParsePersons(strings.NewReader(""), "xml")
+		assert.Error(t, err)
+	})
+}
+
+func TestImportPersons(t *testing.T) {
+	setupTestDB(t)
+	core := models.Team{Name: "Core"}
+	assert.NoError(t, database.GetDB().Create(&core).Error)
+	existing := models.Person{Name: "Old Name", Email: "ann@example.com", Picture: "old.png"}
+	assert.NoError(t, database.GetDB().Create(&existing).Error)
+
+	rows := []PersonRow{
+		{Name: "Ann", Email: "ANN@example.com", Team: "Core"},
+		{Name: "Bob", Email: "bob@example.com", Team: "Platform"},
+		{Name: "Cid", Email: "cid@example.com", Team: "Platform"},
+		{Name: "", Email: "nameless@example.com"},
+		{Name: "Dup", Email: "bob@example.com"},
+		{Name: "Bad", Email: "not-an-email"},
+	}
+
+	t.Run("should validate without writing in dry-run mode", func(t *testing.T) {
+		result, err := ImportPersons(database.GetDB(), rows, Options{DryRun: true})
+		assert.NoError(t, err)
+
+		assert.False(t, result.Committed)
+		assert.Equal(t, 6, result.Rows)
+		assert.Equal(t, 2, result.Created)
+		assert.Equal(t, 1, result.Updated)
+		assert.Equal(t, 1, result.TeamsCreated)
+		assert.Equal(t, 3, result.Failed)
+		assert.Equal(t, []int{4, 5, 6}, []int{result.Errors[0].Row, result.Errors[1].Row, result.Errors[2].Row})
+		assert.Contains(t, result.Errors[1].Error, "first seen in row 2")
+
+		assert.Equal(t, int64(1), count(t, &models.Person{}))
+		assert.Equal(t, int64(1), count(t, &models.Team{}))
+		assert.Equal(t, int64(0), count(t, &models.OutboxEvent{}))
+	})
+
+	t.Run("should import nothing in atomic mode when a row fails", func(t *testing.T) {
+		result, err := ImportPersons(database.GetDB(), rows, Options{Atomic: true})
+		assert.NoError(t, err)
+
+		assert.False(t, result.Committed)
+		assert.Equal(t, 3, result.Failed)
+		assert.Equal(t, int64(1), count(t, &models.Person{}))
+	})
+
+	t.Run("should import valid rows and report the rest", func(t *testing.T) {
+		result, err := ImportPersons(database.GetDB(), rows, Options{})
+		assert.NoError(t, err)
+
+		assert.True(t, result.Committed)
+		assert.Equal(t, 2, result.Created)
+		assert.Equal(t, 1, result.Updated)
+		assert.Equal(t, 3, result.Failed)
+
+		var ann models.Person
+		assert.NoError(t, database.GetDB().First(&ann, existing.ID).Error)
+		assert.Equal(t, "Ann", ann.Name)
+		assert.Equal(t, "ann@example.com", ann.Email)
+		assert.Equal(t, "old.png", ann.Picture)
+		assert.Equal(t, core.ID, *ann.TeamID)
+
+		var platform models.Team
+		assert.NoError(t, database.GetDB().Where("name = ?", "Platform").First(&platform).Error)
+		var members int64
+		database.GetDB().Model(&models.Person{}).Where("team_id = ?", platform.ID).Count(&members)
+		assert.Equal(t, int64(2), members)
+
+		var types []string
+		database.GetDB().Model(&models.OutboxEvent{}).Order("id").Pluck("type", &types)
+		assert.Contains(t, types, events.TeamCreated)
+		assert.Contains(t, types, events.PersonCreated)
+		assert.Contains(t, types, events.PersonUpdated)
+		assert.Contains(t, types, events.PersonAssignedToTeam)
+		assert.Equal(t, int64(3), count(t, &models.Notification{}))
+	})
+
+	t.Run("should leave matching rows unchanged", func(t *testing.T) {
+		result, err := ImportPersons(database.GetDB(), rows[:3], Options{Atomic: true})
+		assert.NoError(t, err)
+
+		assert.True(t, result.Committed)
+		assert.Equal(t, 3, result.Unchanged)
+		assert.Zero(t, result.Created+result.Updated)
+	})
+}
+
+func TestImportTeams(t *testing.T) {
+	setupTestDB(t)
+	assert.NoError(t, database.GetDB().Create(&models.Team{Name: "Core", Logo: "old.png"}).Error)
+
+	rows, err := ParseTeams(strings.NewReader("name,logo\nCore,new.png\nDesign,\n Design ,d.png\n"), FormatCSV)
+	assert.NoError(t, err)
+
+	result, err := ImportTeams(database.GetDB(), rows, Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 1, result.Updated)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, 3, result.Errors[0].Row)
+
+	var core models.Team
+	database.GetDB().Where("name = ?", "Core").First(&core)
+	assert.Equal(t, "new.png", core.Logo)
+	assert.Equal(t, int64(2), count(t, &models.Team{}))
+}
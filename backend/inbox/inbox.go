@@ -0,0 +1,157 @@
+package inbox
+
+import (
+	"fmt"
+	"log"
+	"time"
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"gorm.io/gorm"
+)
+
+const PruneInterval = time.Hour
+
+var (
+	ReadRetention   = 30 * 24 * time.Hour
+	UnreadRetention = 90 * 24 * time.Hour
+)
+
+// actorTitles are the titles that name the notification's actor.
+var actorTitles = map[string]string{
+	models.NotificationFeedbackReceived:     "New feedback from %s",
+	models.NotificationFeedbackAcknowledged: "Your feedback for %s was acknowledged",
+}
+
+// ActorTitle returns the title of a notification of the given type that
+// names the actor, and false for types whose title names no person.
+func ActorTitle(notificationType, name string) (string, bool) {
+	format, ok := actorTitles[notificationType]
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprintf(format, name), true
+}
+
+// ActorTypes lists the notification types whose title names the actor.
+func ActorTypes() []string {
+	return []string{models.NotificationFeedbackReceived, models.NotificationFeedbackAcknowledged}
+}
+
+func Recipients(db *gorm.DB, feedback models.Feedback) ([]models.Person, error) {
+	var people []models.Person
+	query := db
+	switch feedback.TargetType {
+	case "person":
+		query = query.Where("id = ?", feedback.TargetID)
+	case "team":
+		query = query.Where("team_id = ?", feedback.TargetID)
+	default:
+		return people, nil
+	}
+	if feedback.AuthorID != nil {
+		query = query.Where("id <> ?", *feedback.AuthorID)
+	}
+	err := query.Order("id").Find(&people).Error
+	return people, err
+}
+
+func FeedbackReceived(tx *gorm.DB, feedback models.Feedback) error {
+	recipients, err := Recipients(tx, feedback)
+	if err != nil || len(recipients) == 0 {
+		return err
+	}
+
+	author := authorName(tx, feedback.AuthorID)
+	notifications := make([]models.Notification, 0, len(recipients))
+	for _, person := range recipients {
+		title := "You received new feedback"
+		var actorID *uint
+		switch {
+		case feedback.TargetType == "team":
+			title = fmt.Sprintf("New feedback for your team %s", feedback.TargetName)
+		case author != "":
+			title, _ = ActorTitle(models.NotificationFeedbackReceived, author)
+			actorID = feedback.AuthorID
+		}
+		notifications = append(notifications, models.Notification{
+			PersonID:   person.ID,
+			Type:       models.NotificationFeedbackReceived,
+			Title:      title,
+			FeedbackID: &feedback.ID,
+			ActorID:    actorID,
+		})
+	}
+	return tx.Create(&notifications).Error
+}
+
+func FeedbackAcknowledged(tx *gorm.DB, feedback models.Feedback) error {
+	if feedback.AuthorID == nil {
+		return nil
+	}
+
+	title, _ := ActorTitle(models.NotificationFeedbackAcknowledged, feedback.TargetName)
+	var actorID *uint
+	if feedback.TargetType == "person" {
+		actorID = &feedback.TargetID
+	}
+	return tx.Create(&models.Notification{
+		PersonID:   *feedback.AuthorID,
+		Type:       models.NotificationFeedbackAcknowledged,
+		Title:      title,
+		FeedbackID: &feedback.ID,
+		ActorID:    actorID,
+	}).Error
+}
+
+func AddedToTeam(tx *gorm.DB, person models.Person, team models.Team) error {
+	return tx.Create(&models.Notification{
+		PersonID: person.ID,
+		Type:     models.NotificationAddedToTeam,
+		Title:    fmt.Sprintf("You were added to %s", team.Name),
+		TeamID:   &team.ID,
+	}).Error
+}
+
+func RemovedFromTeam(tx *gorm.DB, person models.Person, teamID uint) error {
+	title := "You were removed from your team"
+	var team models.Team
+	if err := tx.First(&team, teamID).Error; err == nil {
+		title = fmt.Sprintf("You were removed from %s", team.Name)
+	}
+	return tx.Create(&models.Notification{
+		PersonID: person.ID,
+		Type:     models.NotificationRemovedFromTeam,
+		Title:    title,
+		TeamID:   &teamID,
+	}).Error
+}
+
+func Prune(now time.Time) (int64, error) {
+	result := database.GetDB().
+		Where("(read_at IS NOT NULL AND read_at < ?) OR created_at < ?", now.Add(-ReadRetention), now.Add(-UnreadRetention)).
+		Delete(&models.Notification{})
+	return result.RowsAffected, result.Error
+}
+
+func Run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := Prune(time.Now()); err != nil {
+			log.Printf("inbox: failed to prune notifications: %v", err)
+		}
+		<-ticker.C
+	}
+}
+
+func authorName(tx *gorm.DB, authorID *uint) string {
+	if authorID == nil {
+		return ""
+	}
+	var author models.Person
+	if err := tx.Select("name").First(&author, *authorID).Error; err != nil {
+		return ""
+	}
+	return author.Name
+}
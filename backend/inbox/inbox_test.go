@@ -0,0 +1,98 @@
+package inbox
+
+import (
+	"testing"
+	"time"
+
+	"coaching-backend/database"
+	"coaching-backend/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTestDB(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	database.DB = db
+}
+
+func createPerson(t *testing.T, name, email string, teamID *uint) models.Person {
+	person := models.Person{Name: name, Email: email, TeamID: teamID}
+	if err := database.GetDB().Create(&person).Error; err != nil {
+		t.Fatalf("Failed to create person: %v", err)
+	}
+	return person
+}
+
+func TestFeedbackReceived(t *testing.T) {
+	setupTestDB(t)
+	team := models.Team{Name: "Inbox Team"}
+	assert.NoError(t, database.GetDB().Create(&team).Error)
+	author := createPerson(t, "Author", "author@example.com", &team.ID)
+	member := createPerson(t, "Member", "member@example.com", &team.ID)
+	createPerson(t, "Outsider", "outsider@example.com", nil)
+
+	t.Run("should notify team members except the author", func(t *testing.T) {
+		feedback := models.Feedback{ID: 7, Content: "Nice", TargetType: "team", TargetID: team.ID, TargetName: team.Name, AuthorID: &author.ID}
+		assert.NoError(t, FeedbackReceived(database.GetDB(), feedback))
+
+		var notifications []models.Notification
+		database.GetDB().Where("feedback_id = ?", 7).Find(&notifications)
+		assert.Len(t, notifications, 1)
+		assert.Equal(t, member.ID, notifications[0].PersonID)
+		assert.Equal(t, "New feedback for your team Inbox Team", notifications[0].Title)
+	})
+
+	t.Run("should name the author for personal feedback", func(t *testing.T) {
+		feedback := models.Feedback{ID: 8, Content: "Nice", TargetType: "person", TargetID: member.ID, TargetName: member.Name, AuthorID: &author.ID}
+		assert.NoError(t, FeedbackReceived(database.GetDB(), feedback))
+
+		var notification models.Notification
+		assert.NoError(t, database.GetDB().Where("feedback_id = ?", 8).First(&notification).Error)
+		assert.Equal(t, "New feedback from Author", notification.Title)
+		assert.Equal(t, author.ID, *notification.ActorID)
+	})
+
+	t.Run("should skip self-addressed feedback", func(t *testing.T) {
+		feedback := models.Feedback{ID: 9, Content: "Note to self", TargetType: "person", TargetID: author.ID, AuthorID: &author.ID}
+		assert.NoError(t, FeedbackReceived(database.GetDB(), feedback))
+
+		var count int64
+		database.GetDB().Model(&models.Notification{}).Where("feedback_id = ?", 9).Count(&count)
+		assert.Zero(t, count)
+	})
+}
+
+func TestPrune(t *testing.T) {
+	setupTestDB(t)
+	now := time.Now()
+	recentRead := now.Add(-24 * time.Hour)
+	oldRead := now.Add(-ReadRetention - time.Hour)
+
+	keep := []models.Notification{
+		{PersonID: 1, Type: models.NotificationAddedToTeam, Title: "unread, recent", CreatedAt: now.Add(-48 * time.Hour)},
+		{PersonID: 1, Type: models.NotificationAddedToTeam, Title: "read, recent", CreatedAt: now.Add(-48 * time.Hour), ReadAt: &recentRead},
+	}
+	drop := []models.Notification{
+		{PersonID: 1, Type: models.NotificationAddedToTeam, Title: "read, old", CreatedAt: oldRead, ReadAt: &oldRead},
+		{PersonID: 1, Type: models.NotificationAddedToTeam, Title: "unread, expired", CreatedAt: now.Add(-UnreadRetention - time.Hour)},
+	}
+	assert.NoError(t, database.GetDB().Create(&keep).Error)
+	assert.NoError(t, database.GetDB().Create(&drop).Error)
+
+	pruned, err := Prune(now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), pruned)
+
+	var remaining []models.Notification
+	database.GetDB().Order("id").Find(&remaining)
+	assert.Len(t, remaining, 2)
+	assert.Equal(t, keep[0].ID, remaining[0].ID)
+	assert.Equal(t, keep[1].ID, remaining[1].ID)
+}
@@ -2,16 +2,77 @@ package main
 
 import (
 	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata"
+	"coaching-backend/chat"
 	"coaching-backend/config"
 	"coaching-backend/database"
+	"coaching-backend/encryption"
+	"coaching-backend/events"
 	"coaching-backend/handlers"
+	"coaching-backend/inbox"
+	"coaching-backend/notifications"
+	"coaching-backend/outbox"
+	"coaching-backend/privacy"
+	"coaching-backend/realtime"
+	"coaching-backend/retention"
+	"coaching-backend/storage"
+	"coaching-backend/stream"
+	"coaching-backend/webhooks"
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
 	cfg := config.Load()
+
+	keyring, err := encryption.Load(cfg.EncryptionKeys, cfg.EncryptionKeyFile, cfg.EncryptionIndexKey)
+	if err != nil {
+		log.Fatalf("Invalid encryption configuration: %v", err)
+	}
+	encryption.Configure(keyring)
+
+	storage.Default, err = storage.Open(cfg)
+	if err != nil {
+		log.Fatalf("Invalid storage configuration: %v", err)
+	}
 	
 	database.Connect(cfg)
+	// Team history used to live only in the outbox, which is now pruned.
+	if _, err := privacy.BackfillMemberships(database.GetDB()); err != nil {
+		log.Fatalf("Failed to backfill team membership history: %v", err)
+	}
+
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	events.Subscribe(webhooks.Default.Handle)
+	events.Subscribe(stream.Default.Handle)
+	events.Subscribe(realtime.Default.Handle)
+	events.Subscribe(chat.Default.Handle)
+	if cfg.SMTPHost != "" {
+		notifier := notifications.NewNotifier(smtpMailer(cfg))
+		events.Subscribe(notifier.Handle)
+		go notifier.Run(notifications.PollInterval)
+	}
+	go outbox.Run(outbox.PollInterval)
+	go webhooks.Default.Run(webhooks.PollInterval)
+	go inbox.Run(inbox.PruneInterval)
+
+	retentionInterval, err := time.ParseDuration(cfg.RetentionInterval)
+	if err != nil {
+		log.Fatalf("Invalid RETENTION_INTERVAL: %v", err)
+	}
+	if retentionInterval > 0 {
+		retentionDryRun, _ := strconv.ParseBool(cfg.RetentionDryRun)
+		go retention.Run(retentionInterval, retentionDryRun)
+	}
 
 	r := gin.Default()
 
@@ -34,30 +95,215 @@ func main() {
 		{
 			persons.POST("", handlers.CreatePerson)
 			persons.GET("", handlers.GetPersons)
+			persons.GET("/export", handlers.ExportPersons)
 			persons.GET("/:id", handlers.GetPerson)
+			persons.GET("/:id/report.pdf", handlers.GetPersonReport)
 			persons.PUT("/:id", handlers.UpdatePerson)
 			persons.DELETE("/:id", handlers.DeletePerson)
+			persons.POST("/:id/picture", handlers.UploadPersonPicture)
+			persons.DELETE("/:id/picture", handlers.DeletePersonPicture)
 			persons.POST("/:id/remove-from-team", handlers.RemoveFromTeam)
+			persons.GET("/:id/sessions", handlers.IdentifyViewer(cfg.AdminToken), handlers.GetPersonSessions)
+			persons.GET("/:id/action-items", handlers.GetPersonActionItems)
+			persons.GET("/:id/skills", handlers.GetPersonSkills)
+			persons.POST("/:id/skills", handlers.AssessSkill)
+			persons.GET("/:id/skills/:skillId/history", handlers.GetPersonSkillHistory)
+			persons.GET("/:id/notification-preferences", handlers.GetNotificationPreferences)
+			persons.PUT("/:id/notification-preferences", handlers.UpdateNotificationPreferences)
+			persons.GET("/:id/notifications", handlers.GetNotifications)
+			persons.GET("/:id/notifications/unread-count", handlers.GetUnreadNotificationCount)
+			persons.POST("/:id/notifications/read", handlers.MarkNotificationsRead)
+			persons.DELETE("/:id/notifications/:notificationId", handlers.DeleteNotification)
 		}
 
 		teams := api.Group("/teams")
 		{
 			teams.POST("", handlers.CreateTeam)
 			teams.GET("", handlers.GetTeams)
+			teams.GET("/export", handlers.ExportTeams)
 			teams.GET("/:id", handlers.GetTeam)
 			teams.PUT("/:id", handlers.UpdateTeam)
 			teams.DELETE("/:id", handlers.DeleteTeam)
+			teams.POST("/:id/logo", handlers.UploadTeamLogo)
+			teams.DELETE("/:id/logo", handlers.DeleteTeamLogo)
+			teams.GET("/:id/sessions", handlers.IdentifyViewer(cfg.AdminToken), handlers.GetTeamSessions)
+			teams.GET("/:id/action-items", handlers.GetTeamActionItems)
+			teams.GET("/:id/skills", handlers.GetTeamSkills)
+			teams.GET("/:id/health", handlers.GetTeamHealth)
+
+			teamChat := teams.Group("/:id/chat", handlers.RequireAdmin(cfg.AdminToken))
+			{
+				teamChat.GET("", handlers.GetTeamChat)
+				teamChat.PUT("", handlers.UpdateTeamChat)
+				teamChat.DELETE("", handlers.DeleteTeamChat)
+				teamChat.POST("/test", handlers.TestTeamChat)
+			}
 		}
 
+		api.GET("/media/*key", handlers.GetMedia)
+
 		feedbacks := api.Group("/feedbacks")
 		{
 			feedbacks.POST("", handlers.CreateFeedback)
 			feedbacks.GET("", handlers.GetFeedbacks)
+			feedbacks.GET("/export", handlers.ExportFeedbacks)
 			feedbacks.GET("/:id", handlers.GetFeedback)
 			feedbacks.GET("/by-target", handlers.GetFeedbacksByTarget)
+			feedbacks.PUT("/:id", handlers.UpdateFeedback)
 			feedbacks.DELETE("/:id", handlers.DeleteFeedback)
+			feedbacks.POST("/:id/acknowledge", handlers.AcknowledgeFeedback)
+		}
+
+		feedbackRequests := api.Group("/feedback-requests")
+		{
+			feedbackRequests.POST("", handlers.CreateFeedbackRequest)
+			feedbackRequests.GET("", handlers.GetFeedbackRequests)
+			feedbackRequests.GET("/:id", handlers.GetFeedbackRequest)
+			feedbackRequests.POST("/:id/decline", handlers.DeclineFeedbackRequest)
+			feedbackRequests.DELETE("/:id", handlers.DeleteFeedbackRequest)
+		}
+
+		templates := api.Group("/feedback-templates")
+		{
+			templates.POST("", handlers.CreateTemplate)
+			templates.GET("", handlers.GetTemplates)
+			templates.GET("/:id", handlers.GetTemplate)
+			templates.PUT("/:id", handlers.UpdateTemplate)
+			templates.DELETE("/:id", handlers.DeleteTemplate)
+		}
+
+		reviewCycles := api.Group("/review-cycles")
+		{
+			reviewCycles.POST("", handlers.CreateReviewCycle)
+			reviewCycles.GET("", handlers.GetReviewCycles)
+			reviewCycles.GET("/:id", handlers.GetReviewCycle)
+			reviewCycles.DELETE("/:id", handlers.DeleteReviewCycle)
+			reviewCycles.POST("/:id/close", handlers.CloseReviewCycle)
+			reviewCycles.GET("/:id/assignments", handlers.GetReviewAssignments)
+			reviewCycles.POST("/:id/assignments/:assignmentId/submit", handlers.SubmitReview)
+			reviewCycles.GET("/:id/report/:personId", handlers.GetReviewReport)
+		}
+
+		sessions := api.Group("/sessions", handlers.IdentifyViewer(cfg.AdminToken))
+		{
+			sessions.POST("", handlers.CreateSession)
+			sessions.GET("", handlers.GetSessions)
+			sessions.GET("/:id", handlers.GetSession)
+			sessions.PUT("/:id", handlers.UpdateSession)
+			sessions.DELETE("/:id", handlers.DeleteSession)
+			sessions.POST("/:id/feedbacks", handlers.AttachSessionFeedback)
+			sessions.DELETE("/:id/feedbacks/:feedbackId", handlers.DetachSessionFeedback)
+		}
+
+		goals := api.Group("/goals")
+		{
+			goals.POST("", handlers.CreateGoal)
+			goals.GET("", handlers.GetGoals)
+			goals.GET("/by-target", handlers.GetTargetOverview)
+			goals.GET("/:id", handlers.GetGoal)
+			goals.PUT("/:id", handlers.UpdateGoal)
+			goals.DELETE("/:id", handlers.DeleteGoal)
+			goals.POST("/:id/check-ins", handlers.CreateCheckIn)
+		}
+
+		actionItems := api.Group("/action-items")
+		{
+			actionItems.POST("", handlers.CreateActionItem)
+			actionItems.GET("", handlers.GetActionItems)
+			actionItems.GET("/overdue", handlers.GetOverdueActionItems)
+			actionItems.GET("/:id", handlers.GetActionItem)
+			actionItems.PUT("/:id", handlers.UpdateActionItem)
+			actionItems.DELETE("/:id", handlers.DeleteActionItem)
 		}
 
+		skills := api.Group("/skills")
+		{
+			skills.POST("", handlers.CreateSkill)
+			skills.GET("", handlers.GetSkills)
+			skills.GET("/:id", handlers.GetSkill)
+			skills.PUT("/:id", handlers.UpdateSkill)
+			skills.DELETE("/:id", handlers.DeleteSkill)
+		}
+
+		healthSurveys := api.Group("/health-surveys")
+		{
+			healthSurveys.POST("", handlers.CreateHealthSurvey)
+			healthSurveys.GET("", handlers.GetHealthSurveys)
+			healthSurveys.GET("/:id", handlers.GetHealthSurvey)
+			healthSurveys.DELETE("/:id", handlers.DeleteHealthSurvey)
+		}
+
+		healthRounds := api.Group("/health-rounds")
+		{
+			healthRounds.POST("", handlers.OpenHealthRound)
+			healthRounds.GET("", handlers.GetHealthRounds)
+			healthRounds.GET("/:id", handlers.GetHealthRound)
+			healthRounds.POST("/:id/close", handlers.CloseHealthRound)
+			healthRounds.POST("/:id/votes", handlers.SubmitHealthVotes)
+			healthRounds.GET("/:id/results", handlers.GetHealthRoundResults)
+		}
+
+		analytics := api.Group("/analytics")
+		{
+			analytics.GET("/volume", handlers.GetFeedbackVolume)
+			analytics.GET("/given-received", handlers.GetGivenReceived)
+			analytics.GET("/gaps", handlers.GetFeedbackGaps)
+			analytics.GET("/categories", handlers.GetCategoryStats)
+		}
+
+		moderation := api.Group("/moderation", handlers.RequireAdmin(cfg.AdminToken))
+		{
+			moderation.GET("/rules", handlers.GetModerationRules)
+			moderation.POST("/rules", handlers.CreateModerationRule)
+			moderation.PUT("/rules/:id", handlers.UpdateModerationRule)
+			moderation.DELETE("/rules/:id", handlers.DeleteModerationRule)
+			moderation.GET("/queue", handlers.GetModerationQueue)
+			moderation.POST("/queue/:id/approve", handlers.ApproveFeedback)
+			moderation.POST("/queue/:id/reject", handlers.RejectFeedback)
+		}
+
+		webhookRoutes := api.Group("/webhooks", handlers.RequireAdmin(cfg.AdminToken))
+		{
+			webhookRoutes.POST("", handlers.CreateWebhook)
+			webhookRoutes.GET("", handlers.GetWebhooks)
+			webhookRoutes.GET("/:id", handlers.GetWebhook)
+			webhookRoutes.PUT("/:id", handlers.UpdateWebhook)
+			webhookRoutes.DELETE("/:id", handlers.DeleteWebhook)
+			webhookRoutes.GET("/:id/deliveries", handlers.GetWebhookDeliveries)
+			webhookRoutes.POST("/:id/test", handlers.TestWebhook)
+		}
+
+		imports := api.Group("/import", handlers.RequireAdmin(cfg.AdminToken))
+		{
+			imports.POST("/persons", handlers.ImportPersons)
+			imports.POST("/teams", handlers.ImportTeams)
+		}
+
+		retentionRoutes := api.Group("/retention", handlers.RequireAdmin(cfg.AdminToken))
+		{
+			retentionRoutes.GET("/rules", handlers.GetRetentionRules)
+			retentionRoutes.POST("/rules", handlers.CreateRetentionRule)
+			retentionRoutes.PUT("/rules/:id", handlers.UpdateRetentionRule)
+			retentionRoutes.DELETE("/rules/:id", handlers.DeleteRetentionRule)
+			retentionRoutes.POST("/run", handlers.RunRetention)
+			retentionRoutes.GET("/runs", handlers.GetRetentionRuns)
+			retentionRoutes.GET("/metrics", handlers.GetRetentionMetrics)
+			retentionRoutes.GET("/holds", handlers.GetLegalHolds)
+			retentionRoutes.PUT("/holds/:id", handlers.PlaceLegalHold)
+			retentionRoutes.DELETE("/holds/:id", handlers.ReleaseLegalHold)
+		}
+
+		privacyRoutes := api.Group("/privacy", handlers.RequireAdmin(cfg.AdminToken))
+		{
+			privacyRoutes.GET("/persons/:id/export", handlers.ExportPersonData)
+			privacyRoutes.POST("/persons/:id/erase", handlers.ErasePerson)
+			privacyRoutes.GET("/audit", handlers.GetPrivacyAudit)
+		}
+
+		api.GET("/stream", handlers.Stream)
+		api.GET("/live", handlers.LiveDashboard(cfg.AdminToken, splitList(cfg.LiveAllowedOrigins)))
+		api.POST("/live/tickets", handlers.RequireAdmin(cfg.AdminToken), handlers.CreateLiveTicket(cfg.AdminToken))
+
 		api.POST("/assign", handlers.AssignToTeam)
 	}
 
@@ -68,3 +314,13 @@ func main() {
 	log.Printf("Server starting on port %s", cfg.Port)
 	log.Fatal(r.Run(":" + cfg.Port))
 }
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
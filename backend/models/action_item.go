@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	ActionStatusOpen       = "open"
+	ActionStatusInProgress = "in_progress"
+	ActionStatusDone       = "done"
+	ActionStatusCancelled  = "cancelled"
+
+	ActionSourceFeedback = "feedback"
+	ActionSourceSession  = "session"
+	ActionSourceGoal     = "goal"
+)
+
+type ActionItem struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	Title       string     `json:"title" gorm:"type:varchar(255);not null"`
+	Description string     `json:"description" gorm:"type:text"`
+	OwnerID     uint       `json:"owner_id" gorm:"index;not null"`
+	Owner       *Person    `json:"owner,omitempty" gorm:"foreignKey:OwnerID"`
+	DueDate     *time.Time `json:"due_date,omitempty" gorm:"index"`
+	Status      string     `json:"status" gorm:"type:varchar(20);not null;default:open;index"`
+	SourceType  string     `json:"source_type,omitempty" gorm:"type:varchar(20);index:idx_action_items_source"`
+	SourceID    *uint      `json:"source_id,omitempty" gorm:"index:idx_action_items_source"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+type CreateActionItemRequest struct {
+	Title       string     `json:"title" binding:"required"`
+	Description string     `json:"description"`
+	OwnerID     uint       `json:"owner_id" binding:"required"`
+	DueDate     *time.Time `json:"due_date"`
+	SourceType  string     `json:"source_type" binding:"omitempty,oneof=feedback session goal"`
+	SourceID    *uint      `json:"source_id" binding:"required_with=SourceType"`
+}
+
+type UpdateActionItemRequest struct {
+	Title       string     `json:"title" binding:"required"`
+	Description string     `json:"description"`
+	OwnerID     uint       `json:"owner_id" binding:"required"`
+	DueDate     *time.Time `json:"due_date"`
+	Status      string     `json:"status" binding:"required,oneof=open in_progress done cancelled"`
+}
+
+type OverdueActionItem struct {
+	ActionItem
+	DaysOverdue int `json:"days_overdue"`
+}
+
+type OverdueOwnerCount struct {
+	OwnerID   uint   `json:"owner_id"`
+	OwnerName string `json:"owner_name"`
+	Count     int    `json:"count"`
+}
+
+type OverdueReport struct {
+	Total   int                 `json:"total"`
+	ByOwner []OverdueOwnerCount `json:"by_owner"`
+	Items   []OverdueActionItem `json:"items"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	IntervalDay   = "day"
+	IntervalWeek  = "week"
+	IntervalMonth = "month"
+)
+
+type VolumePoint struct {
+	Bucket        string   `json:"bucket"`
+	Count         int64    `json:"count"`
+	AverageRating *float64 `json:"average_rating"`
+}
+
+type VolumeSeries struct {
+	Interval   string        `json:"interval"`
+	TimeZone   string        `json:"tz"`
+	TargetType string        `json:"target_type,omitempty"`
+	TargetID   uint          `json:"target_id,omitempty"`
+	Total      int64         `json:"total"`
+	Points     []VolumePoint `json:"points"`
+}
+
+type GivenReceived struct {
+	PersonID uint     `json:"person_id"`
+	Name     string   `json:"name"`
+	Given    int64    `json:"given"`
+	Received int64    `json:"received"`
+	Ratio    *float64 `json:"ratio"`
+}
+
+type FeedbackGap struct {
+	PersonID       uint       `json:"person_id"`
+	Name           string     `json:"name"`
+	Email          string     `json:"email"`
+	TeamID         *uint      `json:"team_id,omitempty"`
+	LastReceivedAt *time.Time `json:"last_received_at"`
+}
+
+type CategoryStats struct {
+	Category      string   `json:"category"`
+	Count         int64    `json:"count"`
+	Rated         int64    `json:"rated"`
+	AverageRating *float64 `json:"average_rating"`
+}
+
+type CategoryReport struct {
+	Total         int64           `json:"total"`
+	Rated         int64           `json:"rated"`
+	AverageRating *float64        `json:"average_rating"`
+	Categories    []CategoryStats `json:"categories"`
+}
@@ -0,0 +1,17 @@
+package models
+
+type TeamChat struct {
+	WebhookURL         string `json:"-" gorm:"type:text"`
+	Enabled            bool   `json:"enabled"`
+	IncludePrivate     bool   `json:"include_private"`
+	FeedbackTemplate   string `json:"feedback_template,omitempty" gorm:"type:text"`
+	MembershipTemplate string `json:"membership_template,omitempty" gorm:"type:text"`
+}
+
+type UpdateTeamChatRequest struct {
+	WebhookURL         string `json:"webhook_url" binding:"omitempty,url"`
+	Enabled            *bool  `json:"enabled"`
+	IncludePrivate     bool   `json:"include_private"`
+	FeedbackTemplate   string `json:"feedback_template"`
+	MembershipTemplate string `json:"membership_template"`
+}
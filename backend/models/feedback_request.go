@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	RequestStatusPending   = "pending"
+	RequestStatusFulfilled = "fulfilled"
+	RequestStatusDeclined  = "declined"
+	RequestStatusExpired   = "expired"
+)
+
+type FeedbackRequest struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	RequesterID uint       `json:"requester_id" gorm:"index;not null"`
+	Requester   *Person    `json:"requester,omitempty" gorm:"foreignKey:RequesterID"`
+	RecipientID uint       `json:"recipient_id" gorm:"index;not null"`
+	Recipient   *Person    `json:"recipient,omitempty" gorm:"foreignKey:RecipientID"`
+	TeamID      *uint      `json:"team_id,omitempty"`
+	TemplateID  *uint      `json:"template_id,omitempty"`
+	Message     string     `json:"message" gorm:"type:text"`
+	DueDate     *time.Time `json:"due_date,omitempty" gorm:"index"`
+	Status      string     `json:"status" gorm:"type:varchar(20);not null;default:pending;index"`
+	FeedbackID  *uint      `json:"feedback_id,omitempty"`
+	RespondedAt *time.Time `json:"responded_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+type CreateFeedbackRequestRequest struct {
+	RequesterID  uint       `json:"requester_id" binding:"required"`
+	RecipientIDs []uint     `json:"recipient_ids"`
+	TeamID       *uint      `json:"team_id"`
+	TemplateID   *uint      `json:"template_id"`
+	Message      string     `json:"message"`
+	DueDate      *time.Time `json:"due_date"`
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	GoalStatusOnTrack   = "on_track"
+	GoalStatusAtRisk    = "at_risk"
+	GoalStatusOffTrack  = "off_track"
+	GoalStatusCompleted = "completed"
+	GoalStatusCancelled = "cancelled"
+)
+
+type Goal struct {
+	ID          uint          `json:"id" gorm:"primaryKey"`
+	Title       string        `json:"title" gorm:"type:varchar(255);not null"`
+	Description string        `json:"description" gorm:"type:text"`
+	TargetType  string        `json:"target_type" gorm:"type:varchar(50);not null;index:idx_goals_target"`
+	TargetID    uint          `json:"target_id" gorm:"not null;index:idx_goals_target"`
+	TargetName  string        `json:"target_name" gorm:"type:varchar(255);not null"`
+	Status      string        `json:"status" gorm:"type:varchar(20);not null;default:on_track"`
+	Progress    float64       `json:"progress"`
+	DueDate     *time.Time    `json:"due_date,omitempty"`
+	KeyResults  []KeyResult   `json:"key_results,omitempty" gorm:"foreignKey:GoalID;constraint:OnDelete:CASCADE"`
+	CheckIns    []GoalCheckIn `json:"check_ins,omitempty" gorm:"foreignKey:GoalID;constraint:OnDelete:CASCADE"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+type KeyResult struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	GoalID       uint      `json:"goal_id" gorm:"index;not null"`
+	Title        string    `json:"title" gorm:"type:varchar(255);not null"`
+	StartValue   float64   `json:"start_value"`
+	TargetValue  float64   `json:"target_value"`
+	CurrentValue float64   `json:"current_value"`
+	Unit         string    `json:"unit" gorm:"type:varchar(50)"`
+	Progress     float64   `json:"progress"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type GoalCheckIn struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	GoalID      uint      `json:"goal_id" gorm:"index;not null"`
+	KeyResultID *uint     `json:"key_result_id,omitempty"`
+	Value       *float64  `json:"value,omitempty"`
+	Progress    float64   `json:"progress"`
+	Status      string    `json:"status" gorm:"type:varchar(20);not null"`
+	Note        string    `json:"note" gorm:"type:text"`
+	AuthorID    *uint     `json:"author_id,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type KeyResultRequest struct {
+	Title       string  `json:"title" binding:"required"`
+	StartValue  float64 `json:"start_value"`
+	TargetValue float64 `json:"target_value"`
+	Unit        string  `json:"unit"`
+}
+
+type CreateGoalRequest struct {
+	Title       string             `json:"title" binding:"required"`
+	Description string             `json:"description"`
+	TargetType  string             `json:"target_type" binding:"required,oneof=person team"`
+	TargetID    uint               `json:"target_id" binding:"required"`
+	DueDate     *time.Time         `json:"due_date"`
+	KeyResults  []KeyResultRequest `json:"key_results" binding:"dive"`
+}
+
+type UpdateGoalRequest struct {
+	Title       string     `json:"title" binding:"required"`
+	Description string     `json:"description"`
+	Status      string     `json:"status" binding:"required,oneof=on_track at_risk off_track completed cancelled"`
+	DueDate     *time.Time `json:"due_date"`
+}
+
+type CheckInRequest struct {
+	KeyResultID *uint    `json:"key_result_id"`
+	Value       *float64 `json:"value"`
+	Progress    *float64 `json:"progress" binding:"omitempty,min=0,max=100"`
+	Status      string   `json:"status" binding:"omitempty,oneof=on_track at_risk off_track completed cancelled"`
+	Note        string   `json:"note"`
+	AuthorID    *uint    `json:"author_id"`
+}
+
+type TargetOverview struct {
+	TargetType string     `json:"target_type"`
+	TargetID   uint       `json:"target_id"`
+	TargetName string     `json:"target_name"`
+	Goals      []Goal     `json:"goals"`
+	Feedbacks  []Feedback `json:"feedbacks"`
+}
@@ -0,0 +1,158 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	HealthRoundOpen   = "open"
+	HealthRoundClosed = "closed"
+
+	HealthGreen  = "green"
+	HealthYellow = "yellow"
+	HealthRed    = "red"
+
+	HealthTrendUp     = "improving"
+	HealthTrendStable = "stable"
+	HealthTrendDown   = "worse"
+
+	MinHealthResponses = 3
+)
+
+type HealthSurvey struct {
+	ID          uint              `json:"id" gorm:"primaryKey"`
+	Name        string            `json:"name" gorm:"type:varchar(255);unique;not null"`
+	Description string            `json:"description" gorm:"type:text"`
+	Dimensions  []HealthDimension `json:"dimensions" gorm:"foreignKey:SurveyID;constraint:OnDelete:CASCADE"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+type HealthDimension struct {
+	ID         uint   `json:"id" gorm:"primaryKey"`
+	SurveyID   uint   `json:"survey_id" gorm:"index;not null"`
+	Position   int    `json:"position" gorm:"not null"`
+	Name       string `json:"name" gorm:"type:varchar(255);not null"`
+	GreenLabel string `json:"green_label" gorm:"type:text"`
+	RedLabel   string `json:"red_label" gorm:"type:text"`
+}
+
+type HealthRound struct {
+	ID        uint          `json:"id" gorm:"primaryKey"`
+	SurveyID  uint          `json:"survey_id" gorm:"index;not null"`
+	Survey    *HealthSurvey `json:"survey,omitempty" gorm:"foreignKey:SurveyID"`
+	TeamID    uint          `json:"team_id" gorm:"index;not null"`
+	Status    string        `json:"status" gorm:"type:varchar(20);not null;default:open;index"`
+	ClosesAt  *time.Time    `json:"closes_at,omitempty"`
+	ClosedAt  *time.Time    `json:"closed_at,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+type HealthParticipation struct {
+	ID       uint `json:"id" gorm:"primaryKey"`
+	RoundID  uint `json:"round_id" gorm:"uniqueIndex:idx_health_round_person;not null"`
+	PersonID uint `json:"person_id" gorm:"uniqueIndex:idx_health_round_person;not null"`
+}
+
+type HealthVote struct {
+	ID          uint   `json:"id" gorm:"primaryKey"`
+	RoundID     uint   `json:"round_id" gorm:"index;not null"`
+	DimensionID uint   `json:"dimension_id" gorm:"index;not null"`
+	Color       string `json:"color" gorm:"type:varchar(10);not null"`
+	Trend       string `json:"trend,omitempty" gorm:"type:varchar(20)"`
+	Comment     string `json:"comment,omitempty" gorm:"type:text"`
+}
+
+type HealthDimensionRequest struct {
+	Name       string `json:"name" binding:"required"`
+	GreenLabel string `json:"green_label"`
+	RedLabel   string `json:"red_label"`
+}
+
+type CreateHealthSurveyRequest struct {
+	Name        string                   `json:"name" binding:"required"`
+	Description string                   `json:"description"`
+	Dimensions  []HealthDimensionRequest `json:"dimensions" binding:"required,min=1,dive"`
+}
+
+type OpenHealthRoundRequest struct {
+	SurveyID uint       `json:"survey_id" binding:"required"`
+	TeamID   uint       `json:"team_id" binding:"required"`
+	ClosesAt *time.Time `json:"closes_at"`
+}
+
+type HealthVoteRequest struct {
+	DimensionID uint   `json:"dimension_id" binding:"required"`
+	Color       string `json:"color" binding:"required,oneof=green yellow red"`
+	Trend       string `json:"trend" binding:"omitempty,oneof=improving stable worse"`
+	Comment     string `json:"comment"`
+}
+
+type SubmitHealthVotesRequest struct {
+	PersonID uint                `json:"person_id" binding:"required"`
+	Votes    []HealthVoteRequest `json:"votes" binding:"required,min=1,dive"`
+}
+
+type HealthDimensionResult struct {
+	DimensionID uint     `json:"dimension_id"`
+	Name        string   `json:"name"`
+	Green       int      `json:"green"`
+	Yellow      int      `json:"yellow"`
+	Red         int      `json:"red"`
+	Score       *float64 `json:"score"`
+	Color       string   `json:"color,omitempty"`
+	Trend       string   `json:"trend,omitempty"`
+	Comments    []string `json:"comments,omitempty"`
+}
+
+type HealthRoundResults struct {
+	Round        HealthRound             `json:"round"`
+	Participants int                     `json:"participants"`
+	TeamSize     int                     `json:"team_size"`
+	Hidden       bool                    `json:"hidden"`
+	Dimensions   []HealthDimensionResult `json:"dimensions"`
+}
+
+type HealthTrendPoint struct {
+	RoundID   uint      `json:"round_id"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	Votes     int       `json:"votes"`
+	Score     *float64  `json:"score"`
+	Color     string    `json:"color,omitempty"`
+}
+
+type HealthDimensionTrend struct {
+	DimensionID uint               `json:"dimension_id"`
+	Name        string             `json:"name"`
+	Points      []HealthTrendPoint `json:"points"`
+}
+
+type TeamHealthTrends struct {
+	TeamID     uint                   `json:"team_id"`
+	SurveyID   uint                   `json:"survey_id"`
+	Rounds     int                    `json:"rounds"`
+	Dimensions []HealthDimensionTrend `json:"dimensions"`
+}
+
+func DefaultHealthSurveys() []HealthSurvey {
+	return []HealthSurvey{
+		{
+			Name:        "Squad Health Check",
+			Description: "Traffic-light check of how the team feels about the way it works",
+			Dimensions: []HealthDimension{
+				{Position: 1, Name: "Easy to release", GreenLabel: "Releasing is simple, safe and mostly automated", RedLabel: "Releasing is risky, painful and takes a lot of work"},
+				{Position: 2, Name: "Suitable process", GreenLabel: "Our way of working fits us perfectly", RedLabel: "Our way of working gets in the way"},
+				{Position: 3, Name: "Tech quality", GreenLabel: "We're proud of the quality of our code", RedLabel: "Our code is hard to work with and technical debt is out of control"},
+				{Position: 4, Name: "Value", GreenLabel: "We deliver great stuff and stakeholders are really happy", RedLabel: "What we deliver doesn't matter to anyone"},
+				{Position: 5, Name: "Speed", GreenLabel: "We get stuff done really quickly, no waiting and no delays", RedLabel: "We never seem to get anything done"},
+				{Position: 6, Name: "Mission", GreenLabel: "We know exactly why we are here and we are really excited about it", RedLabel: "We have no idea why we are here"},
+				{Position: 7, Name: "Fun", GreenLabel: "We love going to work and have great fun working together", RedLabel: "Work is a chore"},
+				{Position: 8, Name: "Learning", GreenLabel: "We're learning lots of interesting stuff all the time", RedLabel: "We never have time to learn anything"},
+				{Position: 9, Name: "Support", GreenLabel: "We always get great support and help when we ask for it", RedLabel: "We keep getting stuck because we can't get the support and help we ask for"},
+				{Position: 10, Name: "Pawns or players", GreenLabel: "We are in control of our destiny", RedLabel: "We are just pawns in a game of chess"},
+			},
+		},
+	}
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"time"
+	_ "coaching-backend/encryption"
 )
 
 type Person struct {
@@ -11,6 +12,8 @@ type Person struct {
 	Picture  string `json:"picture" gorm:"type:text"`
 	TeamID   *uint  `json:"team_id"`
 	Team     *Team  `json:"team,omitempty" gorm:"foreignKey:TeamID"`
+	ManagerID *uint `json:"manager_id,omitempty" gorm:"index"`
+	ErasedAt  *time.Time `json:"erased_at,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -20,24 +23,44 @@ type Team struct {
 	Name      string   `json:"name" gorm:"type:varchar(255);not null"`
 	Logo      string   `json:"logo" gorm:"type:text"`
 	Members   []Person `json:"members,omitempty" gorm:"foreignKey:TeamID"`
+	Chat      TeamChat `json:"chat" gorm:"embedded;embeddedPrefix:chat_"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
 type Feedback struct {
-	ID         uint   `json:"id" gorm:"primaryKey"`
-	Content    string `json:"content" gorm:"type:text;not null"`
-	TargetType string `json:"target_type" gorm:"type:varchar(50);not null"`
-	TargetID   uint   `json:"target_id" gorm:"not null"`
-	TargetName string `json:"target_name" gorm:"type:varchar(255);not null"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID                uint             `json:"id" gorm:"primaryKey"`
+	Content           string           `json:"content" gorm:"type:text;not null;serializer:encrypted"`
+	TargetType        string           `json:"target_type" gorm:"type:varchar(50);not null"`
+	TargetID          uint             `json:"target_id" gorm:"not null"`
+	TargetName        string           `json:"target_name" gorm:"type:text;not null;serializer:encrypted"`
+	TargetNameIndex   string           `json:"-" gorm:"type:varchar(64);index"`
+	TemplateID        *uint            `json:"template_id,omitempty" gorm:"index"`
+	AuthorID          *uint            `json:"author_id,omitempty" gorm:"index"`
+	RequestID         *uint            `json:"request_id,omitempty" gorm:"index"`
+	Category          string           `json:"category,omitempty" gorm:"type:varchar(50);index"`
+	Rating            *int             `json:"rating,omitempty"`
+	Private           bool             `json:"private"`
+	Sentiment         string           `json:"sentiment" gorm:"type:varchar(10);index"`
+	SentimentScore    float64          `json:"sentiment_score"`
+	ModerationStatus  string           `json:"moderation_status" gorm:"type:varchar(20);not null;default:approved;index"`
+	ModerationReasons []string         `json:"moderation_reasons,omitempty" gorm:"serializer:json;type:text"`
+	ModeratedAt       *time.Time       `json:"moderated_at,omitempty"`
+	PublishedAt       *time.Time       `json:"published_at,omitempty"`
+	AcknowledgedAt    *time.Time       `json:"acknowledged_at,omitempty"`
+	LegalHoldAt       *time.Time       `json:"legal_hold_at,omitempty"`
+	LegalHoldReason   string           `json:"legal_hold_reason,omitempty" gorm:"type:text"`
+	AnonymizedAt      *time.Time       `json:"anonymized_at,omitempty"`
+	Answers           []FeedbackAnswer `json:"answers,omitempty" gorm:"foreignKey:FeedbackID;constraint:OnDelete:CASCADE"`
+	CreatedAt         time.Time        `json:"created_at"`
+	UpdatedAt         time.Time        `json:"updated_at"`
 }
 
 type CreatePersonRequest struct {
-	Name    string `json:"name" binding:"required"`
-	Email   string `json:"email" binding:"required,email"`
-	Picture string `json:"picture"`
+	Name      string `json:"name" binding:"required"`
+	Email     string `json:"email" binding:"required,email"`
+	Picture   string `json:"picture"`
+	ManagerID *uint  `json:"manager_id"`
 }
 
 type CreateTeamRequest struct {
@@ -45,13 +68,65 @@ type CreateTeamRequest struct {
 	Logo string `json:"logo"`
 }
 
+// UpdatePersonRequest leaves the picture and manager unchanged when they are
+// omitted. A manager_id of 0 removes the manager.
+type UpdatePersonRequest struct {
+	Name      string  `json:"name" binding:"required"`
+	Email     string  `json:"email" binding:"required,email"`
+	Picture   *string `json:"picture"`
+	ManagerID *uint   `json:"manager_id"`
+}
+
+// UpdateTeamRequest leaves the logo unchanged when it is omitted.
+type UpdateTeamRequest struct {
+	Name string  `json:"name" binding:"required"`
+	Logo *string `json:"logo"`
+}
+
+// TeamAssignment is the payload of person.assigned_to_team. PreviousTeamID is
+// set when the person moved from another team.
+type TeamAssignment struct {
+	Person
+	PreviousTeamID *uint `json:"previous_team_id"`
+}
+
+const (
+	MembershipAdded   = "added"
+	MembershipRemoved = "removed"
+)
+
+type TeamMembershipChange struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	PersonID  uint      `json:"person_id" gorm:"not null;index"`
+	TeamID    uint      `json:"team_id" gorm:"not null"`
+	TeamName  string    `json:"team_name" gorm:"type:varchar(255)"`
+	Change    string    `json:"change" gorm:"type:varchar(20);not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type AssignToTeamRequest struct {
 	PersonID uint `json:"person_id" binding:"required"`
 	TeamID   uint `json:"team_id" binding:"required"`
 }
 
 type CreateFeedbackRequest struct {
-	Content    string `json:"content" binding:"required"`
-	TargetType string `json:"target_type" binding:"required,oneof=person team"`
-	TargetID   uint   `json:"target_id" binding:"required"`
+	Content    string          `json:"content" binding:"required_without=TemplateID"`
+	TargetType string          `json:"target_type" binding:"required,oneof=person team"`
+	TargetID   uint            `json:"target_id" binding:"required"`
+	TemplateID *uint           `json:"template_id,omitempty"`
+	Answers    []AnswerRequest `json:"answers,omitempty" binding:"dive"`
+	AuthorID   *uint           `json:"author_id,omitempty"`
+	RequestID  *uint           `json:"request_id,omitempty"`
+	Category   string          `json:"category,omitempty" binding:"max=50"`
+	Rating     *int            `json:"rating,omitempty" binding:"omitempty,min=1,max=5"`
+	Private    bool            `json:"private"`
+}
+
+// UpdateFeedbackRequest edits feedback. Feedback written with a template is
+// edited through its answers, and content is re-rendered from them.
+type UpdateFeedbackRequest struct {
+	Content  string          `json:"content" binding:"required_without=Answers"`
+	Answers  []AnswerRequest `json:"answers,omitempty" binding:"dive"`
+	Category string          `json:"category" binding:"max=50"`
+	Rating   *int            `json:"rating" binding:"omitempty,min=1,max=5"`
 }
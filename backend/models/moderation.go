@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	ModerationApproved = "approved"
+	ModerationPending  = "pending"
+	ModerationRejected = "rejected"
+)
+
+type ModerationRule struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"type:varchar(255);unique;not null"`
+	Kind      string    `json:"kind" gorm:"type:varchar(20);not null"`
+	Pattern   string    `json:"pattern" gorm:"type:text"`
+	Action    string    `json:"action" gorm:"type:varchar(20);not null"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ModerationRuleRequest struct {
+	Name    string `json:"name" binding:"required"`
+	Kind    string `json:"kind" binding:"required,oneof=words regex email phone max_length"`
+	Pattern string `json:"pattern"`
+	Action  string `json:"action" binding:"required,oneof=redact flag reject"`
+	Enabled *bool  `json:"enabled"`
+}
+
+func DefaultModerationRules() []ModerationRule {
+	return []ModerationRule{
+		{Name: "Maximum length", Kind: "max_length", Pattern: "5000", Action: "reject", Enabled: true},
+		{Name: "Abusive language", Kind: "words", Pattern: "idiot, stupid, moron, dumb, useless, incompetent, pathetic, loser, shut up", Action: "flag", Enabled: true},
+		{Name: "Email addresses", Kind: "email", Action: "redact", Enabled: true},
+		{Name: "Phone numbers", Kind: "phone", Action: "redact", Enabled: true},
+	}
+}
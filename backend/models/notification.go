@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	EmailImmediate = "immediate"
+	EmailDaily     = "daily"
+	EmailWeekly    = "weekly"
+	EmailOff       = "off"
+)
+
+type NotificationPreference struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	PersonID       uint      `json:"person_id" gorm:"not null;uniqueIndex"`
+	EmailFrequency string    `json:"email_frequency" gorm:"type:varchar(20);not null;default:immediate"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type EmailNotification struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	PersonID      uint       `json:"person_id" gorm:"not null;uniqueIndex:idx_email_notification_feedback"`
+	FeedbackID    uint       `json:"feedback_id" gorm:"not null;uniqueIndex:idx_email_notification_feedback"`
+	SentAt        *time.Time `json:"sent_at,omitempty" gorm:"index"`
+	Error         string     `json:"error,omitempty" gorm:"type:text"`
+	Attempts      int        `json:"attempts"`
+	LastAttemptAt *time.Time `json:"last_attempt_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type UpdateNotificationPreferenceRequest struct {
+	EmailFrequency string `json:"email_frequency" binding:"required,oneof=immediate daily weekly off"`
+}
+
+const (
+	NotificationFeedbackReceived     = "feedback_received"
+	NotificationFeedbackAcknowledged = "feedback_acknowledged"
+	NotificationAddedToTeam          = "added_to_team"
+	NotificationRemovedFromTeam      = "removed_from_team"
+)
+
+type Notification struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	PersonID   uint       `json:"person_id" gorm:"not null;index:idx_notification_person_read"`
+	Type       string     `json:"type" gorm:"type:varchar(50);not null"`
+	Title      string     `json:"title" gorm:"type:varchar(255);not null"`
+	FeedbackID *uint      `json:"feedback_id,omitempty" gorm:"index"`
+	TeamID     *uint      `json:"team_id,omitempty"`
+	ActorID    *uint      `json:"actor_id,omitempty" gorm:"index"`
+	ReadAt     *time.Time `json:"read_at,omitempty" gorm:"index:idx_notification_person_read"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"index"`
+}
+
+type MarkNotificationsReadRequest struct {
+	IDs []uint `json:"ids"`
+}
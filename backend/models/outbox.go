@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// OutboxEvent ids are assigned when a transaction inserts the row, which may
+// commit after rows with higher ids. Sequence is assigned when the event is
+// published instead, so it follows the order subscribers see.
+type OutboxEvent struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	EventID     string     `json:"event_id" gorm:"type:varchar(64);uniqueIndex;not null"`
+	Type        string     `json:"type" gorm:"type:varchar(50);not null"`
+	Payload     string     `json:"payload" gorm:"type:text;not null;serializer:encrypted"`
+	OccurredAt  time.Time  `json:"occurred_at"`
+	Sequence    *uint      `json:"sequence,omitempty" gorm:"index"`
+	PublishedAt *time.Time `json:"published_at,omitempty" gorm:"index"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
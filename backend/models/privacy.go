@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	PrivacyActionExport  = "export"
+	PrivacyActionErasure = "erasure"
+
+	ErasedPersonName = "Erased person"
+)
+
+type PrivacyAuditEntry struct {
+	ID          uint             `json:"id" gorm:"primaryKey"`
+	PersonID    uint             `json:"person_id" gorm:"not null;index"`
+	Action      string           `json:"action" gorm:"type:varchar(20);not null"`
+	RequestedBy string           `json:"requested_by" gorm:"type:varchar(255);not null"`
+	Reason      string           `json:"reason,omitempty" gorm:"type:text"`
+	Affected    map[string]int64 `json:"affected,omitempty" gorm:"serializer:json;type:text"`
+	CreatedAt   time.Time        `json:"created_at" gorm:"index"`
+}
+
+type ErasePersonRequest struct {
+	RequestedBy string `json:"requested_by" binding:"required"`
+	Reason      string `json:"reason"`
+}
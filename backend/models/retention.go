@@ -0,0 +1,70 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	RetentionDelete    = "delete"
+	RetentionAnonymize = "anonymize"
+
+	RetentionTriggerSchedule = "schedule"
+	RetentionTriggerManual   = "manual"
+	RetentionTriggerCommand  = "command"
+)
+
+type RetentionRule struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	Name       string    `json:"name" gorm:"type:varchar(255);unique;not null"`
+	TargetType string    `json:"target_type,omitempty" gorm:"type:varchar(50)"`
+	Category   string    `json:"category,omitempty" gorm:"type:varchar(50)"`
+	Months     int       `json:"months" gorm:"not null"`
+	Action     string    `json:"action" gorm:"type:varchar(20);not null"`
+	Enabled    bool      `json:"enabled"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type RetentionRuleRequest struct {
+	Name       string `json:"name" binding:"required"`
+	TargetType string `json:"target_type" binding:"omitempty,oneof=person team"`
+	Category   string `json:"category" binding:"max=50"`
+	Months     int    `json:"months" binding:"required,min=1,max=1200"`
+	Action     string `json:"action" binding:"required,oneof=delete anonymize"`
+	Enabled    *bool  `json:"enabled"`
+}
+
+type RetentionRun struct {
+	ID         uint                  `json:"id" gorm:"primaryKey"`
+	Trigger    string                `json:"trigger" gorm:"type:varchar(20);not null"`
+	DryRun     bool                  `json:"dry_run"`
+	Scanned    int64                 `json:"scanned"`
+	Deleted    int64                 `json:"deleted"`
+	Anonymized int64                 `json:"anonymized"`
+	Held       int64                 `json:"held"`
+	Rules      []RetentionRuleResult `json:"rules" gorm:"serializer:json;type:text"`
+	Error      string                `json:"error,omitempty" gorm:"type:text"`
+	StartedAt  time.Time             `json:"started_at" gorm:"index"`
+	FinishedAt time.Time             `json:"finished_at"`
+}
+
+type RetentionRuleResult struct {
+	RuleID    uint   `json:"rule_id"`
+	Name      string `json:"name"`
+	Action    string `json:"action"`
+	Affected  int64  `json:"affected"`
+	Held      int64  `json:"held"`
+	SampleIDs []uint `json:"sample_ids,omitempty"`
+}
+
+type RetentionMetrics struct {
+	Runs       int64         `json:"runs"`
+	Deleted    int64         `json:"deleted"`
+	Anonymized int64         `json:"anonymized"`
+	LegalHolds int64         `json:"legal_holds"`
+	LastRun    *RetentionRun `json:"last_run"`
+}
+
+type LegalHoldRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	CycleStatusOpen   = "open"
+	CycleStatusClosed = "closed"
+
+	RelationshipSelf    = "self"
+	RelationshipPeer    = "peer"
+	RelationshipManager = "manager"
+	RelationshipReport  = "report"
+
+	AssignmentStatusPending   = "pending"
+	AssignmentStatusSubmitted = "submitted"
+)
+
+type ReviewCycle struct {
+	ID           uint                `json:"id" gorm:"primaryKey"`
+	Name         string              `json:"name" gorm:"type:varchar(255);not null"`
+	StartsAt     time.Time           `json:"starts_at" gorm:"not null"`
+	EndsAt       time.Time           `json:"ends_at" gorm:"not null"`
+	Status       string              `json:"status" gorm:"type:varchar(20);not null;default:open"`
+	TemplateID   *uint               `json:"template_id,omitempty"`
+	ClosedAt     *time.Time          `json:"closed_at,omitempty"`
+	Participants []ReviewParticipant `json:"participants,omitempty" gorm:"foreignKey:CycleID;constraint:OnDelete:CASCADE"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+}
+
+type ReviewParticipant struct {
+	ID       uint    `json:"id" gorm:"primaryKey"`
+	CycleID  uint    `json:"cycle_id" gorm:"uniqueIndex:idx_cycle_person;not null"`
+	PersonID uint    `json:"person_id" gorm:"uniqueIndex:idx_cycle_person;not null"`
+	Person   *Person `json:"person,omitempty" gorm:"foreignKey:PersonID"`
+}
+
+type ReviewAssignment struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	CycleID      uint       `json:"cycle_id" gorm:"index;not null"`
+	ReviewerID   uint       `json:"reviewer_id" gorm:"index;not null"`
+	Reviewer     *Person    `json:"reviewer,omitempty" gorm:"foreignKey:ReviewerID"`
+	RevieweeID   uint       `json:"reviewee_id" gorm:"index;not null"`
+	Reviewee     *Person    `json:"reviewee,omitempty" gorm:"foreignKey:RevieweeID"`
+	Relationship string     `json:"relationship" gorm:"type:varchar(20);not null"`
+	Status       string     `json:"status" gorm:"type:varchar(20);not null;default:pending"`
+	FeedbackID   *uint      `json:"feedback_id,omitempty"`
+	SubmittedAt  *time.Time `json:"submitted_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+type CreateReviewCycleRequest struct {
+	Name           string    `json:"name" binding:"required"`
+	StartsAt       time.Time `json:"starts_at" binding:"required"`
+	EndsAt         time.Time `json:"ends_at" binding:"required"`
+	TemplateID     *uint     `json:"template_id"`
+	ParticipantIDs []uint    `json:"participant_ids"`
+	TeamIDs        []uint    `json:"team_ids"`
+}
+
+type SubmitReviewRequest struct {
+	Content string          `json:"content"`
+	Answers []AnswerRequest `json:"answers" binding:"dive"`
+}
+
+type ReviewProgress struct {
+	Total     int     `json:"total"`
+	Submitted int     `json:"submitted"`
+	Percent   float64 `json:"percent"`
+}
+
+type ReviewCycleSummary struct {
+	ReviewCycle
+	Progress ReviewProgress `json:"progress"`
+}
+
+type QuestionSummary struct {
+	Prompt        string             `json:"prompt"`
+	AnswerType    string             `json:"answer_type"`
+	AverageRating map[string]float64 `json:"average_rating,omitempty"`
+	Responses     []ReviewResponse   `json:"responses,omitempty"`
+}
+
+type ReviewResponse struct {
+	Relationship string `json:"relationship"`
+	Value        string `json:"value"`
+}
+
+type ReviewReport struct {
+	CycleID   uint                      `json:"cycle_id"`
+	Person    Person                    `json:"person"`
+	Progress  map[string]ReviewProgress `json:"progress"`
+	Questions []QuestionSummary         `json:"questions"`
+	Comments  []ReviewResponse          `json:"comments"`
+}
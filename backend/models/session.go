@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	SessionStatusScheduled = "scheduled"
+	SessionStatusCompleted = "completed"
+	SessionStatusCancelled = "cancelled"
+)
+
+type CoachingSession struct {
+	ID              uint       `json:"id" gorm:"primaryKey"`
+	CoachID         uint       `json:"coach_id" gorm:"index;not null"`
+	Coach           *Person    `json:"coach,omitempty" gorm:"foreignKey:CoachID"`
+	CoacheeID       uint       `json:"coachee_id" gorm:"index;not null"`
+	Coachee         *Person    `json:"coachee,omitempty" gorm:"foreignKey:CoacheeID"`
+	ScheduledAt     time.Time  `json:"scheduled_at" gorm:"index;not null"`
+	DurationMinutes int        `json:"duration_minutes"`
+	Agenda          string     `json:"agenda" gorm:"type:text"`
+	SharedNotes     string     `json:"shared_notes" gorm:"type:text"`
+	PrivateNotes    string     `json:"private_notes,omitempty" gorm:"type:text"`
+	Outcomes        string     `json:"outcomes" gorm:"type:text"`
+	Status          string     `json:"status" gorm:"type:varchar(20);not null;default:scheduled"`
+	Feedbacks       []Feedback `json:"feedbacks,omitempty" gorm:"many2many:session_feedbacks"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+type CreateSessionRequest struct {
+	CoachID         uint      `json:"coach_id" binding:"required"`
+	CoacheeID       uint      `json:"coachee_id" binding:"required"`
+	ScheduledAt     time.Time `json:"scheduled_at" binding:"required"`
+	DurationMinutes int       `json:"duration_minutes" binding:"omitempty,min=1"`
+	Agenda          string    `json:"agenda"`
+}
+
+type UpdateSessionRequest struct {
+	ScheduledAt     time.Time `json:"scheduled_at" binding:"required"`
+	DurationMinutes int       `json:"duration_minutes" binding:"omitempty,min=1"`
+	Agenda          string    `json:"agenda"`
+	SharedNotes     string    `json:"shared_notes"`
+	PrivateNotes    string    `json:"private_notes"`
+	Outcomes        string    `json:"outcomes"`
+	Status          string    `json:"status" binding:"required,oneof=scheduled completed cancelled"`
+}
+
+type AttachFeedbackRequest struct {
+	FeedbackID uint `json:"feedback_id" binding:"required"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	AssessmentSourceSelf    = "self"
+	AssessmentSourceManager = "manager"
+
+	SkillLevelMin = 1
+	SkillLevelMax = 5
+)
+
+type Skill struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Name        string    `json:"name" gorm:"type:varchar(255);unique;not null"`
+	Category    string    `json:"category" gorm:"type:varchar(100)"`
+	Description string    `json:"description" gorm:"type:text"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type SkillAssessment struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	PersonID   uint      `json:"person_id" gorm:"index:idx_assessments_person_skill;not null"`
+	SkillID    uint      `json:"skill_id" gorm:"index:idx_assessments_person_skill;not null"`
+	Skill      *Skill    `json:"skill,omitempty" gorm:"foreignKey:SkillID"`
+	Level      int       `json:"level" gorm:"not null"`
+	Source     string    `json:"source" gorm:"type:varchar(20);not null"`
+	AssessorID *uint     `json:"assessor_id,omitempty"`
+	Note       string    `json:"note" gorm:"type:text"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type CreateSkillRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Category    string `json:"category"`
+	Description string `json:"description"`
+}
+
+type AssessSkillRequest struct {
+	SkillID    uint   `json:"skill_id" binding:"required"`
+	Level      int    `json:"level" binding:"required"`
+	Source     string `json:"source" binding:"required,oneof=self manager"`
+	AssessorID *uint  `json:"assessor_id"`
+	Note       string `json:"note"`
+}
+
+type PersonSkill struct {
+	Skill             Skill      `json:"skill"`
+	SelfLevel         *int       `json:"self_level"`
+	SelfAssessedAt    *time.Time `json:"self_assessed_at,omitempty"`
+	ManagerLevel      *int       `json:"manager_level"`
+	ManagerAssessedAt *time.Time `json:"manager_assessed_at,omitempty"`
+}
+
+type SkillHeatmapMember struct {
+	PersonID uint         `json:"person_id"`
+	Name     string       `json:"name"`
+	Levels   map[uint]int `json:"levels"`
+}
+
+type SkillHeatmapSkill struct {
+	Skill   Skill   `json:"skill"`
+	Average float64 `json:"average"`
+	Rated   int     `json:"rated"`
+}
+
+type SkillHeatmap struct {
+	TeamID  uint                 `json:"team_id"`
+	Source  string               `json:"source"`
+	Skills  []SkillHeatmapSkill  `json:"skills"`
+	Members []SkillHeatmapMember `json:"members"`
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	AnswerTypeText   = "text"
+	AnswerTypeRating = "rating"
+	AnswerTypeChoice = "choice"
+	AnswerTypeYesNo  = "yes_no"
+
+	RatingMin = 1
+	RatingMax = 5
+)
+
+type FeedbackTemplate struct {
+	ID          uint               `json:"id" gorm:"primaryKey"`
+	Name        string             `json:"name" gorm:"type:varchar(255);unique;not null"`
+	Description string             `json:"description" gorm:"type:text"`
+	Questions   []TemplateQuestion `json:"questions" gorm:"foreignKey:TemplateID;constraint:OnDelete:CASCADE"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+type TemplateQuestion struct {
+	ID         uint     `json:"id" gorm:"primaryKey"`
+	TemplateID uint     `json:"template_id" gorm:"index;not null"`
+	Position   int      `json:"position" gorm:"not null"`
+	Prompt     string   `json:"prompt" gorm:"type:text;not null"`
+	AnswerType string   `json:"answer_type" gorm:"type:varchar(20);not null"`
+	Required   bool     `json:"required"`
+	Options    []string `json:"options,omitempty" gorm:"serializer:json;type:text"`
+}
+
+type FeedbackAnswer struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	FeedbackID uint      `json:"feedback_id" gorm:"index;not null"`
+	QuestionID uint      `json:"question_id" gorm:"not null"`
+	Prompt     string    `json:"prompt" gorm:"type:text;not null"`
+	AnswerType string    `json:"answer_type" gorm:"type:varchar(20);not null"`
+	Value      string    `json:"value" gorm:"type:text;serializer:encrypted"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type TemplateQuestionRequest struct {
+	ID         uint     `json:"id"`
+	Prompt     string   `json:"prompt" binding:"required"`
+	AnswerType string   `json:"answer_type" binding:"required,oneof=text rating choice yes_no"`
+	Required   bool     `json:"required"`
+	Options    []string `json:"options"`
+}
+
+type CreateTemplateRequest struct {
+	Name        string                    `json:"name" binding:"required"`
+	Description string                    `json:"description"`
+	Questions   []TemplateQuestionRequest `json:"questions" binding:"required,min=1,dive"`
+}
+
+type AnswerRequest struct {
+	QuestionID uint   `json:"question_id" binding:"required"`
+	Value      string `json:"value"`
+}
+
+func DefaultTemplates() []FeedbackTemplate {
+	return []FeedbackTemplate{
+		{
+			Name:        "SBI",
+			Description: "Situation, Behavior, Impact",
+			Questions: []TemplateQuestion{
+				{Position: 1, Prompt: "Situation", AnswerType: AnswerTypeText, Required: true},
+				{Position: 2, Prompt: "Behavior", AnswerType: AnswerTypeText, Required: true},
+				{Position: 3, Prompt: "Impact", AnswerType: AnswerTypeText, Required: true},
+			},
+		},
+		{
+			Name:        "Start, Stop, Continue",
+			Description: "What to start doing, stop doing and keep doing",
+			Questions: []TemplateQuestion{
+				{Position: 1, Prompt: "Start", AnswerType: AnswerTypeText},
+				{Position: 2, Prompt: "Stop", AnswerType: AnswerTypeText},
+				{Position: 3, Prompt: "Continue", AnswerType: AnswerTypeText},
+			},
+		},
+	}
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+)
+
+type WebhookSubscription struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	URL       string    `json:"url" gorm:"type:text;not null"`
+	Secret    string    `json:"-" gorm:"type:varchar(255);not null"`
+	Events    []string  `json:"events" gorm:"serializer:json;type:text"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type WebhookDelivery struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	SubscriptionID uint       `json:"subscription_id" gorm:"not null;index"`
+	EventID        string     `json:"event_id" gorm:"type:varchar(64);not null;index"`
+	EventType      string     `json:"event_type" gorm:"type:varchar(50);not null"`
+	EventSequence  uint       `json:"event_sequence,omitempty"`
+	Attempt        int        `json:"attempt"`
+	StatusCode     int        `json:"status_code"`
+	Success        bool       `json:"success"`
+	Error          string     `json:"error,omitempty" gorm:"type:text"`
+	DurationMs     int64      `json:"duration_ms"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty" gorm:"index"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type WebhookSubscriptionRequest struct {
+	URL    string   `json:"url" binding:"required,url"`
+	Secret string   `json:"secret" binding:"required,min=16"`
+	Events []string `json:"events"`
+	Active *bool    `json:"active"`
+}
@@ -0,0 +1,186 @@
+package moderation
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	KindWords     = "words"
+	KindRegex     = "regex"
+	KindEmail     = "email"
+	KindPhone     = "phone"
+	KindMaxLength = "max_length"
+
+	ActionRedact = "redact"
+	ActionFlag   = "flag"
+	ActionReject = "reject"
+
+	Redacted = "[redacted]"
+
+	minPhoneDigits              = 10
+	minInternationalPhoneDigits = 8
+)
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	phonePattern = regexp.MustCompile(`\+?\(?\d[\d\s().-]{6,}\d`)
+	datePattern  = regexp.MustCompile(`\d{4}[-./]\d{1,2}[-./]\d{1,2}|\d{1,2}[-./]\d{1,2}[-./]\d{2,4}`)
+)
+
+type Rule struct {
+	Name    string
+	Kind    string
+	Pattern string
+	Action  string
+}
+
+type Result struct {
+	Content  string
+	Rejected bool
+	Flagged  bool
+	Reasons  []string
+}
+
+func (r Rule) Validate() error {
+	switch r.Action {
+	case ActionRedact, ActionFlag, ActionReject:
+	default:
+		return fmt.Errorf("unknown moderation action %q", r.Action)
+	}
+
+	switch r.Kind {
+	case KindWords:
+		if len(splitWords(r.Pattern)) == 0 {
+			return errors.New("words rule needs a comma-separated list of words")
+		}
+	case KindRegex:
+		if _, err := regexp.Compile(r.Pattern); err != nil {
+			return fmt.Errorf("invalid regex: %v", err)
+		}
+	case KindEmail, KindPhone:
+	case KindMaxLength:
+		if limit, err := strconv.Atoi(r.Pattern); err != nil || limit < 1 {
+			return errors.New("max_length rule needs a positive number as pattern")
+		}
+	default:
+		return fmt.Errorf("unknown moderation rule kind %q", r.Kind)
+	}
+	return nil
+}
+
+func Apply(content string, rules []Rule) Result {
+	result := Result{Content: content, Reasons: []string{}}
+
+	for _, rule := range rules {
+		if rule.Validate() != nil {
+			continue
+		}
+
+		if rule.Kind == KindMaxLength {
+			limit, _ := strconv.Atoi(rule.Pattern)
+			runes := []rune(result.Content)
+			if len(runes) <= limit {
+				continue
+			}
+			if rule.Action == ActionRedact {
+				result.Content = string(runes[:limit])
+			}
+			result.record(rule)
+			continue
+		}
+
+		matches := findMatches(rule, result.Content)
+		if len(matches) == 0 {
+			continue
+		}
+		if rule.Action == ActionRedact {
+			result.Content = redact(result.Content, matches)
+		}
+		result.record(rule)
+	}
+
+	return result
+}
+
+func (r *Result) record(rule Rule) {
+	switch rule.Action {
+	case ActionReject:
+		r.Rejected = true
+	case ActionFlag:
+		r.Flagged = true
+	}
+	r.Reasons = append(r.Reasons, fmt.Sprintf("%s (%s)", rule.Name, rule.Action))
+}
+
+func findMatches(rule Rule, content string) [][]int {
+	switch rule.Kind {
+	case KindWords:
+		words := splitWords(rule.Pattern)
+		for i, w := range words {
+			words[i] = regexp.QuoteMeta(w)
+		}
+		pattern := regexp.MustCompile(`(?i)\b(` + strings.Join(words, "|") + `)\b`)
+		return pattern.FindAllStringIndex(content, -1)
+	case KindRegex:
+		return regexp.MustCompile(rule.Pattern).FindAllStringIndex(content, -1)
+	case KindEmail:
+		return emailPattern.FindAllStringIndex(content, -1)
+	case KindPhone:
+		var matches [][]int
+		for _, m := range phonePattern.FindAllStringIndex(content, -1) {
+			if looksLikePhone(content[m[0]:m[1]]) {
+				matches = append(matches, m)
+			}
+		}
+		return matches
+	}
+	return nil
+}
+
+// looksLikePhone keeps the phone rule away from dates, times and other runs of
+// numbers: a number needs a leading + or at least ten digits, and no date in it.
+func looksLikePhone(s string) bool {
+	if datePattern.MatchString(s) {
+		return false
+	}
+	if strings.HasPrefix(s, "+") {
+		return countDigits(s) >= minInternationalPhoneDigits
+	}
+	return countDigits(s) >= minPhoneDigits
+}
+
+func redact(content string, matches [][]int) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		b.WriteString(content[last:m[0]])
+		b.WriteString(Redacted)
+		last = m[1]
+	}
+	b.WriteString(content[last:])
+	return b.String()
+}
+
+func splitWords(pattern string) []string {
+	var words []string
+	for _, w := range strings.Split(pattern, ",") {
+		if w = strings.TrimSpace(w); w != "" {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+func countDigits(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			n++
+		}
+	}
+	return n
+}
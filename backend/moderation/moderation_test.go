@@ -0,0 +1,83 @@
+package moderation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApply(t *testing.T) {
+	t.Run("should redact emails and phone numbers", func(t *testing.T) {
+		rules := []Rule{
+			{Name: "Email", Kind: KindEmail, Action: ActionRedact},
+			{Name: "Phone", Kind: KindPhone, Action: ActionRedact},
+		}
+
+		result := Apply("Reach me at jane.doe@example.com or +1 (555) 123-4567 before 2024-01-02.", rules)
+
+		assert.Equal(t, "Reach me at [redacted] or [redacted] before 2024-01-02.", result.Content)
+		assert.False(t, result.Rejected)
+		assert.False(t, result.Flagged)
+		assert.Equal(t, []string{"Email (redact)", "Phone (redact)"}, result.Reasons)
+	})
+
+	t.Run("should leave dates, times and short numbers alone", func(t *testing.T) {
+		rules := []Rule{{Name: "Phone", Kind: KindPhone, Action: ActionRedact}}
+
+		for _, content := range []string{
+			"The retro is on 2024-01-15 10:30 in room 4",
+			"Moved to 15.01.2024 at 09:00",
+			"Ticket 123 456 789 is done",
+		} {
+			result := Apply(content, rules)
+			assert.Equal(t, content, result.Content)
+			assert.Empty(t, result.Reasons)
+		}
+
+		assert.Equal(t, "Call [redacted] or [redacted]", Apply("Call 555-123-4567 or +44 20 7946 0958", rules).Content)
+	})
+
+	t.Run("should flag whole words in any case", func(t *testing.T) {
+		rules := []Rule{{Name: "Abuse", Kind: KindWords, Pattern: "idiot, shut up", Action: ActionFlag}}
+
+		assert.True(t, Apply("What an IDIOT move", rules).Flagged)
+		assert.True(t, Apply("Please shut up in meetings", rules).Flagged)
+		assert.False(t, Apply("Idiomatic code is nice", rules).Flagged)
+	})
+
+	t.Run("should reject on regex match", func(t *testing.T) {
+		rules := []Rule{{Name: "Salary", Kind: KindRegex, Pattern: `(?i)salary of \$?\d+`, Action: ActionReject}}
+
+		result := Apply("Her salary of 90000 is too high", rules)
+
+		assert.True(t, result.Rejected)
+		assert.Equal(t, "Her salary of 90000 is too high", result.Content)
+	})
+
+	t.Run("should enforce maximum length", func(t *testing.T) {
+		reject := []Rule{{Name: "Length", Kind: KindMaxLength, Pattern: "10", Action: ActionReject}}
+		truncate := []Rule{{Name: "Length", Kind: KindMaxLength, Pattern: "10", Action: ActionRedact}}
+
+		assert.False(t, Apply("short", reject).Rejected)
+		assert.True(t, Apply("this is far too long", reject).Rejected)
+		assert.Equal(t, "this is fa", Apply("this is far too long", truncate).Content)
+	})
+
+	t.Run("should skip invalid rules", func(t *testing.T) {
+		rules := []Rule{{Name: "Broken", Kind: KindRegex, Pattern: "(", Action: ActionReject}}
+
+		result := Apply("anything", rules)
+
+		assert.False(t, result.Rejected)
+		assert.Empty(t, result.Reasons)
+	})
+}
+
+func TestRuleValidate(t *testing.T) {
+	assert.NoError(t, Rule{Kind: KindEmail, Action: ActionRedact}.Validate())
+	assert.Error(t, Rule{Kind: KindEmail, Action: "delete"}.Validate())
+	assert.Error(t, Rule{Kind: "sentiment", Action: ActionFlag}.Validate())
+	assert.Error(t, Rule{Kind: KindRegex, Pattern: "[", Action: ActionFlag}.Validate())
+	assert.Error(t, Rule{Kind: KindWords, Pattern: " , ", Action: ActionFlag}.Validate())
+	assert.Error(t, Rule{Kind: KindMaxLength, Pattern: "0", Action: ActionReject}.Validate())
+}
@@ -0,0 +1,331 @@
+package notifications
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+	"coaching-backend/database"
+	"coaching-backend/events"
+	"coaching-backend/inbox"
+	"coaching-backend/models"
+	"gorm.io/gorm"
+)
+
+const (
+	PollInterval = 15 * time.Minute
+	MaxAttempts  = 5
+)
+
+var (
+	// RetryDelay is how long an unsent email waits before it is tried again.
+	RetryDelay = 5 * time.Minute
+
+	digestPeriods = map[string]time.Duration{
+		models.EmailDaily:  24 * time.Hour,
+		models.EmailWeekly: 7 * 24 * time.Hour,
+	}
+)
+
+type Notifier struct {
+	Mailer Mailer
+	wg     sync.WaitGroup
+}
+
+func NewNotifier(mailer Mailer) *Notifier {
+	return &Notifier{Mailer: mailer}
+}
+
+func Frequency(personID uint) string {
+	var pref models.NotificationPreference
+	if err := database.GetDB().Where("person_id = ?", personID).First(&pref).Error; err != nil {
+		return models.EmailImmediate
+	}
+	return pref.EmailFrequency
+}
+
+func (n *Notifier) Handle(event events.Event) {
+	if event.Type != events.FeedbackCreated {
+		return
+	}
+
+	feedback, err := decodeFeedback(event)
+	if err != nil {
+		log.Printf("notifications: failed to decode %s %s: %v", event.Type, event.ID, err)
+		return
+	}
+
+	recipients, err := inbox.Recipients(database.GetDB(), feedback)
+	if err != nil {
+		log.Printf("notifications: failed to load recipients for feedback %d: %v", feedback.ID, err)
+		return
+	}
+
+	for _, person := range recipients {
+		frequency := Frequency(person.ID)
+		if frequency == models.EmailOff {
+			continue
+		}
+
+		// The unique index on person and feedback makes redelivered events a no-op.
+		notification := models.EmailNotification{PersonID: person.ID, FeedbackID: feedback.ID}
+		if err := database.GetDB().Create(&notification).Error; err != nil {
+			continue
+		}
+
+		if frequency == models.EmailImmediate {
+			n.wg.Add(1)
+			go func(person models.Person, notification models.EmailNotification) {
+				defer n.wg.Done()
+				n.sendImmediate(person, feedback, notification)
+			}(person, notification)
+		}
+	}
+}
+
+func (n *Notifier) Wait() {
+	n.wg.Wait()
+}
+
+// Run sends due digests and retries failed immediate emails on every tick.
+func (n *Notifier) Run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := n.RetryUnsent(time.Now()); err != nil {
+			log.Printf("notifications: failed to retry unsent emails: %v", err)
+		}
+		for _, frequency := range []string{models.EmailDaily, models.EmailWeekly} {
+			if _, err := n.SendDueDigests(frequency, time.Now()); err != nil {
+				log.Printf("notifications: failed to send %s digests: %v", frequency, err)
+			}
+		}
+		<-ticker.C
+	}
+}
+
+// SendDigests sends a digest to everyone on the frequency who has unsent
+// notifications, however recently they received the last one.
+func (n *Notifier) SendDigests(frequency string) (int, error) {
+	return n.sendDigests(frequency, func(uint) (bool, error) { return true, nil })
+}
+
+// SendDueDigests sends a digest to everyone on the frequency whose last email,
+// or oldest unsent notification if they were never emailed, is at least one
+// period old. A failed digest is retried after RetryDelay.
+func (n *Notifier) SendDueDigests(frequency string, now time.Time) (int, error) {
+	period := digestPeriods[frequency]
+	return n.sendDigests(frequency, func(personID uint) (bool, error) {
+		var oldest models.EmailNotification
+		err := unsent(database.GetDB()).Where("person_id = ?", personID).Order("id").First(&oldest).Error
+		if err == gorm.ErrRecordNotFound {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if oldest.LastAttemptAt != nil && now.Sub(*oldest.LastAttemptAt) < RetryDelay {
+			return false, nil
+		}
+
+		since := oldest.CreatedAt
+		var last models.EmailNotification
+		err = database.GetDB().Where("person_id = ? AND sent_at IS NOT NULL", personID).Order("sent_at desc").First(&last).Error
+		if err == nil {
+			since = *last.SentAt
+		} else if err != gorm.ErrRecordNotFound {
+			return false, err
+		}
+		return now.Sub(since) >= period, nil
+	})
+}
+
+// RetryUnsent emails people on immediate delivery about notifications that
+// failed or were never attempted, for example because of a restart.
+func (n *Notifier) RetryUnsent(now time.Time) (int, error) {
+	cutoff := now.Add(-RetryDelay)
+	var pending []models.EmailNotification
+	if err := unsent(database.GetDB()).
+		Where("created_at < ? AND (last_attempt_at IS NULL OR last_attempt_at < ?)", cutoff, cutoff).
+		Order("id").Find(&pending).Error; err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, notification := range pending {
+		if Frequency(notification.PersonID) != models.EmailImmediate {
+			continue
+		}
+
+		var person models.Person
+		if err := database.GetDB().First(&person, notification.PersonID).Error; err != nil {
+			markSent([]uint{notification.ID})
+			continue
+		}
+		var feedback models.Feedback
+		if err := database.GetDB().First(&feedback, notification.FeedbackID).Error; err != nil {
+			markSent([]uint{notification.ID})
+			continue
+		}
+
+		if n.sendImmediate(person, feedback, notification) {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+func (n *Notifier) sendDigests(frequency string, due func(personID uint) (bool, error)) (int, error) {
+	if frequency != models.EmailDaily && frequency != models.EmailWeekly {
+		return 0, fmt.Errorf("unsupported digest frequency %q", frequency)
+	}
+
+	var prefs []models.NotificationPreference
+	if err := database.GetDB().Where("email_frequency = ?", frequency).Order("person_id").Find(&prefs).Error; err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	var firstErr error
+	for _, pref := range prefs {
+		ok, err := due(pref.PersonID)
+		if err == nil && ok {
+			ok, err = n.sendDigest(pref.PersonID, frequency)
+		}
+		if err != nil {
+			log.Printf("notifications: failed to send %s digest to person %d: %v", frequency, pref.PersonID, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		if ok {
+			sent++
+		}
+	}
+	return sent, firstErr
+}
+
+func (n *Notifier) sendImmediate(person models.Person, feedback models.Feedback, notification models.EmailNotification) bool {
+	item := toItem(feedback)
+	msg, err := render(person.Email, subjectFor(item), emailData{Name: person.Name, Items: []feedbackItem{item}})
+	if err == nil {
+		err = n.Mailer.Send(msg)
+	}
+
+	if err != nil {
+		log.Printf("notifications: failed to email person %d about feedback %d: %v", person.ID, feedback.ID, err)
+		markFailed([]uint{notification.ID}, err)
+		return false
+	}
+	markSent([]uint{notification.ID})
+	return true
+}
+
+func (n *Notifier) sendDigest(personID uint, frequency string) (bool, error) {
+	var pending []models.EmailNotification
+	if err := unsent(database.GetDB()).Where("person_id = ?", personID).Order("id").Find(&pending).Error; err != nil {
+		return false, err
+	}
+	if len(pending) == 0 {
+		return false, nil
+	}
+
+	ids := make([]uint, 0, len(pending))
+	feedbackIDs := make([]uint, 0, len(pending))
+	for _, notification := range pending {
+		ids = append(ids, notification.ID)
+		feedbackIDs = append(feedbackIDs, notification.FeedbackID)
+	}
+
+	var feedbacks []models.Feedback
+	if err := database.GetDB().Where("id IN ?", feedbackIDs).Order("created_at").Find(&feedbacks).Error; err != nil {
+		return false, err
+	}
+	// Feedback deleted since the notification was queued is dropped from the digest.
+	if len(feedbacks) == 0 {
+		markSent(ids)
+		return false, nil
+	}
+
+	var person models.Person
+	if err := database.GetDB().First(&person, personID).Error; err != nil {
+		return false, err
+	}
+
+	items := make([]feedbackItem, 0, len(feedbacks))
+	for _, feedback := range feedbacks {
+		items = append(items, toItem(feedback))
+	}
+
+	subject := fmt.Sprintf("Your %s feedback digest: %d new", frequency, len(items))
+	msg, err := render(person.Email, subject, emailData{Name: person.Name, Period: frequency, Items: items})
+	if err == nil {
+		err = n.Mailer.Send(msg)
+	}
+	if err != nil {
+		markFailed(ids, err)
+		return false, err
+	}
+
+	markSent(ids)
+	return true, nil
+}
+
+// unsent scopes a query to notifications that are still to be emailed and
+// have attempts left.
+func unsent(db *gorm.DB) *gorm.DB {
+	return db.Where("sent_at IS NULL AND attempts < ?", MaxAttempts)
+}
+
+func markSent(ids []uint) {
+	database.GetDB().Model(&models.EmailNotification{}).Where("id IN ?", ids).
+		Updates(map[string]interface{}{"sent_at": time.Now(), "error": ""})
+}
+
+func markFailed(ids []uint, err error) {
+	database.GetDB().Model(&models.EmailNotification{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+		"error":           err.Error(),
+		"attempts":        gorm.Expr("attempts + 1"),
+		"last_attempt_at": time.Now(),
+	})
+}
+
+func toItem(feedback models.Feedback) feedbackItem {
+	item := feedbackItem{Category: feedback.Category, Content: feedback.Content, CreatedAt: feedback.CreatedAt}
+	if feedback.TargetType == "team" {
+		item.TeamName = feedback.TargetName
+	}
+	if feedback.AuthorID != nil {
+		var author models.Person
+		if err := database.GetDB().Select("name").First(&author, *feedback.AuthorID).Error; err == nil {
+			item.Author = author.Name
+		}
+	}
+	return item
+}
+
+func subjectFor(item feedbackItem) string {
+	if item.TeamName != "" {
+		return fmt.Sprintf("New feedback for your team %s", item.TeamName)
+	}
+	if item.Author != "" {
+		return fmt.Sprintf("New feedback from %s", item.Author)
+	}
+	return "You received new feedback"
+}
+
+func decodeFeedback(event events.Event) (models.Feedback, error) {
+	var feedback models.Feedback
+
+	raw, ok := event.Data.(json.RawMessage)
+	if !ok {
+		var err error
+		if raw, err = json.Marshal(event.Data); err != nil {
+			return feedback, err
+		}
+	}
+	err := json.Unmarshal(raw, &feedback)
+	return feedback, err
+}
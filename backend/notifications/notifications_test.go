@@ -0,0 +1,465 @@
+package notifications
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"coaching-backend/database"
+	"coaching-backend/events"
+	"coaching-backend/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type receivedMail struct {
+	From    string
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+type fakeSMTPServer struct {
+	listener   net.Listener
+	rejectRcpt bool
+
+	mu       sync.Mutex
+	received []receivedMail
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to start fake SMTP server: %v", err)
+	}
+	server := &fakeSMTPServer{listener: listener}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(t, conn)
+		}
+	}()
+	return server
+}
+
+func (s *fakeSMTPServer) mailer() SMTPMailer {
+	host, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	return SMTPMailer{Host: host, Port: port, From: "coaching@example.com"}
+}
+
+func (s *fakeSMTPServer) messages() []receivedMail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]receivedMail(nil), s.received...)
+}
+
+func (s *fakeSMTPServer) serve(t *testing.T, conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	reply("220 fake.smtp ESMTP")
+	var current receivedMail
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		switch verb {
+		case "EHLO", "HELO":
+			reply("250 fake.smtp")
+		case "MAIL":
+			current = receivedMail{From: addressArg(line)}
+			reply("250 OK")
+		case "RCPT":
+			if s.rejectRcpt {
+				reply("550 No such user")
+				continue
+			}
+			current.To = append(current.To, addressArg(line))
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				dataLine, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(dataLine, "."))
+			}
+			if err := parseMail(data.String(), &current); err != nil {
+				t.Errorf("Fake SMTP server received malformed mail: %v", err)
+			}
+			s.mu.Lock()
+			s.received = append(s.received, current)
+			s.mu.Unlock()
+			reply("250 OK")
+		case "RSET", "NOOP":
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+func addressArg(line string) string {
+	_, arg, _ := strings.Cut(line, ":")
+	return strings.Trim(strings.TrimSpace(arg), "<>")
+}
+
+func parseMail(data string, received *receivedMail) error {
+	msg, err := mail.ReadMessage(strings.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if received.Subject, err = new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject")); err != nil {
+		return err
+	}
+
+	_, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		return err
+	}
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		body, err := io.ReadAll(part)
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(part.Header.Get("Content-Type"), "text/html") {
+			received.HTML = string(body)
+		} else {
+			received.Text = string(body)
+		}
+	}
+}
+
+func setupTestDB(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("Failed to access test database: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	database.DB = db
+}
+
+func createPerson(t *testing.T, name, email string, teamID *uint) models.Person {
+	person := models.Person{Name: name, Email: email, TeamID: teamID}
+	if err := database.GetDB().Create(&person).Error; err != nil {
+		t.Fatalf("Failed to create person: %v", err)
+	}
+	return person
+}
+
+func setFrequency(t *testing.T, personID uint, frequency string) {
+	pref := models.NotificationPreference{PersonID: personID, EmailFrequency: frequency}
+	if err := database.GetDB().Create(&pref).Error; err != nil {
+		t.Fatalf("Failed to save preference: %v", err)
+	}
+}
+
+func createFeedback(t *testing.T, content, targetType string, targetID uint, targetName string, authorID *uint) models.Feedback {
+	feedback := models.Feedback{Content: content, TargetType: targetType, TargetID: targetID, TargetName: targetName, AuthorID: authorID}
+	if err := database.GetDB().Create(&feedback).Error; err != nil {
+		t.Fatalf("Failed to create feedback: %v", err)
+	}
+	return feedback
+}
+
+func TestMessageBytes(t *testing.T) {
+	t.Run("should keep the subject on a single header line", func(t *testing.T) {
+		msg := Message{To: "someone@example.com", Subject: "Hello\r\nBcc: victim@example.com", Text: "Hi", HTML: "<p>Hi</p>"}
+
+		body, err := msg.Bytes("coaching@example.com")
+		assert.NoError(t, err)
+
+		parsed, err := mail.ReadMessage(strings.NewReader(string(body)))
+		assert.NoError(t, err)
+		assert.Empty(t, parsed.Header.Get("Bcc"))
+	})
+
+	t.Run("should reject invalid recipients", func(t *testing.T) {
+		_, err := Message{To: "not an address\r\nBcc: x@example.com"}.Bytes("coaching@example.com")
+		assert.Error(t, err)
+	})
+}
+
+func TestNotifierHandle(t *testing.T) {
+	setupTestDB(t)
+	server := newFakeSMTPServer(t)
+	notifier := NewNotifier(server.mailer())
+
+	team := models.Team{Name: "Platform"}
+	assert.NoError(t, database.GetDB().Create(&team).Error)
+	author := createPerson(t, "Alice Author", "alice@example.com", &team.ID)
+	bob := createPerson(t, "Bob Immediate", "bob@example.com", &team.ID)
+	carol := createPerson(t, "Carol Digest", "carol@example.com", &team.ID)
+	dave := createPerson(t, "Dave Off", "dave@example.com", &team.ID)
+	setFrequency(t, carol.ID, models.EmailDaily)
+	setFrequency(t, dave.ID, models.EmailOff)
+
+	t.Run("should email a person right away", func(t *testing.T) {
+		feedback := createFeedback(t, "Great <demo>\nThanks!", "person", bob.ID, bob.Name, &author.ID)
+
+		notifier.Handle(events.New(events.FeedbackCreated, feedback))
+		notifier.Wait()
+
+		received := server.messages()
+		assert.Len(t, received, 1)
+		assert.Equal(t, []string{"bob@example.com"}, received[0].To)
+		assert.Equal(t, "New feedback from Alice Author", received[0].Subject)
+		assert.Contains(t, received[0].Text, "Great <demo>\r\nThanks!")
+		assert.Contains(t, received[0].HTML, "Great &lt;demo&gt;<br>Thanks!")
+
+		var notification models.EmailNotification
+		assert.NoError(t, database.GetDB().Where("person_id = ? AND feedback_id = ?", bob.ID, feedback.ID).First(&notification).Error)
+		assert.NotNil(t, notification.SentAt)
+	})
+
+	t.Run("should notify team members according to their preferences", func(t *testing.T) {
+		before := len(server.messages())
+		feedback := createFeedback(t, "Smooth release", "team", team.ID, team.Name, &author.ID)
+		payload, _ := json.Marshal(feedback)
+		event := events.Event{ID: "evt-team", Type: events.FeedbackCreated, Data: json.RawMessage(payload)}
+
+		notifier.Handle(event)
+		notifier.Wait()
+
+		received := server.messages()[before:]
+		assert.Len(t, received, 1)
+		assert.Equal(t, []string{"bob@example.com"}, received[0].To)
+		assert.Equal(t, "New feedback for your team Platform", received[0].Subject)
+
+		var notifications []models.EmailNotification
+		assert.NoError(t, database.GetDB().Where("feedback_id = ?", feedback.ID).Order("person_id").Find(&notifications).Error)
+		assert.Len(t, notifications, 2)
+		assert.Equal(t, carol.ID, notifications[1].PersonID)
+		assert.Nil(t, notifications[1].SentAt)
+
+		t.Run("and ignore redelivered events", func(t *testing.T) {
+			notifier.Handle(event)
+			notifier.Wait()
+
+			assert.Len(t, server.messages(), before+1)
+		})
+	})
+
+	t.Run("should ignore other events", func(t *testing.T) {
+		before := len(server.messages())
+		notifier.Handle(events.New(events.PersonCreated, bob))
+		notifier.Wait()
+
+		assert.Len(t, server.messages(), before)
+	})
+}
+
+func TestNotifierFailure(t *testing.T) {
+	setupTestDB(t)
+	server := newFakeSMTPServer(t)
+	server.rejectRcpt = true
+	notifier := NewNotifier(server.mailer())
+
+	person := createPerson(t, "Unreachable", "unreachable@example.com", nil)
+	feedback := createFeedback(t, "Nice work", "person", person.ID, person.Name, nil)
+
+	notifier.Handle(events.New(events.FeedbackCreated, feedback))
+	notifier.Wait()
+
+	var notification models.EmailNotification
+	assert.NoError(t, database.GetDB().Where("person_id = ?", person.ID).First(&notification).Error)
+	assert.Nil(t, notification.SentAt)
+	assert.Contains(t, notification.Error, "550")
+}
+
+func TestSendDigests(t *testing.T) {
+	setupTestDB(t)
+	server := newFakeSMTPServer(t)
+	notifier := NewNotifier(server.mailer())
+
+	daily := createPerson(t, "Daily Dana", "dana@example.com", nil)
+	weekly := createPerson(t, "Weekly Will", "will@example.com", nil)
+	setFrequency(t, daily.ID, models.EmailDaily)
+	setFrequency(t, weekly.ID, models.EmailWeekly)
+
+	for _, content := range []string{"First note", "Second note"} {
+		notifier.Handle(events.New(events.FeedbackCreated, createFeedback(t, content, "person", daily.ID, daily.Name, nil)))
+	}
+	notifier.Handle(events.New(events.FeedbackCreated, createFeedback(t, "Weekly note", "person", weekly.ID, weekly.Name, nil)))
+	notifier.Wait()
+	assert.Empty(t, server.messages())
+
+	t.Run("should send one digest per person", func(t *testing.T) {
+		sent, err := notifier.SendDigests(models.EmailDaily)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, sent)
+
+		received := server.messages()
+		assert.Len(t, received, 1)
+		assert.Equal(t, []string{"dana@example.com"}, received[0].To)
+		assert.Equal(t, "Your daily feedback digest: 2 new", received[0].Subject)
+		assert.Contains(t, received[0].Text, "First note")
+		assert.Contains(t, received[0].Text, "Second note")
+		assert.Contains(t, received[0].HTML, "Second note")
+
+		var pending int64
+		database.GetDB().Model(&models.EmailNotification{}).Where("person_id = ? AND sent_at IS NULL", daily.ID).Count(&pending)
+		assert.Zero(t, pending)
+	})
+
+	t.Run("should not resend a digest", func(t *testing.T) {
+		sent, err := notifier.SendDigests(models.EmailDaily)
+		assert.NoError(t, err)
+		assert.Zero(t, sent)
+	})
+
+	t.Run("should send weekly digests separately", func(t *testing.T) {
+		sent, err := notifier.SendDigests(models.EmailWeekly)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, sent)
+		assert.Equal(t, []string{"will@example.com"}, server.messages()[1].To)
+	})
+
+	t.Run("should reject unknown frequencies", func(t *testing.T) {
+		_, err := notifier.SendDigests(models.EmailImmediate)
+		assert.Error(t, err)
+	})
+}
+
+func TestRetryUnsent(t *testing.T) {
+	setupTestDB(t)
+	server := newFakeSMTPServer(t)
+	server.rejectRcpt = true
+	notifier := NewNotifier(server.mailer())
+
+	person := createPerson(t, "Flaky Inbox", "flaky@example.com", nil)
+	feedback := createFeedback(t, "Try again", "person", person.ID, person.Name, nil)
+	notifier.Handle(events.New(events.FeedbackCreated, feedback))
+	notifier.Wait()
+
+	var notification models.EmailNotification
+	database.GetDB().Where("person_id = ?", person.ID).First(&notification)
+	assert.Equal(t, 1, notification.Attempts)
+	assert.NotNil(t, notification.LastAttemptAt)
+
+	t.Run("should wait before retrying", func(t *testing.T) {
+		sent, err := notifier.RetryUnsent(time.Now())
+		assert.NoError(t, err)
+		assert.Zero(t, sent)
+	})
+
+	t.Run("should resend once the delay has passed", func(t *testing.T) {
+		server.rejectRcpt = false
+		sent, err := notifier.RetryUnsent(time.Now().Add(RetryDelay + time.Second))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, sent)
+		assert.Len(t, server.messages(), 1)
+
+		database.GetDB().First(&notification, notification.ID)
+		assert.NotNil(t, notification.SentAt)
+		assert.Empty(t, notification.Error)
+	})
+
+	t.Run("should give up after the last attempt", func(t *testing.T) {
+		other := createFeedback(t, "Never arrives", "person", person.ID, person.Name, nil)
+		database.GetDB().Create(&models.EmailNotification{PersonID: person.ID, FeedbackID: other.ID, Attempts: MaxAttempts})
+
+		sent, err := notifier.RetryUnsent(time.Now().Add(RetryDelay + time.Second))
+		assert.NoError(t, err)
+		assert.Zero(t, sent)
+	})
+
+	t.Run("should leave digest notifications to the digest", func(t *testing.T) {
+		daily := createPerson(t, "Daily Dora", "dora@example.com", nil)
+		setFrequency(t, daily.ID, models.EmailDaily)
+		notifier.Handle(events.New(events.FeedbackCreated, createFeedback(t, "Later", "person", daily.ID, daily.Name, nil)))
+		notifier.Wait()
+
+		sent, err := notifier.RetryUnsent(time.Now().Add(RetryDelay + time.Second))
+		assert.NoError(t, err)
+		assert.Zero(t, sent)
+	})
+}
+
+func TestSendDueDigests(t *testing.T) {
+	setupTestDB(t)
+	server := newFakeSMTPServer(t)
+	notifier := NewNotifier(server.mailer())
+
+	person := createPerson(t, "Daily Dan", "dan@example.com", nil)
+	setFrequency(t, person.ID, models.EmailDaily)
+	notifier.Handle(events.New(events.FeedbackCreated, createFeedback(t, "First", "person", person.ID, person.Name, nil)))
+	notifier.Wait()
+
+	t.Run("should wait a full period for the first digest", func(t *testing.T) {
+		sent, err := notifier.SendDueDigests(models.EmailDaily, time.Now().Add(23*time.Hour))
+		assert.NoError(t, err)
+		assert.Zero(t, sent)
+
+		sent, err = notifier.SendDueDigests(models.EmailDaily, time.Now().Add(25*time.Hour))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, sent)
+		assert.Len(t, server.messages(), 1)
+	})
+
+	t.Run("should count the next period from the last digest", func(t *testing.T) {
+		notifier.Handle(events.New(events.FeedbackCreated, createFeedback(t, "Second", "person", person.ID, person.Name, nil)))
+		notifier.Wait()
+
+		sent, err := notifier.SendDueDigests(models.EmailDaily, time.Now().Add(time.Hour))
+		assert.NoError(t, err)
+		assert.Zero(t, sent)
+
+		sent, err = notifier.SendDueDigests(models.EmailDaily, time.Now().Add(25*time.Hour))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, sent)
+		assert.Contains(t, server.messages()[1].Text, "Second")
+	})
+
+	t.Run("should not send weekly digests to daily readers", func(t *testing.T) {
+		notifier.Handle(events.New(events.FeedbackCreated, createFeedback(t, "Third", "person", person.ID, person.Name, nil)))
+		notifier.Wait()
+
+		sent, err := notifier.SendDueDigests(models.EmailWeekly, time.Now().Add(30*24*time.Hour))
+		assert.NoError(t, err)
+		assert.Zero(t, sent)
+	})
+}
@@ -0,0 +1,76 @@
+package notifications
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+type feedbackItem struct {
+	TeamName  string
+	Author    string
+	Category  string
+	Content   string
+	CreatedAt time.Time
+}
+
+type emailData struct {
+	Name   string
+	Period string
+	Items  []feedbackItem
+}
+
+var templateFuncs = map[string]interface{}{
+	"date": func(t time.Time) string { return t.Format("Mon 2 Jan 2006, 15:04") },
+	"lines": func(s string) []string {
+		return strings.Split(strings.TrimSpace(s), "\n")
+	},
+}
+
+const textTemplate = `Hi {{.Name}},
+{{if .Period}}
+Here is your {{.Period}} feedback digest with {{len .Items}} new {{if eq (len .Items) 1}}entry{{else}}entries{{end}}.
+{{else}}
+You have received new feedback.
+{{end}}{{range .Items}}
+----------------------------------------
+{{if .TeamName}}For your team {{.TeamName}}{{else}}For you{{end}}{{if .Author}}, from {{.Author}}{{end}} - {{date .CreatedAt}}{{if .Category}} [{{.Category}}]{{end}}
+
+{{.Content}}
+{{end}}
+----------------------------------------
+You can change how often you receive these emails in your notification preferences.
+`
+
+const htmlTemplate = `<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif; color: #222; max-width: 600px;">
+<p>Hi {{.Name}},</p>
+{{if .Period}}<p>Here is your {{.Period}} feedback digest with {{len .Items}} new {{if eq (len .Items) 1}}entry{{else}}entries{{end}}.</p>
+{{else}}<p>You have received new feedback.</p>
+{{end}}{{range .Items}}<div style="border-left: 3px solid #4a7; padding: 4px 12px; margin: 16px 0;">
+<p style="color: #666; font-size: 13px;">{{if .TeamName}}For your team <strong>{{.TeamName}}</strong>{{else}}For you{{end}}{{if .Author}}, from {{.Author}}{{end}} &middot; {{date .CreatedAt}}{{if .Category}} &middot; {{.Category}}{{end}}</p>
+<p>{{range $i, $line := lines .Content}}{{if $i}}<br>{{end}}{{$line}}{{end}}</p>
+</div>
+{{end}}<p style="color: #888; font-size: 12px;">You can change how often you receive these emails in your notification preferences.</p>
+</body>
+</html>
+`
+
+var (
+	textEmail = texttemplate.Must(texttemplate.New("text").Funcs(templateFuncs).Parse(textTemplate))
+	htmlEmail = htmltemplate.Must(htmltemplate.New("html").Funcs(templateFuncs).Parse(htmlTemplate))
+)
+
+func render(to, subject string, data emailData) (Message, error) {
+	var text, html bytes.Buffer
+	if err := textEmail.Execute(&text, data); err != nil {
+		return Message{}, err
+	}
+	if err := htmlEmail.Execute(&html, data); err != nil {
+		return Message{}, err
+	}
+	return Message{To: to, Subject: subject, Text: text.String(), HTML: html.String()}, nil
+}
@@ -0,0 +1,191 @@
+package outbox
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+	"coaching-backend/database"
+	"coaching-backend/encryption"
+	"coaching-backend/events"
+	"coaching-backend/models"
+	"gorm.io/gorm"
+)
+
+const (
+	PollInterval  = 2 * time.Second
+	PruneInterval = time.Hour
+
+	batchSize = 100
+)
+
+var (
+	Retention = 7 * 24 * time.Hour
+
+	wake    = make(chan struct{}, 1)
+	flushMu sync.Mutex
+)
+
+func Add(tx *gorm.DB, eventType string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	event := events.New(eventType, nil)
+	return tx.Create(&models.OutboxEvent{
+		EventID:    event.ID,
+		Type:       event.Type,
+		Payload:    string(payload),
+		OccurredAt: event.OccurredAt,
+	}).Error
+}
+
+func Notify() {
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+}
+
+func Run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastPrune time.Time
+	for {
+		if _, err := Flush(); err != nil {
+			log.Printf("outbox: failed to publish pending events: %v", err)
+		}
+		if time.Since(lastPrune) >= PruneInterval {
+			if _, err := Prune(time.Now()); err != nil {
+				log.Printf("outbox: failed to prune published events: %v", err)
+			}
+			lastPrune = time.Now()
+		}
+
+		select {
+		case <-ticker.C:
+		case <-wake:
+		}
+	}
+}
+
+func ToEvent(row models.OutboxEvent) events.Event {
+	event := events.Event{
+		ID:         row.EventID,
+		Type:       row.Type,
+		OccurredAt: row.OccurredAt,
+		Data:       json.RawMessage(row.Payload),
+	}
+	if row.Sequence != nil {
+		event.Sequence = *row.Sequence
+	}
+	return event
+}
+
+func Published(after uint, limit int) ([]events.Event, error) {
+	var rows []models.OutboxEvent
+	if err := database.GetDB().Where("sequence > ? AND published_at IS NOT NULL", after).
+		Order("sequence").Limit(limit).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	published := make([]events.Event, 0, len(rows))
+	for _, row := range rows {
+		published = append(published, ToEvent(row))
+	}
+	return published, nil
+}
+
+// Scan calls fn for every stored event of the given types, or of every type
+// when none are given, in batches. Payloads are encrypted, so they have to be
+// searched after loading rather than in SQL.
+func Scan(tx *gorm.DB, types []string, fn func(row models.OutboxEvent) error) error {
+	var lastID uint
+	for {
+		query := tx.Where("id > ?", lastID)
+		if len(types) > 0 {
+			query = query.Where("type IN ?", types)
+		}
+		var rows []models.OutboxEvent
+		if err := query.Order("id").Limit(batchSize).Find(&rows).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		for _, row := range rows {
+			if err := fn(row); err != nil {
+				return err
+			}
+			lastID = row.ID
+		}
+	}
+}
+
+// SetPayload replaces the payload of a stored event.
+func SetPayload(tx *gorm.DB, id uint, payload string) error {
+	encrypted, err := encryption.Encrypt("payload", payload)
+	if err != nil {
+		return err
+	}
+	return tx.Model(&models.OutboxEvent{}).Where("id = ?", id).UpdateColumn("payload", encrypted).Error
+}
+
+// Flush publishes pending events. Each event gets the next sequence before it
+// is published, so a transaction that commits late is published after the
+// events already sent rather than slotted in behind them. An event that was
+// numbered but not marked published is sent again first, with its sequence.
+func Flush() (int, error) {
+	flushMu.Lock()
+	defer flushMu.Unlock()
+
+	var last uint
+	if err := database.GetDB().Model(&models.OutboxEvent{}).Select("COALESCE(MAX(sequence), 0)").Scan(&last).Error; err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for {
+		var pending []models.OutboxEvent
+		if err := database.GetDB().Where("published_at IS NULL").Order("sequence IS NULL, sequence, id").
+			Limit(batchSize).Find(&pending).Error; err != nil {
+			return published, err
+		}
+		if len(pending) == 0 {
+			return published, nil
+		}
+
+		for _, row := range pending {
+			if row.Sequence == nil {
+				sequence := last + 1
+				if err := database.GetDB().Model(&models.OutboxEvent{}).Where("id = ? AND sequence IS NULL", row.ID).
+					Update("sequence", sequence).Error; err != nil {
+					return published, err
+				}
+				row.Sequence = &sequence
+				last = sequence
+			}
+			events.Publish(ToEvent(row))
+
+			if err := database.GetDB().Model(&models.OutboxEvent{}).Where("id = ?", row.ID).
+				Update("published_at", time.Now()).Error; err != nil {
+				return published, err
+			}
+			published++
+		}
+	}
+}
+
+// Prune deletes events published before the retention window. Events that
+// still have a webhook delivery queued are kept so the retry can load them.
+func Prune(now time.Time) (int64, error) {
+	queued := database.GetDB().Model(&models.WebhookDelivery{}).
+		Select("event_id").Where("next_attempt_at IS NOT NULL")
+	result := database.GetDB().
+		Where("published_at IS NOT NULL AND published_at < ?", now.Add(-Retention)).
+		Where("event_id NOT IN (?)", queued).
+		Delete(&models.OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,182 @@
+package outbox
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"coaching-backend/database"
+	"coaching-backend/events"
+	"coaching-backend/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTestDB(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	database.DB = db
+}
+
+func collect(t *testing.T) *[]events.Event {
+	var received []events.Event
+	unsubscribe := events.Subscribe(func(e events.Event) {
+		received = append(received, e)
+	})
+	t.Cleanup(unsubscribe)
+	return &received
+}
+
+func TestAdd(t *testing.T) {
+	t.Run("should write the event with the transaction", func(t *testing.T) {
+		setupTestDB(t)
+
+		err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&models.Team{Name: "Outbox Team"}).Error; err != nil {
+				return err
+			}
+			return Add(tx, events.TeamCreated, map[string]string{"name": "Outbox Team"})
+		})
+		assert.NoError(t, err)
+
+		var rows []models.OutboxEvent
+		database.GetDB().Find(&rows)
+		assert.Len(t, rows, 1)
+		assert.Equal(t, events.TeamCreated, rows[0].Type)
+		assert.JSONEq(t, `{"name":"Outbox Team"}`, rows[0].Payload)
+		assert.NotEmpty(t, rows[0].EventID)
+		assert.Nil(t, rows[0].PublishedAt)
+	})
+
+	t.Run("should drop the event when the transaction rolls back", func(t *testing.T) {
+		setupTestDB(t)
+
+		err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+			if err := Add(tx, events.TeamCreated, nil); err != nil {
+				return err
+			}
+			return errors.New("boom")
+		})
+		assert.Error(t, err)
+
+		var count int64
+		database.GetDB().Model(&models.OutboxEvent{}).Count(&count)
+		assert.Equal(t, int64(0), count)
+	})
+}
+
+func TestFlush(t *testing.T) {
+	setupTestDB(t)
+	received := collect(t)
+
+	for _, eventType := range []string{events.PersonCreated, events.TeamCreated, events.PersonAssignedToTeam} {
+		assert.NoError(t, Add(database.GetDB(), eventType, map[string]string{"type": eventType}))
+	}
+
+	published, err := Flush()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, published)
+
+	var rows []models.OutboxEvent
+	database.GetDB().Order("id").Find(&rows)
+	assert.Len(t, *received, 3)
+	for i, e := range *received {
+		assert.Equal(t, rows[i].EventID, e.ID)
+		assert.Equal(t, rows[i].Type, e.Type)
+		assert.NotNil(t, rows[i].PublishedAt)
+
+		body, err := json.Marshal(e)
+		assert.NoError(t, err)
+		assert.Contains(t, string(body), `"data":{"type":"`+e.Type+`"}`)
+	}
+
+	published, err = Flush()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, published)
+	assert.Len(t, *received, 3)
+}
+
+func TestFlushRepublishesUnmarkedEvents(t *testing.T) {
+	setupTestDB(t)
+	received := collect(t)
+
+	assert.NoError(t, Add(database.GetDB(), events.FeedbackCreated, nil))
+	_, err := Flush()
+	assert.NoError(t, err)
+
+	database.GetDB().Model(&models.OutboxEvent{}).Where("1 = 1").Update("published_at", nil)
+	_, err = Flush()
+	assert.NoError(t, err)
+
+	assert.Len(t, *received, 2)
+	assert.Equal(t, (*received)[0].ID, (*received)[1].ID)
+	assert.Equal(t, (*received)[0].Sequence, (*received)[1].Sequence)
+}
+
+func TestFlushNumbersEventsInPublishOrder(t *testing.T) {
+	setupTestDB(t)
+	received := collect(t)
+
+	for _, eventType := range []string{events.TeamCreated, events.TeamUpdated, events.TeamDeleted} {
+		assert.NoError(t, Add(database.GetDB(), eventType, nil))
+	}
+	// The first row stands in for a transaction that commits after the others
+	// were published.
+	var late models.OutboxEvent
+	database.GetDB().Order("id").First(&late)
+	database.GetDB().Delete(&late)
+
+	_, err := Flush()
+	assert.NoError(t, err)
+	assert.NoError(t, database.GetDB().Create(&late).Error)
+	_, err = Flush()
+	assert.NoError(t, err)
+
+	assert.Len(t, *received, 3)
+	for i, e := range *received {
+		assert.Equal(t, uint(i+1), e.Sequence)
+	}
+	assert.Equal(t, late.EventID, (*received)[2].ID)
+
+	replay, err := Published(2, 10)
+	assert.NoError(t, err)
+	assert.Len(t, replay, 1)
+	assert.Equal(t, late.EventID, replay[0].ID)
+}
+
+func TestPrune(t *testing.T) {
+	setupTestDB(t)
+
+	for _, eventType := range []string{events.TeamCreated, events.TeamUpdated, events.TeamDeleted, events.PersonCreated} {
+		assert.NoError(t, Add(database.GetDB(), eventType, nil))
+	}
+	var rows []models.OutboxEvent
+	database.GetDB().Order("id").Find(&rows)
+
+	old := time.Now().Add(-Retention - time.Hour)
+	recent := time.Now().Add(-time.Hour)
+	database.GetDB().Model(&rows[0]).Update("published_at", old)
+	database.GetDB().Model(&rows[1]).Update("published_at", old)
+	database.GetDB().Model(&rows[2]).Update("published_at", recent)
+
+	due := time.Now()
+	database.GetDB().Create(&models.WebhookDelivery{SubscriptionID: 1, EventID: rows[1].EventID, EventType: rows[1].Type, Attempt: 2, NextAttemptAt: &due})
+
+	pruned, err := Prune(time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), pruned)
+
+	var remaining []models.OutboxEvent
+	database.GetDB().Order("id").Find(&remaining)
+	assert.Len(t, remaining, 3)
+	assert.Equal(t, rows[1].ID, remaining[0].ID)
+	assert.Equal(t, rows[2].ID, remaining[1].ID)
+	assert.Equal(t, rows[3].ID, remaining[2].ID)
+}
@@ -0,0 +1,214 @@
+package pdf
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// A4 in points.
+const (
+	PageWidth    = 595.28
+	PageHeight   = 841.89
+	Margin       = 50.0
+	ContentWidth = PageWidth - 2*Margin
+)
+
+const (
+	FontRegular = "F1"
+	FontBold    = "F2"
+)
+
+const (
+	lineSpacing  = 1.35
+	footerHeight = 20.0
+	barLabel     = 110.0
+	barCaption   = 70.0
+)
+
+var baseFonts = map[string]string{
+	FontRegular: "Helvetica",
+	FontBold:    "Helvetica-Bold",
+}
+
+type Style struct {
+	Font   string
+	Size   float64
+	Gray   float64
+	Indent float64
+}
+
+type Document struct {
+	title   string
+	created time.Time
+	pages   []*bytes.Buffer
+	y       float64
+}
+
+func New(title string, created time.Time) *Document {
+	d := &Document{title: title, created: created}
+	d.addPage()
+	return d
+}
+
+func (d *Document) Pages() int {
+	return len(d.pages)
+}
+
+func (d *Document) page() *bytes.Buffer {
+	return d.pages[len(d.pages)-1]
+}
+
+func (d *Document) addPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.y = PageHeight - Margin
+}
+
+// Ensure starts a new page unless height points still fit above the footer.
+func (d *Document) Ensure(height float64) {
+	if d.y-height < Margin+footerHeight {
+		d.addPage()
+	}
+}
+
+func (d *Document) Space(height float64) {
+	d.y -= height
+	if d.y < Margin+footerHeight {
+		d.addPage()
+	}
+}
+
+func (d *Document) Text(style Style, text string) {
+	lineHeight := style.Size * lineSpacing
+	for _, line := range Wrap(style.Font, style.Size, text, ContentWidth-style.Indent) {
+		d.Ensure(lineHeight)
+		d.text(style, Margin+style.Indent, d.y-style.Size, line)
+		d.y -= lineHeight
+	}
+}
+
+func (d *Document) Rule() {
+	d.Ensure(10)
+	fmt.Fprintf(d.page(), "0.8 G 0.5 w %.2f %.2f m %.2f %.2f l S\n", Margin, d.y-5, PageWidth-Margin, d.y-5)
+	d.y -= 10
+}
+
+// Bar draws a labelled horizontal bar filled to value/max with a caption to its right.
+func (d *Document) Bar(label string, value, max float64, caption string) {
+	const height = 10.0
+	style := Style{Font: FontRegular, Size: 9}
+	d.Ensure(height + 6)
+
+	baseline := d.y - height + 2
+	d.text(style, Margin, baseline, truncate(style, label, barLabel-6))
+
+	x, width := Margin+barLabel, ContentWidth-barLabel-barCaption
+	fmt.Fprintf(d.page(), "0.92 g %.2f %.2f %.2f %.2f re f\n", x, d.y-height, width, height)
+	if max > 0 && value > 0 {
+		if value > max {
+			value = max
+		}
+		fmt.Fprintf(d.page(), "0.24 0.47 0.75 rg %.2f %.2f %.2f %.2f re f\n", x, d.y-height, width*value/max, height)
+	}
+	d.text(style, x+width+6, baseline, caption)
+	d.y -= height + 6
+}
+
+func (d *Document) text(style Style, x, y float64, s string) {
+	page := d.page()
+	fmt.Fprintf(page, "BT %.2f g /%s %.2f Tf %.2f %.2f Td (", style.Gray, style.Font, style.Size, x, y)
+	page.Write(escape(encode(s)))
+	page.WriteString(") Tj ET\n")
+}
+
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	pw := &writer{w: bufio.NewWriter(w)}
+	pw.printf("%%PDF-1.4\n%%\xe2\xe3\xcf\xd3\n")
+
+	// Objects 1-5 are fixed; each page then takes a page and a content object.
+	const firstPage = 6
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	pw.object("<< /Type /Catalog /Pages 2 0 R >>")
+	pw.object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	pw.object(fontObject(FontRegular))
+	pw.object(fontObject(FontBold))
+	pw.object(fmt.Sprintf("<< /Title (%s) /Producer (coaching-backend) /CreationDate (D:%s) >>",
+		escape(encode(d.title)), d.created.UTC().Format("20060102150405Z")))
+
+	for i, page := range d.pages {
+		pw.object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			PageWidth, PageHeight, firstPage+2*i+1))
+
+		content := append([]byte{}, page.Bytes()...)
+		content = append(content, d.footer(i+1)...)
+		pw.object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+	}
+
+	xref := pw.n
+	pw.printf("xref\n0 %d\n0000000000 65535 f \n", len(pw.offsets)+1)
+	for _, offset := range pw.offsets {
+		pw.printf("%010d 00000 n \n", offset)
+	}
+	pw.printf("trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(pw.offsets)+1, xref)
+
+	if pw.err == nil {
+		pw.err = pw.w.Flush()
+	}
+	return pw.n, pw.err
+}
+
+func (d *Document) footer(number int) []byte {
+	var b bytes.Buffer
+	style := Style{Font: FontRegular, Size: 8, Gray: 0.45}
+	pageLabel := fmt.Sprintf("Page %d of %d", number, len(d.pages))
+
+	fmt.Fprintf(&b, "BT %.2f g /%s %.2f Tf %.2f %.2f Td (", style.Gray, style.Font, style.Size, Margin, Margin-10)
+	b.Write(escape(encode(truncate(style, d.title, ContentWidth-80))))
+	b.WriteString(") Tj ET\n")
+	fmt.Fprintf(&b, "BT %.2f g /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", style.Gray, style.Font, style.Size,
+		PageWidth-Margin-Width(style.Font, style.Size, pageLabel), Margin-10, pageLabel)
+	return b.Bytes()
+}
+
+func fontObject(font string) string {
+	return fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", baseFonts[font])
+}
+
+type writer struct {
+	w       *bufio.Writer
+	n       int64
+	offsets []int64
+	err     error
+}
+
+func (pw *writer) printf(format string, args ...interface{}) {
+	if pw.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(pw.w, format, args...)
+	pw.n += int64(n)
+	pw.err = err
+}
+
+func (pw *writer) object(body string) {
+	pw.offsets = append(pw.offsets, pw.n)
+	pw.printf("%d 0 obj\n%s\nendobj\n", len(pw.offsets), body)
+}
+
+func escape(b []byte) []byte {
+	out := make([]byte, 0, len(b))
+	for _, c := range b {
+		if c == '\\' || c == '(' || c == ')' {
+			out = append(out, '\\')
+		}
+		out = append(out, c)
+	}
+	return out
+}
@@ -0,0 +1,88 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func render(t *testing.T, d *Document) string {
+	var b bytes.Buffer
+	n, err := d.WriteTo(&b)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(b.Len()), n)
+	return b.String()
+}
+
+func TestWriteTo(t *testing.T) {
+	d := New("Report (draft)", time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC))
+	d.Text(Style{Font: FontBold, Size: 14}, `Hello (world) \ café — “quoted”`)
+	d.Rule()
+	d.Bar("Score", 3, 5, "3.0")
+	out := render(t, d)
+
+	assert.True(t, strings.HasPrefix(out, "%PDF-1.4\n"))
+	assert.True(t, strings.HasSuffix(out, "%%EOF\n"))
+	assert.Contains(t, out, `(Hello \(world\) \\ caf`+"\xe9 \x97 \x93quoted\x94) Tj")
+	assert.Contains(t, out, "/Title (Report \\(draft\\))")
+	assert.Contains(t, out, "/CreationDate (D:20261018090000Z)")
+	assert.Contains(t, out, "(Page 1 of 1) Tj")
+
+	// Every cross-reference entry must point at the start of its object.
+	xref := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(out)
+	assert.NotNil(t, xref)
+	start, _ := strconv.Atoi(xref[1])
+	assert.True(t, strings.HasPrefix(out[start:], "xref\n0 8\n"))
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(out[start:], -1)
+	assert.Len(t, entries, 7)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(entry[1])
+		assert.True(t, strings.HasPrefix(out[offset:], fmt.Sprintf("%d 0 obj\n", i+1)), "object %d", i+1)
+	}
+
+	// Stream lengths must match the bytes between stream and endstream.
+	stream := regexp.MustCompile(`(?s)<< /Length (\d+) >>\nstream\n(.*?)\nendstream`).FindStringSubmatch(out)
+	assert.NotNil(t, stream)
+	assert.Equal(t, stream[1], strconv.Itoa(len(stream[2])))
+}
+
+func TestPageBreaks(t *testing.T) {
+	d := New("Long", time.Now())
+	for i := 0; i < 120; i++ {
+		d.Text(Style{Font: FontRegular, Size: 10}, fmt.Sprintf("Line %d", i))
+	}
+	assert.Equal(t, 3, d.Pages())
+
+	out := render(t, d)
+	assert.Contains(t, out, "/Count 3")
+	assert.Contains(t, out, "(Page 3 of 3) Tj")
+	assert.Contains(t, out, "(Line 119) Tj")
+}
+
+func TestWrap(t *testing.T) {
+	assert.Equal(t, 278.0, Width(FontRegular, 1000, " "))
+	assert.InDelta(t, 12.22, Width(FontBold, 10, "Wi"), 0.001)
+
+	lines := Wrap(FontRegular, 10, "The quick brown fox jumps over the lazy dog", 100)
+	assert.Equal(t, []string{"The quick brown fox", "jumps over the lazy", "dog"}, lines)
+	for _, line := range lines {
+		assert.LessOrEqual(t, Width(FontRegular, 10, line), 100.0)
+	}
+
+	assert.Equal(t, []string{"first", "", "second"}, Wrap(FontRegular, 10, "first\r\n\nsecond", 100))
+
+	long := Wrap(FontRegular, 10, strings.Repeat("w", 40), 100)
+	assert.Equal(t, strings.Repeat("w", 40), strings.Join(long, ""))
+	assert.Greater(t, len(long), 1)
+}
+
+func TestEncode(t *testing.T) {
+	assert.Equal(t, []byte("a b\xe9\x80?"), encode("a\tbé€漢"))
+}
@@ -0,0 +1,390 @@
+package privacy
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+	"coaching-backend/encryption"
+	"coaching-backend/events"
+	"coaching-backend/inbox"
+	"coaching-backend/models"
+	"coaching-backend/outbox"
+	"gorm.io/gorm"
+)
+
+var ErrAlreadyErased = errors.New("Person has already been erased")
+
+type Archive struct {
+	PersonID    uint      `json:"person_id"`
+	RequestedBy string    `json:"requested_by"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Sections    []Section `json:"-"`
+}
+
+type Section struct {
+	Name    string
+	Records interface{}
+	Count   int
+}
+
+type TeamMemberships struct {
+	CurrentTeam *models.Team     `json:"current_team"`
+	History     []TeamMembership `json:"history"`
+}
+
+type TeamMembership struct {
+	TeamID   uint      `json:"team_id"`
+	TeamName string    `json:"team_name,omitempty"`
+	Change   string    `json:"change"`
+	At       time.Time `json:"at"`
+}
+
+// Export collects everything stored about a person and records the access in the
+// audit trail. The audit entry is part of the archive it describes.
+func Export(db *gorm.DB, personID uint, requestedBy string, now time.Time) (*Archive, error) {
+	archive := &Archive{PersonID: personID, RequestedBy: requestedBy, GeneratedAt: now.UTC()}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var person models.Person
+		if err := tx.Preload("Team").First(&person, personID).Error; err != nil {
+			return err
+		}
+
+		entry := models.PrivacyAuditEntry{PersonID: person.ID, Action: models.PrivacyActionExport, RequestedBy: requestedBy}
+		if err := tx.Create(&entry).Error; err != nil {
+			return err
+		}
+
+		archive.add("profile", person)
+		memberships, err := teamMemberships(tx, person)
+		if err != nil {
+			return err
+		}
+		archive.add("team_memberships", memberships)
+
+		id := person.ID
+		queries := []struct {
+			name    string
+			records interface{}
+			query   *gorm.DB
+		}{
+			{"feedback_received", &[]models.Feedback{}, tx.Preload("Answers").Where("target_type = ? AND target_id = ?", "person", id)},
+			{"feedback_given", &[]models.Feedback{}, tx.Preload("Answers").Where("author_id = ?", id)},
+			{"feedback_requests", &[]models.FeedbackRequest{}, tx.Where("requester_id = ? OR recipient_id = ?", id, id)},
+			{"review_participations", &[]models.ReviewParticipant{}, tx.Where("person_id = ?", id)},
+			{"review_assignments", &[]models.ReviewAssignment{}, tx.Where("reviewer_id = ? OR reviewee_id = ?", id, id)},
+			{"coaching_sessions", &[]models.CoachingSession{}, tx.Where("coach_id = ? OR coachee_id = ?", id, id)},
+			{"goals", &[]models.Goal{}, tx.Preload("KeyResults").Preload("CheckIns").Where("target_type = ? AND target_id = ?", "person", id)},
+			{"goal_check_ins", &[]models.GoalCheckIn{}, tx.Where("author_id = ?", id)},
+			{"action_items", &[]models.ActionItem{}, tx.Where("owner_id = ?", id)},
+			{"skill_assessments", &[]models.SkillAssessment{}, tx.Preload("Skill").Where("person_id = ?", id)},
+			{"health_check_participations", &[]models.HealthParticipation{}, tx.Where("person_id = ?", id)},
+			{"notifications", &[]models.Notification{}, tx.Where("person_id = ?", id)},
+			{"notification_preferences", &[]models.NotificationPreference{}, tx.Where("person_id = ?", id)},
+			{"email_notifications", &[]models.EmailNotification{}, tx.Where("person_id = ?", id)},
+			{"privacy_audit", &[]models.PrivacyAuditEntry{}, tx.Where("person_id = ?", id)},
+		}
+		for _, q := range queries {
+			if err := q.query.Order("id").Find(q.records).Error; err != nil {
+				return fmt.Errorf("%s: %w", q.name, err)
+			}
+			archive.add(q.name, reflect.ValueOf(q.records).Elem().Interface())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return archive, nil
+}
+
+func (a *Archive) add(name string, records interface{}) {
+	count := 1
+	if v := reflect.ValueOf(records); v.Kind() == reflect.Slice {
+		count = v.Len()
+	}
+	a.Sections = append(a.Sections, Section{Name: name, Records: records, Count: count})
+}
+
+// WriteZip writes one JSON file per section and a manifest listing them.
+func (a *Archive) WriteZip(w io.Writer) error {
+	zw := zip.NewWriter(w)
+	files := map[string]int{}
+	for _, s := range a.Sections {
+		files[s.Name+".json"] = s.Count
+		if err := writeJSON(zw, s.Name+".json", s.Records); err != nil {
+			return err
+		}
+	}
+
+	manifest := struct {
+		*Archive
+		Files map[string]int `json:"files"`
+	}{a, files}
+	if err := writeJSON(zw, "manifest.json", manifest); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+func writeJSON(zw *zip.Writer, name string, v interface{}) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// RecordMembership adds a change to a person's team history. It runs in the
+// transaction that moves the person.
+func RecordMembership(tx *gorm.DB, personID, teamID uint, change string) error {
+	var team models.Team
+	if err := tx.Select("id, name").First(&team, teamID).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return tx.Create(&models.TeamMembershipChange{PersonID: personID, TeamID: teamID, TeamName: team.Name, Change: change}).Error
+}
+
+// RecordAssignment records a person joining a team, and leaving the previous
+// one when they moved. Assigning a person to their current team is no change.
+func RecordAssignment(tx *gorm.DB, personID uint, previousTeamID *uint, teamID uint) error {
+	if previousTeamID != nil {
+		if *previousTeamID == teamID {
+			return nil
+		}
+		if err := RecordMembership(tx, personID, *previousTeamID, models.MembershipRemoved); err != nil {
+			return err
+		}
+	}
+	return RecordMembership(tx, personID, teamID, models.MembershipAdded)
+}
+
+// BackfillMemberships fills an empty history from the team events still in
+// the outbox, which was the only record of memberships before the history
+// table existed.
+func BackfillMemberships(db *gorm.DB) (int, error) {
+	var count int64
+	if err := db.Model(&models.TeamMembershipChange{}).Count(&count).Error; err != nil || count > 0 {
+		return 0, err
+	}
+
+	var rows []models.OutboxEvent
+	if err := db.Where("type IN ?", []string{events.PersonAssignedToTeam, events.PersonRemovedFromTeam}).Order("id").Find(&rows).Error; err != nil {
+		return 0, err
+	}
+
+	var changes []models.TeamMembershipChange
+	for _, row := range rows {
+		var payload struct {
+			ID             uint  `json:"id"`
+			TeamID         *uint `json:"team_id"`
+			PreviousTeamID *uint `json:"previous_team_id"`
+			Team           *struct {
+				Name string `json:"name"`
+			} `json:"team"`
+			Person *struct {
+				ID uint `json:"id"`
+			} `json:"person"`
+		}
+		if err := json.Unmarshal([]byte(row.Payload), &payload); err != nil || payload.TeamID == nil {
+			continue
+		}
+
+		change := models.TeamMembershipChange{TeamID: *payload.TeamID, CreatedAt: row.OccurredAt}
+		switch {
+		case row.Type == events.PersonAssignedToTeam:
+			change.PersonID = payload.ID
+			change.Change = models.MembershipAdded
+			if payload.Team != nil {
+				change.TeamName = payload.Team.Name
+			}
+			if payload.PreviousTeamID != nil && *payload.PreviousTeamID != *payload.TeamID {
+				changes = append(changes, models.TeamMembershipChange{
+					PersonID: payload.ID, TeamID: *payload.PreviousTeamID, Change: models.MembershipRemoved, CreatedAt: row.OccurredAt,
+				})
+			}
+		case payload.Person != nil:
+			change.PersonID = payload.Person.ID
+			change.Change = models.MembershipRemoved
+		default:
+			continue
+		}
+		changes = append(changes, change)
+	}
+	if len(changes) == 0 {
+		return 0, nil
+	}
+
+	teamNames := map[uint]string{}
+	for i, change := range changes {
+		if change.TeamName != "" {
+			continue
+		}
+		name, ok := teamNames[change.TeamID]
+		if !ok {
+			var team models.Team
+			if err := db.Select("id, name").First(&team, change.TeamID).Error; err == nil {
+				name = team.Name
+			}
+			teamNames[change.TeamID] = name
+		}
+		changes[i].TeamName = name
+	}
+	err := db.CreateInBatches(&changes, 100).Error
+	return len(changes), err
+}
+
+func teamMemberships(tx *gorm.DB, person models.Person) (TeamMemberships, error) {
+	memberships := TeamMemberships{CurrentTeam: person.Team, History: []TeamMembership{}}
+
+	var changes []models.TeamMembershipChange
+	if err := tx.Where("person_id = ?", person.ID).Order("id").Find(&changes).Error; err != nil {
+		return memberships, err
+	}
+	for _, change := range changes {
+		memberships.History = append(memberships.History, TeamMembership{
+			TeamID:   change.TeamID,
+			TeamName: change.TeamName,
+			Change:   change.Change,
+			At:       change.CreatedAt,
+		})
+	}
+	return memberships, nil
+}
+
+func ErasedEmail(personID uint) string {
+	return fmt.Sprintf("erased-%d@erased.invalid", personID)
+}
+
+// Erase anonymizes a person in place, so their feedback still counts towards
+// team statistics, and removes their name from everything that copied it.
+func Erase(db *gorm.DB, personID uint, req models.ErasePersonRequest, now time.Time) (models.PrivacyAuditEntry, error) {
+	entry := models.PrivacyAuditEntry{
+		PersonID:    personID,
+		Action:      models.PrivacyActionErasure,
+		RequestedBy: req.RequestedBy,
+		Reason:      req.Reason,
+		Affected:    map[string]int64{},
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var person models.Person
+		if err := tx.First(&person, personID).Error; err != nil {
+			return err
+		}
+
+		erasedEmail := ErasedEmail(person.ID)
+		result := tx.Model(&models.Person{}).Where("id = ? AND erased_at IS NULL", person.ID).Updates(map[string]interface{}{
+			"name":       models.ErasedPersonName,
+			"email":      erasedEmail,
+			"picture":    "",
+			"team_id":    nil,
+			"manager_id": nil,
+			"erased_at":  now,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrAlreadyErased
+		}
+
+		encryptedName, err := encryption.Encrypt("target_name", models.ErasedPersonName)
+		if err != nil {
+			return err
+		}
+
+		type update struct {
+			key   string
+			query *gorm.DB
+			value map[string]interface{}
+		}
+		updates := []update{
+			{"feedback_target_names", tx.Model(&models.Feedback{}).Where("target_type = ? AND target_id = ?", "person", person.ID), map[string]interface{}{"target_name": encryptedName, "target_name_index": encryption.BlindIndex(models.ErasedPersonName)}},
+			{"feedback_authors", tx.Model(&models.Feedback{}).Where("author_id = ?", person.ID), map[string]interface{}{"author_id": nil}},
+			{"goal_target_names", tx.Model(&models.Goal{}).Where("target_type = ? AND target_id = ?", "person", person.ID), map[string]interface{}{"target_name": models.ErasedPersonName}},
+			{"goal_check_in_authors", tx.Model(&models.GoalCheckIn{}).Where("author_id = ?", person.ID), map[string]interface{}{"author_id": nil}},
+		}
+		// Titles that name the person are rebuilt. Notifications written
+		// before actor_id existed are matched by their exact title instead.
+		for _, kind := range inbox.ActorTypes() {
+			title, _ := inbox.ActorTitle(kind, person.Name)
+			erasedTitle, _ := inbox.ActorTitle(kind, models.ErasedPersonName)
+			updates = append(updates, update{"notification_titles", tx.Model(&models.Notification{}).
+				Where("type = ? AND person_id <> ?", kind, person.ID).
+				Where("actor_id = ? OR (actor_id IS NULL AND title = ?)", person.ID, title),
+				map[string]interface{}{"title": erasedTitle, "actor_id": nil}})
+		}
+		for _, u := range updates {
+			result := u.query.Updates(u.value)
+			if result.Error != nil {
+				return fmt.Errorf("%s: %w", u.key, result.Error)
+			}
+			entry.Affected[u.key] += result.RowsAffected
+		}
+
+		deletes := []struct {
+			key   string
+			model interface{}
+		}{
+			{"notifications", &models.Notification{}},
+			{"email_notifications", &models.EmailNotification{}},
+		}
+		for _, d := range deletes {
+			result := tx.Where("person_id = ?", person.ID).Delete(d.model)
+			if result.Error != nil {
+				return fmt.Errorf("%s: %w", d.key, result.Error)
+			}
+			entry.Affected[d.key] = result.RowsAffected
+		}
+
+		// The address is gone, so there is nowhere left to send email to.
+		var preference models.NotificationPreference
+		if err := tx.Where("person_id = ?", person.ID).First(&preference).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		preference.PersonID = person.ID
+		preference.EmailFrequency = models.EmailOff
+		if err := tx.Save(&preference).Error; err != nil {
+			return err
+		}
+
+		// Event payloads were serialized with the person's details at the time.
+		replacer := strings.NewReplacer(jsonString(person.Name), jsonString(models.ErasedPersonName), jsonString(person.Email), jsonString(erasedEmail))
+		n, err := scrubPayloads(tx, replacer)
+		if err != nil {
+			return fmt.Errorf("outbox_events: %w", err)
+		}
+		entry.Affected["outbox_events"] = n
+
+		if err := tx.Create(&entry).Error; err != nil {
+			return err
+		}
+		return outbox.Add(tx, events.PersonErased, map[string]interface{}{"id": person.ID, "erased_at": now})
+	})
+	return entry, err
+}
+
+func scrubPayloads(tx *gorm.DB, replacer *strings.Replacer) (int64, error) {
+	var scrubbed int64
+	err := outbox.Scan(tx, nil, func(row models.OutboxEvent) error {
+		payload := replacer.Replace(row.Payload)
+		if payload == row.Payload {
+			return nil
+		}
+		scrubbed++
+		return outbox.SetPayload(tx, row.ID, payload)
+	})
+	return scrubbed, err
+}
+
+func jsonString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
@@ -0,0 +1,259 @@
+package privacy
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"coaching-backend/database"
+	"coaching-backend/events"
+	"coaching-backend/inbox"
+	"coaching-backend/models"
+	"coaching-backend/outbox"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	database.DB = db
+	return db
+}
+
+type fixture struct {
+	ann, bob models.Person
+	team     models.Team
+	received models.Feedback
+	given    models.Feedback
+}
+
+func seed(t *testing.T, db *gorm.DB) fixture {
+	f := fixture{
+		ann:  models.Person{Name: "Ann O'Lee", Email: "ann@example.com", Picture: "ann.png"},
+		bob:  models.Person{Name: "Bob Other", Email: "bob@example.com"},
+		team: models.Team{Name: "Platform"},
+	}
+	assert.NoError(t, db.Create(&f.team).Error)
+	f.ann.TeamID = &f.team.ID
+	assert.NoError(t, db.Create(&f.ann).Error)
+	assert.NoError(t, db.Create(&f.bob).Error)
+
+	f.received = models.Feedback{Content: "Ann shipped it", TargetType: "person", TargetID: f.ann.ID, TargetName: f.ann.Name, AuthorID: &f.bob.ID}
+	f.given = models.Feedback{Content: "Nice review", TargetType: "person", TargetID: f.bob.ID, TargetName: f.bob.Name, AuthorID: &f.ann.ID}
+	assert.NoError(t, db.Create(&f.received).Error)
+	assert.NoError(t, db.Create(&f.given).Error)
+
+	assert.NoError(t, db.Transaction(func(tx *gorm.DB) error {
+		person := f.ann
+		person.Team = &f.team
+		if err := outbox.Add(tx, events.PersonAssignedToTeam, person); err != nil {
+			return err
+		}
+		if err := RecordAssignment(tx, f.ann.ID, nil, f.team.ID); err != nil {
+			return err
+		}
+		if err := outbox.Add(tx, events.PersonRemovedFromTeam, map[string]interface{}{"person": f.bob, "team_id": f.team.ID}); err != nil {
+			return err
+		}
+		if err := outbox.Add(tx, events.FeedbackCreated, f.received); err != nil {
+			return err
+		}
+		if err := inbox.FeedbackReceived(tx, f.given); err != nil {
+			return err
+		}
+		return inbox.FeedbackReceived(tx, f.received)
+	}))
+	assert.NoError(t, db.Create(&models.ActionItem{Title: "Follow up", OwnerID: f.ann.ID}).Error)
+	return f
+}
+
+func readZip(t *testing.T, archive *Archive) map[string][]byte {
+	var b bytes.Buffer
+	assert.NoError(t, archive.WriteZip(&b))
+
+	r, err := zip.NewReader(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	assert.NoError(t, err)
+	files := map[string][]byte{}
+	for _, f := range r.File {
+		rc, err := f.Open()
+		assert.NoError(t, err)
+		files[f.Name], _ = io.ReadAll(rc)
+		rc.Close()
+	}
+	return files
+}
+
+func TestExport(t *testing.T) {
+	db := setupTestDB(t)
+	f := seed(t, db)
+
+	archive, err := Export(db, f.ann.ID, "dpo@example.com", time.Now())
+	assert.NoError(t, err)
+	files := readZip(t, archive)
+
+	var profile models.Person
+	assert.NoError(t, json.Unmarshal(files["profile.json"], &profile))
+	assert.Equal(t, "ann@example.com", profile.Email)
+	assert.Equal(t, "Platform", profile.Team.Name)
+
+	var memberships TeamMemberships
+	assert.NoError(t, json.Unmarshal(files["team_memberships.json"], &memberships))
+	assert.Equal(t, f.team.ID, memberships.CurrentTeam.ID)
+	assert.Len(t, memberships.History, 1)
+	assert.Equal(t, TeamMembership{TeamID: f.team.ID, TeamName: "Platform", Change: "added", At: memberships.History[0].At}, memberships.History[0])
+
+	var received, given []models.Feedback
+	assert.NoError(t, json.Unmarshal(files["feedback_received.json"], &received))
+	assert.NoError(t, json.Unmarshal(files["feedback_given.json"], &given))
+	assert.Equal(t, "Ann shipped it", received[0].Content)
+	assert.Equal(t, "Nice review", given[0].Content)
+
+	var manifest struct {
+		PersonID    uint           `json:"person_id"`
+		RequestedBy string         `json:"requested_by"`
+		Files       map[string]int `json:"files"`
+	}
+	assert.NoError(t, json.Unmarshal(files["manifest.json"], &manifest))
+	assert.Equal(t, f.ann.ID, manifest.PersonID)
+	assert.Equal(t, "dpo@example.com", manifest.RequestedBy)
+	assert.Equal(t, 1, manifest.Files["action_items.json"])
+	assert.Equal(t, 1, manifest.Files["notifications.json"])
+	assert.Equal(t, 1, manifest.Files["privacy_audit.json"])
+	assert.Len(t, files, len(manifest.Files)+1)
+
+	var entry models.PrivacyAuditEntry
+	assert.NoError(t, db.Where("person_id = ?", f.ann.ID).First(&entry).Error)
+	assert.Equal(t, models.PrivacyActionExport, entry.Action)
+
+	_, err = Export(db, 9999, "dpo@example.com", time.Now())
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestErase(t *testing.T) {
+	db := setupTestDB(t)
+	f := seed(t, db)
+	assert.NoError(t, outbox.Add(db, events.FeedbackUpdated, map[string]string{"target_name": "Ann O'Lee", "note": "Ann O'Leeway"}))
+	unrelated := models.Notification{PersonID: f.bob.ID, Type: models.NotificationAddedToTeam, Title: "You were added to Ann O'Lee Fans"}
+	legacy := models.Notification{PersonID: f.bob.ID, Type: models.NotificationFeedbackAcknowledged, Title: "Your feedback for Ann O'Lee was acknowledged"}
+	assert.NoError(t, db.Create(&unrelated).Error)
+	assert.NoError(t, db.Create(&legacy).Error)
+
+	entry, err := Erase(db, f.ann.ID, models.ErasePersonRequest{RequestedBy: "dpo@example.com", Reason: "Article 17 request"}, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, models.PrivacyActionErasure, entry.Action)
+	assert.Equal(t, int64(1), entry.Affected["feedback_target_names"])
+	assert.Equal(t, int64(1), entry.Affected["feedback_authors"])
+	assert.Equal(t, int64(1), entry.Affected["notifications"])
+	assert.Equal(t, int64(2), entry.Affected["notification_titles"])
+	assert.Equal(t, int64(3), entry.Affected["outbox_events"])
+
+	var ann models.Person
+	assert.NoError(t, db.First(&ann, f.ann.ID).Error)
+	assert.Equal(t, models.ErasedPersonName, ann.Name)
+	assert.Equal(t, ErasedEmail(f.ann.ID), ann.Email)
+	assert.Empty(t, ann.Picture)
+	assert.Nil(t, ann.TeamID)
+	assert.NotNil(t, ann.ErasedAt)
+
+	var received, given models.Feedback
+	db.First(&received, f.received.ID)
+	db.First(&given, f.given.ID)
+	assert.Equal(t, models.ErasedPersonName, received.TargetName)
+	assert.Equal(t, "Ann shipped it", received.Content)
+	assert.Nil(t, given.AuthorID)
+
+	var bobNotification models.Notification
+	assert.NoError(t, db.Where("person_id = ? AND type = ?", f.bob.ID, models.NotificationFeedbackReceived).First(&bobNotification).Error)
+	assert.Equal(t, "New feedback from "+models.ErasedPersonName, bobNotification.Title)
+	assert.Nil(t, bobNotification.ActorID)
+	db.First(&unrelated, unrelated.ID)
+	assert.Equal(t, "You were added to Ann O'Lee Fans", unrelated.Title)
+	db.First(&legacy, legacy.ID)
+	assert.Equal(t, "Your feedback for "+models.ErasedPersonName+" was acknowledged", legacy.Title)
+
+	var preference models.NotificationPreference
+	assert.NoError(t, db.Where("person_id = ?", f.ann.ID).First(&preference).Error)
+	assert.Equal(t, models.EmailOff, preference.EmailFrequency)
+
+	var rows []models.OutboxEvent
+	db.Where("type <> ?", events.PersonErased).Find(&rows)
+	for _, row := range rows {
+		assert.NotContains(t, row.Payload, "Ann O'Lee\"")
+		assert.NotContains(t, row.Payload, "ann@example.com")
+	}
+	var updated models.OutboxEvent
+	db.Where("type = ?", events.FeedbackUpdated).First(&updated)
+	assert.JSONEq(t, `{"target_name":"Erased person","note":"Ann O'Leeway"}`, updated.Payload)
+
+	var erased models.OutboxEvent
+	assert.NoError(t, db.Where("type = ?", events.PersonErased).First(&erased).Error)
+
+	// The history is still exported after the erasure, without the name.
+	archive, err := Export(db, f.ann.ID, "dpo@example.com", time.Now())
+	assert.NoError(t, err)
+	files := readZip(t, archive)
+	assert.NotContains(t, string(files["team_memberships.json"]), "Ann O'Lee")
+	assert.Contains(t, string(files["team_memberships.json"]), `"change": "added"`)
+
+	_, err = Erase(db, f.ann.ID, models.ErasePersonRequest{RequestedBy: "dpo@example.com"}, time.Now())
+	assert.ErrorIs(t, err, ErrAlreadyErased)
+
+	var audits int64
+	db.Model(&models.PrivacyAuditEntry{}).Where("person_id = ? AND action = ?", f.ann.ID, models.PrivacyActionErasure).Count(&audits)
+	assert.Equal(t, int64(1), audits)
+}
+
+func TestRecordAssignment(t *testing.T) {
+	db := setupTestDB(t)
+	f := seed(t, db)
+	other := models.Team{Name: "Mobile"}
+	assert.NoError(t, db.Create(&other).Error)
+
+	assert.NoError(t, RecordAssignment(db, f.ann.ID, &f.team.ID, f.team.ID))
+	assert.NoError(t, RecordAssignment(db, f.ann.ID, &f.team.ID, other.ID))
+	assert.NoError(t, RecordMembership(db, f.ann.ID, other.ID, models.MembershipRemoved))
+
+	memberships, err := teamMemberships(db, f.ann)
+	assert.NoError(t, err)
+	changes := make([]string, 0, len(memberships.History))
+	for _, m := range memberships.History {
+		changes = append(changes, m.Change+" "+m.TeamName)
+	}
+	assert.Equal(t, []string{"added Platform", "removed Platform", "added Mobile", "removed Mobile"}, changes)
+}
+
+func TestBackfillMemberships(t *testing.T) {
+	db := setupTestDB(t)
+	f := seed(t, db)
+	db.Where("1 = 1").Delete(&models.TeamMembershipChange{})
+
+	n, err := BackfillMemberships(db)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	var changes []models.TeamMembershipChange
+	db.Order("id").Find(&changes)
+	assert.Len(t, changes, 2)
+	assert.Equal(t, f.ann.ID, changes[0].PersonID)
+	assert.Equal(t, models.MembershipAdded, changes[0].Change)
+	assert.Equal(t, "Platform", changes[0].TeamName)
+	assert.Equal(t, f.bob.ID, changes[1].PersonID)
+	assert.Equal(t, models.MembershipRemoved, changes[1].Change)
+	assert.Equal(t, "Platform", changes[1].TeamName)
+
+	t.Run("should only run once", func(t *testing.T) {
+		n, err := BackfillMemberships(db)
+		assert.NoError(t, err)
+		assert.Zero(t, n)
+	})
+}
@@ -0,0 +1,236 @@
+package realtime
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"coaching-backend/events"
+	"coaching-backend/stream"
+)
+
+const (
+	MessageSubscribe   = "subscribe"
+	MessageUnsubscribe = "unsubscribe"
+	MessagePing        = "ping"
+
+	MessageWelcome      = "welcome"
+	MessageSubscribed   = "subscribed"
+	MessageUnsubscribed = "unsubscribed"
+	MessagePresence     = "presence"
+	MessageEvent        = "event"
+	MessagePong         = "pong"
+	MessageError        = "error"
+
+	ChannelTeam   = "team"
+	ChannelPerson = "person"
+)
+
+var Default = NewHub()
+
+type Viewer struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type Message struct {
+	Type    string        `json:"type"`
+	Channel string        `json:"channel,omitempty"`
+	Viewer  *Viewer       `json:"viewer,omitempty"`
+	Viewers []Viewer      `json:"viewers,omitempty"`
+	Event   *events.Event `json:"event,omitempty"`
+	Error   string        `json:"error,omitempty"`
+}
+
+type Client struct {
+	Viewer Viewer
+	send   chan Message
+}
+
+func (c *Client) Messages() <-chan Message {
+	return c.send
+}
+
+type Hub struct {
+	mu       sync.Mutex
+	clients  map[*Client]map[string]struct{}
+	channels map[string]map[*Client]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		clients:  map[*Client]map[string]struct{}{},
+		channels: map[string]map[*Client]struct{}{},
+	}
+}
+
+func ParseChannel(channel string) (string, uint, error) {
+	kind, rawID, found := strings.Cut(channel, ":")
+	if !found || (kind != ChannelTeam && kind != ChannelPerson) {
+		return "", 0, errors.New("Channel must be team:<id> or person:<id>")
+	}
+	id, err := strconv.ParseUint(rawID, 10, 64)
+	if err != nil || id == 0 {
+		return "", 0, fmt.Errorf("Invalid %s ID", kind)
+	}
+	return kind, uint(id), nil
+}
+
+func (h *Hub) Register(viewer Viewer, buffer int) *Client {
+	client := &Client{Viewer: viewer, send: make(chan Message, buffer)}
+
+	h.mu.Lock()
+	h.clients[client] = map[string]struct{}{}
+	h.mu.Unlock()
+
+	return client
+}
+
+func (h *Hub) Unregister(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.drop(client)
+}
+
+func (h *Hub) Send(client *Client, msg Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.deliver(client, msg)
+}
+
+func (h *Hub) Subscribe(client *Client, channel string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	subscriptions, ok := h.clients[client]
+	if !ok {
+		return
+	}
+	if _, already := subscriptions[channel]; !already {
+		subscriptions[channel] = struct{}{}
+		if h.channels[channel] == nil {
+			h.channels[channel] = map[*Client]struct{}{}
+		}
+		h.channels[channel][client] = struct{}{}
+	}
+
+	h.deliver(client, Message{Type: MessageSubscribed, Channel: channel, Viewers: h.viewers(channel)})
+	h.announce(channel, client)
+}
+
+func (h *Hub) Unsubscribe(client *Client, channel string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	subscriptions, ok := h.clients[client]
+	if !ok {
+		return
+	}
+	if _, subscribed := subscriptions[channel]; subscribed {
+		h.leave(client, channel)
+	}
+	h.deliver(client, Message{Type: MessageUnsubscribed, Channel: channel})
+}
+
+func (h *Hub) Viewers(channel string) []Viewer {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.viewers(channel)
+}
+
+func (h *Hub) Handle(event events.Event) {
+	h.mu.Lock()
+	channels := make([]string, 0, len(h.channels))
+	for channel := range h.channels {
+		channels = append(channels, channel)
+	}
+	h.mu.Unlock()
+
+	// Matching may hit the database, so it runs without holding the lock.
+	var matched []string
+	for _, channel := range channels {
+		if channelFilter(channel).Matches(event) {
+			matched = append(matched, channel)
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, channel := range matched {
+		e := event
+		for client := range h.channels[channel] {
+			h.deliver(client, Message{Type: MessageEvent, Channel: channel, Event: &e})
+		}
+	}
+}
+
+func channelFilter(channel string) stream.Filter {
+	kind, id, _ := ParseChannel(channel)
+	if kind == ChannelTeam {
+		return stream.Filter{TeamID: id}
+	}
+	return stream.Filter{TargetType: ChannelPerson, TargetID: id}
+}
+
+// deliver never blocks: a client whose buffer is full is dropped so one slow
+// dashboard cannot stall the others. Callers must hold h.mu.
+func (h *Hub) deliver(client *Client, msg Message) {
+	if _, ok := h.clients[client]; !ok {
+		return
+	}
+	select {
+	case client.send <- msg:
+	default:
+		h.drop(client)
+	}
+}
+
+func (h *Hub) drop(client *Client) {
+	subscriptions, ok := h.clients[client]
+	if !ok {
+		return
+	}
+	delete(h.clients, client)
+	close(client.send)
+
+	for channel := range subscriptions {
+		h.leave(client, channel)
+	}
+}
+
+func (h *Hub) leave(client *Client, channel string) {
+	if subscriptions, ok := h.clients[client]; ok {
+		delete(subscriptions, channel)
+	}
+	delete(h.channels[channel], client)
+	if len(h.channels[channel]) == 0 {
+		delete(h.channels, channel)
+		return
+	}
+	h.announce(channel, nil)
+}
+
+func (h *Hub) announce(channel string, except *Client) {
+	viewers := h.viewers(channel)
+	for other := range h.channels[channel] {
+		if other != except {
+			h.deliver(other, Message{Type: MessagePresence, Channel: channel, Viewers: viewers})
+		}
+	}
+}
+
+func (h *Hub) viewers(channel string) []Viewer {
+	seen := map[uint]bool{}
+	viewers := []Viewer{}
+	for client := range h.channels[channel] {
+		if client.Viewer.ID == 0 || seen[client.Viewer.ID] {
+			continue
+		}
+		seen[client.Viewer.ID] = true
+		viewers = append(viewers, client.Viewer)
+	}
+	sort.Slice(viewers, func(i, j int) bool { return viewers[i].ID < viewers[j].ID })
+	return viewers
+}
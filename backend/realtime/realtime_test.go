@@ -0,0 +1,101 @@
+package realtime
+
+import (
+	"encoding/json"
+	"testing"
+
+	"coaching-backend/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func drain(client *Client) []Message {
+	var messages []Message
+	for {
+		select {
+		case msg, ok := <-client.Messages():
+			if !ok {
+				return messages
+			}
+			messages = append(messages, msg)
+		default:
+			return messages
+		}
+	}
+}
+
+func TestParseChannel(t *testing.T) {
+	kind, id, err := ParseChannel("team:12")
+	assert.NoError(t, err)
+	assert.Equal(t, ChannelTeam, kind)
+	assert.Equal(t, uint(12), id)
+
+	for _, channel := range []string{"", "team", "team:", "team:abc", "team:0", "goal:1"} {
+		_, _, err := ParseChannel(channel)
+		assert.Error(t, err, channel)
+	}
+}
+
+func TestHubPresence(t *testing.T) {
+	hub := NewHub()
+	alice := hub.Register(Viewer{ID: 1, Name: "Alice"}, 8)
+	bob := hub.Register(Viewer{ID: 2, Name: "Bob"}, 8)
+
+	hub.Subscribe(alice, "team:1")
+	assert.Equal(t, []Message{{Type: MessageSubscribed, Channel: "team:1", Viewers: []Viewer{{ID: 1, Name: "Alice"}}}}, drain(alice))
+
+	hub.Subscribe(bob, "team:1")
+	assert.Equal(t, []Viewer{{ID: 1, Name: "Alice"}, {ID: 2, Name: "Bob"}}, drain(bob)[0].Viewers)
+	presence := drain(alice)
+	assert.Len(t, presence, 1)
+	assert.Equal(t, MessagePresence, presence[0].Type)
+	assert.Len(t, presence[0].Viewers, 2)
+
+	hub.Unregister(bob)
+	presence = drain(alice)
+	assert.Equal(t, []Viewer{{ID: 1, Name: "Alice"}}, presence[0].Viewers)
+
+	hub.Unsubscribe(alice, "team:1")
+	assert.Equal(t, MessageUnsubscribed, drain(alice)[0].Type)
+	assert.Empty(t, hub.Viewers("team:1"))
+}
+
+func TestHubRoutesEvents(t *testing.T) {
+	hub := NewHub()
+	watcher := hub.Register(Viewer{ID: 1, Name: "Watcher"}, 8)
+	hub.Subscribe(watcher, "team:7")
+	drain(watcher)
+
+	hub.Handle(events.Event{Type: events.TeamUpdated, Sequence: 1, Data: json.RawMessage(`{"id":8}`)})
+	hub.Handle(events.Event{Type: events.TeamUpdated, Sequence: 2, Data: json.RawMessage(`{"id":7}`)})
+
+	messages := drain(watcher)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, MessageEvent, messages[0].Type)
+	assert.Equal(t, "team:7", messages[0].Channel)
+	assert.Equal(t, uint(2), messages[0].Event.Sequence)
+}
+
+func TestHubDropsSlowClients(t *testing.T) {
+	hub := NewHub()
+	slow := hub.Register(Viewer{ID: 1, Name: "Slow"}, 2)
+	fast := hub.Register(Viewer{ID: 2, Name: "Fast"}, 8)
+	hub.Subscribe(slow, "team:7")
+	hub.Subscribe(fast, "team:7")
+	drain(slow)
+	drain(fast)
+
+	for i := 0; i < 3; i++ {
+		hub.Handle(events.Event{Type: events.TeamUpdated, Data: json.RawMessage(`{"id":7}`)})
+	}
+
+	assert.Len(t, drain(slow), 2, "buffered messages are kept and the channel is closed")
+	_, open := <-slow.Messages()
+	assert.False(t, open)
+
+	var types []string
+	for _, msg := range drain(fast) {
+		types = append(types, msg.Type)
+	}
+	assert.ElementsMatch(t, []string{MessageEvent, MessageEvent, MessageEvent, MessagePresence}, types)
+	assert.Equal(t, []Viewer{{ID: 2, Name: "Fast"}}, hub.Viewers("team:7"))
+}
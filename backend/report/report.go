@@ -0,0 +1,318 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"coaching-backend/models"
+	"coaching-backend/pdf"
+)
+
+const (
+	PeriodMonth   = "month"
+	PeriodQuarter = "quarter"
+)
+
+const uncategorized = "Uncategorized"
+
+var (
+	titleStyle   = pdf.Style{Font: pdf.FontBold, Size: 20}
+	nameStyle    = pdf.Style{Font: pdf.FontBold, Size: 14}
+	sectionStyle = pdf.Style{Font: pdf.FontBold, Size: 13}
+	groupStyle   = pdf.Style{Font: pdf.FontBold, Size: 11}
+	labelStyle   = pdf.Style{Font: pdf.FontBold, Size: 10}
+	bodyStyle    = pdf.Style{Font: pdf.FontRegular, Size: 10}
+	mutedStyle   = pdf.Style{Font: pdf.FontRegular, Size: 9, Gray: 0.4}
+	metaStyle    = pdf.Style{Font: pdf.FontRegular, Size: 8.5, Gray: 0.4, Indent: 12}
+	contentStyle = pdf.Style{Font: pdf.FontRegular, Size: 10, Indent: 12}
+)
+
+type PersonReport struct {
+	Person  models.Person
+	Manager *models.Person
+	// Feedback received by the person, oldest first.
+	Feedback    []models.Feedback
+	Authors     map[uint]string
+	Period      string
+	GeneratedAt time.Time
+}
+
+type Summary struct {
+	Count        int
+	Rated        int
+	Average      float64
+	Distribution [5]int
+	Sentiments   map[string]int
+}
+
+type Group struct {
+	Label    string
+	Feedback []models.Feedback
+}
+
+type PeriodGroup struct {
+	Group
+	Categories []Group
+}
+
+func IsPeriod(period string) bool {
+	return period == PeriodMonth || period == PeriodQuarter
+}
+
+func PeriodLabel(t time.Time, period string) string {
+	if period == PeriodMonth {
+		return t.Format("Jan 2006")
+	}
+	return fmt.Sprintf("%d Q%d", t.Year(), (int(t.Month())+2)/3)
+}
+
+func Summarize(feedback []models.Feedback) Summary {
+	s := Summary{Count: len(feedback), Sentiments: map[string]int{}}
+	total := 0
+	for _, f := range feedback {
+		if f.Sentiment != "" {
+			s.Sentiments[f.Sentiment]++
+		}
+		if f.Rating == nil || *f.Rating < 1 || *f.Rating > 5 {
+			continue
+		}
+		s.Rated++
+		s.Distribution[*f.Rating-1]++
+		total += *f.Rating
+	}
+	if s.Rated > 0 {
+		s.Average = float64(total) / float64(s.Rated)
+	}
+	return s
+}
+
+// GroupByPeriod groups feedback, which must be sorted oldest first, into periods in
+// the same order and each period into categories by name with uncategorized last.
+func GroupByPeriod(feedback []models.Feedback, period string) []PeriodGroup {
+	var groups []PeriodGroup
+	for _, f := range feedback {
+		label := PeriodLabel(f.CreatedAt, period)
+		if len(groups) == 0 || groups[len(groups)-1].Label != label {
+			groups = append(groups, PeriodGroup{Group: Group{Label: label}})
+		}
+		g := &groups[len(groups)-1]
+		g.Feedback = append(g.Feedback, f)
+	}
+	for i := range groups {
+		groups[i].Categories = GroupByCategory(groups[i].Feedback)
+	}
+	return groups
+}
+
+func GroupByCategory(feedback []models.Feedback) []Group {
+	index := map[string]int{}
+	var groups []Group
+	for _, f := range feedback {
+		label := f.Category
+		if label == "" {
+			label = uncategorized
+		}
+		i, ok := index[label]
+		if !ok {
+			i = len(groups)
+			index[label] = i
+			groups = append(groups, Group{Label: label})
+		}
+		groups[i].Feedback = append(groups[i].Feedback, f)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if (groups[i].Label == uncategorized) != (groups[j].Label == uncategorized) {
+			return groups[j].Label == uncategorized
+		}
+		return groups[i].Label < groups[j].Label
+	})
+	return groups
+}
+
+// Trend compares the average rating of the two most recent periods with ratings.
+func Trend(groups []PeriodGroup) string {
+	var rated []PeriodGroup
+	var averages []float64
+	for _, g := range groups {
+		if s := Summarize(g.Feedback); s.Rated > 0 {
+			rated = append(rated, g)
+			averages = append(averages, s.Average)
+		}
+	}
+	if len(rated) < 2 {
+		return "Not enough rated periods to show a trend."
+	}
+
+	n := len(rated)
+	previous, latest := averages[n-2], averages[n-1]
+	change := latest - previous
+	direction := "Stable"
+	switch {
+	case change >= 0.05:
+		direction = "Improving"
+	case change <= -0.05:
+		direction = "Declining"
+	}
+	return fmt.Sprintf("%s: average rating %.1f in %s compared with %.1f in %s (%+.1f).",
+		direction, latest, rated[n-1].Label, previous, rated[n-2].Label, change)
+}
+
+func (r PersonReport) Render() *pdf.Document {
+	doc := pdf.New("Feedback report: "+r.Person.Name, r.GeneratedAt)
+	groups := GroupByPeriod(r.Feedback, r.Period)
+	summary := Summarize(r.Feedback)
+
+	doc.Text(titleStyle, "Feedback report")
+	doc.Text(nameStyle, r.Person.Name)
+	doc.Text(mutedStyle, "Generated "+r.GeneratedAt.Format("January 2, 2006 15:04 MST"))
+	doc.Rule()
+
+	r.renderProfile(doc)
+	r.renderSummary(doc, summary)
+	r.renderTrend(doc, groups)
+	r.renderCategories(doc)
+	r.renderFeedback(doc, groups)
+	return doc
+}
+
+func (r PersonReport) renderProfile(doc *pdf.Document) {
+	section(doc, "Profile")
+	team, manager := "No team", "None"
+	if r.Person.Team != nil {
+		team = r.Person.Team.Name
+	}
+	if r.Manager != nil {
+		manager = r.Manager.Name
+	}
+	field(doc, "Email", r.Person.Email)
+	field(doc, "Current team", team)
+	field(doc, "Manager", manager)
+	field(doc, "Member since", r.Person.CreatedAt.Format("January 2, 2006"))
+}
+
+func (r PersonReport) renderSummary(doc *pdf.Document, summary Summary) {
+	section(doc, "Summary")
+	field(doc, "Feedback received", fmt.Sprintf("%d (%d rated)", summary.Count, summary.Rated))
+	field(doc, "Average rating", formatAverage(summary))
+	field(doc, "Sentiment", fmt.Sprintf("%d positive, %d neutral, %d negative",
+		summary.Sentiments["positive"], summary.Sentiments["neutral"], summary.Sentiments["negative"]))
+	if summary.Rated == 0 {
+		return
+	}
+
+	doc.Space(6)
+	max := 0
+	for _, n := range summary.Distribution {
+		if n > max {
+			max = n
+		}
+	}
+	for rating := 5; rating >= 1; rating-- {
+		n := summary.Distribution[rating-1]
+		doc.Bar(fmt.Sprintf("%d stars", rating), float64(n), float64(max), fmt.Sprintf("%d", n))
+	}
+}
+
+func (r PersonReport) renderTrend(doc *pdf.Document, groups []PeriodGroup) {
+	section(doc, "Rating trend by "+r.Period)
+	for _, g := range groups {
+		s := Summarize(g.Feedback)
+		if s.Rated == 0 {
+			doc.Bar(g.Label, 0, 5, "no ratings")
+			continue
+		}
+		doc.Bar(g.Label, s.Average, 5, fmt.Sprintf("%.1f (%d)", s.Average, s.Rated))
+	}
+	doc.Space(4)
+	doc.Text(bodyStyle, Trend(groups))
+}
+
+func (r PersonReport) renderCategories(doc *pdf.Document) {
+	section(doc, "Ratings by category")
+	categories := GroupByCategory(r.Feedback)
+	if len(categories) == 0 {
+		doc.Text(mutedStyle, "No feedback received yet.")
+		return
+	}
+	for _, g := range categories {
+		s := Summarize(g.Feedback)
+		caption := fmt.Sprintf("%d feedback", s.Count)
+		if s.Rated > 0 {
+			caption = fmt.Sprintf("%.1f (%d of %d)", s.Average, s.Rated, s.Count)
+		}
+		doc.Bar(g.Label, s.Average, 5, caption)
+	}
+}
+
+func (r PersonReport) renderFeedback(doc *pdf.Document, groups []PeriodGroup) {
+	section(doc, "Feedback by period")
+	if len(groups) == 0 {
+		doc.Text(mutedStyle, "No feedback received yet.")
+		return
+	}
+
+	// Most recent period first, matching the order of the feedback lists in the API.
+	for i := len(groups) - 1; i >= 0; i-- {
+		g := groups[i]
+		s := Summarize(g.Feedback)
+		doc.Space(6)
+		doc.Ensure(60)
+		doc.Text(groupStyle, fmt.Sprintf("%s — %d feedback, average rating %s", g.Label, s.Count, formatAverage(s)))
+
+		for _, category := range g.Categories {
+			doc.Space(2)
+			doc.Ensure(40)
+			doc.Text(labelStyle, category.Label)
+			for j := len(category.Feedback) - 1; j >= 0; j-- {
+				r.renderEntry(doc, category.Feedback[j])
+			}
+		}
+	}
+}
+
+func (r PersonReport) renderEntry(doc *pdf.Document, f models.Feedback) {
+	meta := []string{f.CreatedAt.Format("Jan 2, 2006"), r.author(f)}
+	if f.Rating != nil {
+		meta = append(meta, fmt.Sprintf("rating %d/5", *f.Rating))
+	}
+	if f.Sentiment != "" {
+		meta = append(meta, f.Sentiment)
+	}
+	if f.Private {
+		meta = append(meta, "private")
+	}
+
+	doc.Ensure(30)
+	doc.Text(metaStyle, strings.Join(meta, " · "))
+	doc.Text(contentStyle, f.Content)
+	doc.Space(4)
+}
+
+func (r PersonReport) author(f models.Feedback) string {
+	if f.AuthorID == nil {
+		return "Anonymous"
+	}
+	if name, ok := r.Authors[*f.AuthorID]; ok {
+		return name
+	}
+	return "Former member"
+}
+
+func section(doc *pdf.Document, title string) {
+	doc.Space(10)
+	doc.Ensure(50)
+	doc.Text(sectionStyle, title)
+	doc.Space(2)
+}
+
+func field(doc *pdf.Document, label, value string) {
+	doc.Text(bodyStyle, label+": "+value)
+}
+
+func formatAverage(s Summary) string {
+	if s.Rated == 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.1f / 5", s.Average)
+}
@@ -0,0 +1,116 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"coaching-backend/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func rating(n int) *int {
+	return &n
+}
+
+func at(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 12, 0, 0, 0, time.UTC)
+}
+
+func sampleFeedback() []models.Feedback {
+	author := uint(2)
+	return []models.Feedback{
+		{ID: 1, Content: "Solid start", Category: "delivery", Rating: rating(3), Sentiment: "neutral", CreatedAt: at(2026, 2, 3)},
+		{ID: 2, Content: "Clear writeups", Category: "communication", Rating: rating(4), Sentiment: "positive", AuthorID: &author, CreatedAt: at(2026, 3, 20)},
+		{ID: 3, Content: "Thanks for the help", Sentiment: "positive", CreatedAt: at(2026, 4, 1)},
+		{ID: 4, Content: "Great demo", Category: "communication", Rating: rating(5), Sentiment: "positive", CreatedAt: at(2026, 5, 9)},
+		{ID: 5, Content: "Shipped on time", Category: "delivery", Rating: rating(4), Private: true, CreatedAt: at(2026, 6, 30)},
+	}
+}
+
+func TestPeriodLabel(t *testing.T) {
+	assert.Equal(t, "2026 Q1", PeriodLabel(at(2026, 3, 31), PeriodQuarter))
+	assert.Equal(t, "2026 Q4", PeriodLabel(at(2026, 10, 1), PeriodQuarter))
+	assert.Equal(t, "Oct 2026", PeriodLabel(at(2026, 10, 1), PeriodMonth))
+	assert.True(t, IsPeriod(PeriodMonth))
+	assert.False(t, IsPeriod("week"))
+}
+
+func TestSummarize(t *testing.T) {
+	s := Summarize(sampleFeedback())
+	assert.Equal(t, 5, s.Count)
+	assert.Equal(t, 4, s.Rated)
+	assert.Equal(t, 4.0, s.Average)
+	assert.Equal(t, [5]int{0, 0, 1, 2, 1}, s.Distribution)
+	assert.Equal(t, 3, s.Sentiments["positive"])
+
+	assert.Zero(t, Summarize(nil).Average)
+}
+
+func TestGroupByPeriod(t *testing.T) {
+	groups := GroupByPeriod(sampleFeedback(), PeriodQuarter)
+	assert.Len(t, groups, 2)
+	assert.Equal(t, "2026 Q1", groups[0].Label)
+	assert.Equal(t, []string{"communication", "delivery"}, labels(groups[0].Categories))
+	assert.Equal(t, []string{"communication", "delivery", "Uncategorized"}, labels(groups[1].Categories))
+
+	assert.Len(t, GroupByPeriod(sampleFeedback(), PeriodMonth), 5)
+}
+
+func labels(groups []Group) []string {
+	var out []string
+	for _, g := range groups {
+		out = append(out, g.Label)
+	}
+	return out
+}
+
+func TestTrend(t *testing.T) {
+	groups := GroupByPeriod(sampleFeedback(), PeriodQuarter)
+	assert.Equal(t, "Improving: average rating 4.5 in 2026 Q2 compared with 3.5 in 2026 Q1 (+1.0).", Trend(groups))
+	assert.Equal(t, "Not enough rated periods to show a trend.", Trend(groups[:1]))
+
+	declining := GroupByPeriod([]models.Feedback{
+		{Rating: rating(5), CreatedAt: at(2026, 1, 1)},
+		{CreatedAt: at(2026, 4, 1)},
+		{Rating: rating(2), CreatedAt: at(2026, 7, 1)},
+	}, PeriodQuarter)
+	assert.Contains(t, Trend(declining), "Declining: average rating 2.0 in 2026 Q3 compared with 5.0 in 2026 Q1")
+}
+
+func TestRender(t *testing.T) {
+	team := models.Team{Name: "Platform"}
+	r := PersonReport{
+		Person:      models.Person{Name: "Ann Lee", Email: "ann@example.com", Team: &team, CreatedAt: at(2025, 1, 1)},
+		Manager:     &models.Person{Name: "Max Boss"},
+		Feedback:    sampleFeedback(),
+		Authors:     map[uint]string{2: "Bob Author"},
+		Period:      PeriodQuarter,
+		GeneratedAt: at(2026, 10, 18),
+	}
+
+	var b bytes.Buffer
+	_, err := r.Render().WriteTo(&b)
+	assert.NoError(t, err)
+	out := b.String()
+
+	for _, text := range []string{
+		"(Ann Lee)", "(Current team: Platform)", "(Manager: Max Boss)", "(Average rating: 4.0 / 5)",
+		"(2026 Q2 \x97 3 feedback, average rating 4.5 / 5)", "(Uncategorized)", "(Great demo)",
+		"Bob Author \xb7 rating 4/5", "Anonymous \xb7 rating 4/5 \xb7 private", "(Improving: average rating 4.5",
+	} {
+		assert.Contains(t, out, text)
+	}
+	// The most recent period is listed first.
+	assert.Less(t, bytes.Index(b.Bytes(), []byte("(Great demo)")), bytes.Index(b.Bytes(), []byte("(Solid start)")))
+}
+
+func TestRenderWithoutFeedback(t *testing.T) {
+	r := PersonReport{Person: models.Person{Name: "New Hire"}, Period: PeriodMonth, GeneratedAt: time.Now()}
+
+	var b bytes.Buffer
+	_, err := r.Render().WriteTo(&b)
+	assert.NoError(t, err)
+	assert.Contains(t, b.String(), "(Current team: No team)")
+	assert.Contains(t, b.String(), "(No feedback received yet.)")
+}
@@ -0,0 +1,306 @@
+package retention
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+	"coaching-backend/database"
+	"coaching-backend/encryption"
+	"coaching-backend/events"
+	"coaching-backend/models"
+	"coaching-backend/outbox"
+	"gorm.io/gorm"
+)
+
+const AnonymizedContent = "[Removed by retention policy]"
+
+const (
+	batchSize  = 500
+	sampleSize = 100
+)
+
+var applyMu sync.Mutex
+
+type candidate struct {
+	ID           uint
+	TargetType   string
+	Category     string
+	CreatedAt    time.Time
+	LegalHoldAt  *time.Time
+	AnonymizedAt *time.Time
+}
+
+// Select returns the rule that governs a feedback: the most specific matching
+// rule, and among equally specific rules the one that keeps feedback longest.
+func Select(rules []models.RetentionRule, targetType, category string) *models.RetentionRule {
+	var best *models.RetentionRule
+	bestSpecificity := -1
+	for i := range rules {
+		r := &rules[i]
+		if (r.TargetType != "" && r.TargetType != targetType) || (r.Category != "" && r.Category != category) {
+			continue
+		}
+		specificity := 0
+		if r.TargetType != "" {
+			specificity++
+		}
+		if r.Category != "" {
+			specificity++
+		}
+		switch {
+		case specificity > bestSpecificity,
+			specificity == bestSpecificity && r.Months > best.Months,
+			specificity == bestSpecificity && r.Months == best.Months && r.Action == models.RetentionAnonymize && best.Action == models.RetentionDelete:
+			best, bestSpecificity = r, specificity
+		}
+	}
+	return best
+}
+
+func Cutoff(rule models.RetentionRule, now time.Time) time.Time {
+	return now.AddDate(0, -rule.Months, 0)
+}
+
+// Apply runs every enabled rule once and records the run. A dry run counts what
+// would happen without changing anything.
+func Apply(db *gorm.DB, now time.Time, dryRun bool, trigger string) (models.RetentionRun, error) {
+	applyMu.Lock()
+	defer applyMu.Unlock()
+
+	run := models.RetentionRun{Trigger: trigger, DryRun: dryRun, Rules: []models.RetentionRuleResult{}, StartedAt: now}
+	err := apply(db, now, &run)
+	if err != nil {
+		run.Error = err.Error()
+	}
+	run.FinishedAt = time.Now()
+	if saveErr := db.Create(&run).Error; saveErr != nil && err == nil {
+		err = saveErr
+	}
+	if !dryRun {
+		outbox.Notify()
+	}
+	return run, err
+}
+
+func apply(db *gorm.DB, now time.Time, run *models.RetentionRun) error {
+	var rules []models.RetentionRule
+	if err := db.Where("enabled = ?", true).Order("id").Find(&rules).Error; err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	results := map[uint]*models.RetentionRuleResult{}
+	latestCutoff := Cutoff(rules[0], now)
+	for _, r := range rules {
+		run.Rules = append(run.Rules, models.RetentionRuleResult{RuleID: r.ID, Name: r.Name, Action: r.Action})
+		if cutoff := Cutoff(r, now); cutoff.After(latestCutoff) {
+			latestCutoff = cutoff
+		}
+	}
+	for i := range run.Rules {
+		results[run.Rules[i].RuleID] = &run.Rules[i]
+	}
+
+	var lastID uint
+	for {
+		var batch []candidate
+		err := db.Model(&models.Feedback{}).
+			Select("id, target_type, category, created_at, legal_hold_at, anonymized_at").
+			Where("id > ? AND created_at < ?", lastID, latestCutoff).
+			Order("id").Limit(batchSize).Find(&batch).Error
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		lastID = batch[len(batch)-1].ID
+		run.Scanned += int64(len(batch))
+
+		var toDelete, toAnonymize []uint
+		for _, f := range batch {
+			rule := Select(rules, f.TargetType, f.Category)
+			if rule == nil || !f.CreatedAt.Before(Cutoff(*rule, now)) {
+				continue
+			}
+			if rule.Action == models.RetentionAnonymize && f.AnonymizedAt != nil {
+				continue
+			}
+
+			result := results[rule.ID]
+			if f.LegalHoldAt != nil {
+				result.Held++
+				run.Held++
+				continue
+			}
+			result.Affected++
+			if run.DryRun && len(result.SampleIDs) < sampleSize {
+				result.SampleIDs = append(result.SampleIDs, f.ID)
+			}
+			if rule.Action == models.RetentionDelete {
+				toDelete = append(toDelete, f.ID)
+			} else {
+				toAnonymize = append(toAnonymize, f.ID)
+			}
+		}
+
+		run.Deleted += int64(len(toDelete))
+		run.Anonymized += int64(len(toAnonymize))
+		if run.DryRun || len(toDelete)+len(toAnonymize) == 0 {
+			continue
+		}
+		err = db.Transaction(func(tx *gorm.DB) error {
+			// Before the new events are added, which carry no content to purge.
+			if err := purgeFeedbackEvents(tx, append(toDelete, toAnonymize...)); err != nil {
+				return err
+			}
+			if err := deleteFeedbacks(tx, toDelete); err != nil {
+				return err
+			}
+			return anonymizeFeedbacks(tx, toAnonymize, now)
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func deleteFeedbacks(tx *gorm.DB, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var feedbacks []models.Feedback
+	if err := tx.Select("id, target_type, target_id").Where("id IN ? AND legal_hold_at IS NULL", ids).Find(&feedbacks).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("feedback_id IN ?", ids).Delete(&models.FeedbackAnswer{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Exec("DELETE FROM session_feedbacks WHERE feedback_id IN ?", ids).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("feedback_id IN ?", ids).Delete(&models.Notification{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("feedback_id IN ?", ids).Delete(&models.EmailNotification{}).Error; err != nil {
+		return err
+	}
+	// A hold placed since the batch was read still wins.
+	if err := tx.Where("id IN ? AND legal_hold_at IS NULL", ids).Delete(&models.Feedback{}).Error; err != nil {
+		return err
+	}
+	for _, f := range feedbacks {
+		if err := outbox.Add(tx, events.FeedbackDeleted, map[string]interface{}{"id": f.ID, "target_type": f.TargetType, "target_id": f.TargetID}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// purgeFeedbackEvents deletes the stored events that carry a copy of the
+// feedback, so its content does not outlive the feedback itself.
+func purgeFeedbackEvents(tx *gorm.DB, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	var unheld []uint
+	if err := tx.Model(&models.Feedback{}).Where("id IN ? AND legal_hold_at IS NULL", ids).Pluck("id", &unheld).Error; err != nil {
+		return err
+	}
+	purged := make(map[uint]bool, len(unheld))
+	for _, id := range unheld {
+		purged[id] = true
+	}
+
+	var eventIDs []uint
+	err := outbox.Scan(tx, []string{events.FeedbackCreated, events.FeedbackUpdated}, func(row models.OutboxEvent) error {
+		var payload struct {
+			ID uint `json:"id"`
+		}
+		if err := json.Unmarshal([]byte(row.Payload), &payload); err == nil && purged[payload.ID] {
+			eventIDs = append(eventIDs, row.ID)
+		}
+		return nil
+	})
+	if err != nil || len(eventIDs) == 0 {
+		return err
+	}
+	return tx.Where("id IN ?", eventIDs).Delete(&models.OutboxEvent{}).Error
+}
+
+func anonymizeFeedbacks(tx *gorm.DB, ids []uint, now time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	content, err := encryption.Encrypt("content", AnonymizedContent)
+	if err != nil {
+		return err
+	}
+	err = tx.Model(&models.Feedback{}).Where("id IN ? AND legal_hold_at IS NULL", ids).Updates(map[string]interface{}{
+		"content":       content,
+		"author_id":     nil,
+		"anonymized_at": now,
+	}).Error
+	if err != nil {
+		return err
+	}
+	err = tx.Model(&models.FeedbackAnswer{}).Where("feedback_id IN ? AND answer_type = ?", ids, models.AnswerTypeText).
+		Update("value", "").Error
+	if err != nil {
+		return err
+	}
+
+	var feedbacks []models.Feedback
+	if err := tx.Preload("Answers").Where("id IN ? AND anonymized_at IS NOT NULL", ids).Find(&feedbacks).Error; err != nil {
+		return err
+	}
+	for _, f := range feedbacks {
+		if err := outbox.Add(tx, events.FeedbackUpdated, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func Run(interval time.Duration, dryRun bool) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		run, err := Apply(database.GetDB(), time.Now(), dryRun, models.RetentionTriggerSchedule)
+		if err != nil {
+			log.Printf("retention: run failed: %v", err)
+		} else if run.Deleted+run.Anonymized+run.Held > 0 {
+			log.Printf("retention: %d deleted, %d anonymized, %d held (dry run: %t)", run.Deleted, run.Anonymized, run.Held, dryRun)
+		}
+		<-ticker.C
+	}
+}
+
+func Metrics(db *gorm.DB) (models.RetentionMetrics, error) {
+	var metrics models.RetentionMetrics
+	err := db.Model(&models.RetentionRun{}).Where("dry_run = ?", false).
+		Select("COUNT(*), COALESCE(SUM(deleted), 0), COALESCE(SUM(anonymized), 0)").
+		Row().Scan(&metrics.Runs, &metrics.Deleted, &metrics.Anonymized)
+	if err != nil {
+		return metrics, err
+	}
+	if err := db.Model(&models.Feedback{}).Where("legal_hold_at IS NOT NULL").Count(&metrics.LegalHolds).Error; err != nil {
+		return metrics, err
+	}
+
+	var last models.RetentionRun
+	result := db.Order("started_at desc, id desc").Limit(1).Find(&last)
+	if result.Error != nil {
+		return metrics, result.Error
+	}
+	if result.RowsAffected > 0 {
+		metrics.LastRun = &last
+	}
+	return metrics, nil
+}
@@ -0,0 +1,167 @@
+package retention
+
+import (
+	"testing"
+	"time"
+
+	"coaching-backend/database"
+	"coaching-backend/events"
+	"coaching-backend/models"
+	"coaching-backend/outbox"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	database.DB = db
+	return db
+}
+
+func monthsAgo(now time.Time, months int) time.Time {
+	return now.AddDate(0, -months, -1)
+}
+
+func TestSelect(t *testing.T) {
+	rules := []models.RetentionRule{
+		{ID: 1, Months: 24, Action: models.RetentionDelete},
+		{ID: 2, TargetType: "person", Months: 12, Action: models.RetentionDelete},
+		{ID: 3, TargetType: "person", Category: "performance", Months: 60, Action: models.RetentionAnonymize},
+		{ID: 4, Category: "kudos", Months: 6, Action: models.RetentionDelete},
+		{ID: 5, Category: "kudos", Months: 6, Action: models.RetentionAnonymize},
+		{ID: 6, Category: "kudos", Months: 3, Action: models.RetentionDelete},
+	}
+
+	assert.Equal(t, uint(1), Select(rules, "team", "").ID)
+	assert.Equal(t, uint(2), Select(rules, "person", "growth").ID)
+	assert.Equal(t, uint(3), Select(rules, "person", "performance").ID)
+	assert.Equal(t, uint(2), Select(rules, "person", "kudos").ID)
+	assert.Equal(t, uint(5), Select(rules, "team", "kudos").ID)
+	assert.Nil(t, Select(rules[1:3], "team", ""))
+}
+
+func TestApply(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	author := uint(7)
+	held := now.AddDate(0, -1, 0)
+
+	rules := []models.RetentionRule{
+		{Name: "Team feedback", TargetType: "team", Months: 12, Action: models.RetentionDelete, Enabled: true},
+		{Name: "Person feedback", TargetType: "person", Months: 24, Action: models.RetentionAnonymize, Enabled: true},
+		{Name: "Disabled", Months: 1, Action: models.RetentionDelete, Enabled: false},
+	}
+	assert.NoError(t, db.Create(&rules).Error)
+
+	feedbacks := []models.Feedback{
+		{Content: "Old team note", TargetType: "team", TargetID: 1, TargetName: "Core", CreatedAt: monthsAgo(now, 13)},
+		{Content: "Recent team note", TargetType: "team", TargetID: 1, TargetName: "Core", CreatedAt: monthsAgo(now, 11)},
+		{Content: "Old person note", TargetType: "person", TargetID: 2, TargetName: "Ann", AuthorID: &author, CreatedAt: monthsAgo(now, 25)},
+		{Content: "Held team note", TargetType: "team", TargetID: 1, TargetName: "Core", CreatedAt: monthsAgo(now, 30), LegalHoldAt: &held, LegalHoldReason: "Litigation"},
+		{Content: "Recent person note", TargetType: "person", TargetID: 2, TargetName: "Ann", CreatedAt: monthsAgo(now, 13)},
+	}
+	assert.NoError(t, db.Create(&feedbacks).Error)
+	assert.NoError(t, db.Create(&models.FeedbackAnswer{FeedbackID: feedbacks[2].ID, QuestionID: 1, Prompt: "Why?", AnswerType: models.AnswerTypeText, Value: "Because Ann"}).Error)
+	assert.NoError(t, db.Create(&models.Notification{PersonID: 1, Type: models.NotificationFeedbackReceived, Title: "New", FeedbackID: &feedbacks[0].ID}).Error)
+
+	t.Run("should report without changes in dry-run mode", func(t *testing.T) {
+		run, err := Apply(db, now, true, models.RetentionTriggerManual)
+		assert.NoError(t, err)
+
+		assert.True(t, run.DryRun)
+		assert.Equal(t, int64(4), run.Scanned)
+		assert.Equal(t, int64(1), run.Deleted)
+		assert.Equal(t, int64(1), run.Anonymized)
+		assert.Equal(t, int64(1), run.Held)
+		assert.Len(t, run.Rules, 2)
+		assert.Equal(t, []uint{feedbacks[0].ID}, run.Rules[0].SampleIDs)
+		assert.Equal(t, int64(1), run.Rules[0].Held)
+		assert.Equal(t, []uint{feedbacks[2].ID}, run.Rules[1].SampleIDs)
+
+		var count int64
+		db.Model(&models.Feedback{}).Count(&count)
+		assert.Equal(t, int64(5), count)
+		db.Model(&models.OutboxEvent{}).Count(&count)
+		assert.Zero(t, count)
+		db.Model(&models.RetentionRun{}).Count(&count)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("should delete and anonymize expired feedback", func(t *testing.T) {
+		for _, f := range feedbacks[:4] {
+			assert.NoError(t, outbox.Add(db, events.FeedbackCreated, f))
+		}
+		assert.NoError(t, outbox.Add(db, events.FeedbackUpdated, feedbacks[2]))
+
+		run, err := Apply(db, now, false, models.RetentionTriggerSchedule)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), run.Deleted)
+		assert.Equal(t, int64(1), run.Anonymized)
+		assert.Nil(t, run.Rules[0].SampleIDs)
+
+		assert.ErrorIs(t, db.First(&models.Feedback{}, feedbacks[0].ID).Error, gorm.ErrRecordNotFound)
+		assert.NoError(t, db.First(&models.Feedback{}, feedbacks[1].ID).Error)
+		assert.NoError(t, db.First(&models.Feedback{}, feedbacks[3].ID).Error)
+
+		var anonymized models.Feedback
+		assert.NoError(t, db.Preload("Answers").First(&anonymized, feedbacks[2].ID).Error)
+		assert.Equal(t, AnonymizedContent, anonymized.Content)
+		assert.Nil(t, anonymized.AuthorID)
+		assert.NotNil(t, anonymized.AnonymizedAt)
+		assert.Equal(t, "", anonymized.Answers[0].Value)
+		assert.Equal(t, "Ann", anonymized.TargetName)
+
+		var notifications int64
+		db.Model(&models.Notification{}).Count(&notifications)
+		assert.Zero(t, notifications)
+
+		var rows []models.OutboxEvent
+		db.Order("id").Find(&rows)
+		types := make([]string, 0, len(rows))
+		for _, row := range rows {
+			types = append(types, row.Type)
+			assert.NotContains(t, row.Payload, "Old team note")
+			assert.NotContains(t, row.Payload, "Old person note")
+		}
+		assert.Equal(t, []string{events.FeedbackCreated, events.FeedbackCreated, events.FeedbackDeleted, events.FeedbackUpdated}, types)
+		assert.Contains(t, rows[0].Payload, "Recent team note")
+		assert.Contains(t, rows[1].Payload, "Held team note")
+	})
+
+	t.Run("should not process anonymized feedback again", func(t *testing.T) {
+		run, err := Apply(db, now, false, models.RetentionTriggerSchedule)
+		assert.NoError(t, err)
+		assert.Zero(t, run.Deleted+run.Anonymized)
+		assert.Equal(t, int64(1), run.Held)
+	})
+
+	t.Run("should report metrics for applied runs", func(t *testing.T) {
+		metrics, err := Metrics(db)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), metrics.Runs)
+		assert.Equal(t, int64(1), metrics.Deleted)
+		assert.Equal(t, int64(1), metrics.Anonymized)
+		assert.Equal(t, int64(1), metrics.LegalHolds)
+		assert.NotNil(t, metrics.LastRun)
+	})
+}
+
+func TestApplyWithoutRules(t *testing.T) {
+	db := setupTestDB(t)
+
+	run, err := Apply(db, time.Now(), false, models.RetentionTriggerManual)
+	assert.NoError(t, err)
+	assert.Zero(t, run.Scanned)
+	assert.NotZero(t, run.ID)
+
+	metrics, err := Metrics(db)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), metrics.Runs)
+}
@@ -0,0 +1,110 @@
+package sentiment
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+const (
+	Positive = "positive"
+	Neutral  = "neutral"
+	Negative = "negative"
+
+	threshold     = 0.05
+	normalization = 15.0
+	negationScope = 3
+)
+
+var lexicon = map[string]float64{
+	"amazing": 3, "awesome": 3, "brilliant": 3, "excellent": 3, "exceptional": 3, "fantastic": 3, "outstanding": 3, "superb": 3, "wonderful": 3,
+	"great": 2.5, "impressive": 2.5, "love": 2.5, "loved": 2.5,
+	"appreciate": 2, "appreciated": 2, "clear": 1.5, "collaborative": 2, "creative": 2, "dependable": 2, "effective": 2, "empathetic": 2,
+	"good": 2, "grateful": 2, "happy": 2, "helpful": 2, "insightful": 2, "kind": 2, "nice": 1.5, "proactive": 2, "proud": 2,
+	"reliable": 2, "strong": 1.5, "supportive": 2, "thank": 2, "thanks": 2, "thorough": 1.5, "thoughtful": 2,
+	"well": 1, "improved": 1.5, "improving": 1, "progress": 1, "solid": 1.5, "fast": 1, "responsive": 1.5, "organized": 1.5,
+	"calm": 1, "confident": 1.5, "engaged": 1.5, "focused": 1.5, "friendly": 1.5, "generous": 2, "patient": 1.5, "respectful": 2,
+	"success": 2, "successful": 2, "win": 2, "recommend": 1.5, "enjoy": 2, "enjoyed": 2,
+
+	"awful": -3, "horrible": -3, "terrible": -3, "toxic": -3, "unacceptable": -3, "disrespectful": -3, "hostile": -3,
+	"bad": -2.5, "poor": -2, "rude": -2.5, "hate": -3, "angry": -2, "annoying": -2, "careless": -2, "confusing": -1.5,
+	"defensive": -1.5, "difficult": -1.5, "disappointed": -2, "disappointing": -2, "dismissive": -2, "disorganized": -2,
+	"frustrated": -2, "frustrating": -2, "ignored": -2, "ignores": -2, "inconsistent": -1.5, "ineffective": -2,
+	"late": -1.5, "lazy": -2.5, "messy": -1.5, "missed": -1.5, "mistake": -1.5, "mistakes": -1.5, "negative": -2,
+	"problem": -1.5, "problems": -1.5, "slow": -1.5, "sloppy": -2, "struggle": -1.5, "struggled": -1.5, "struggles": -1.5,
+	"unclear": -1.5, "unhelpful": -2, "unprepared": -2, "unprofessional": -2.5, "unreliable": -2, "upset": -2,
+	"weak": -1.5, "worse": -2, "worst": -3, "wrong": -1.5, "fail": -2, "failed": -2, "failure": -2, "blame": -2, "blamed": -2,
+	"delay": -1, "delayed": -1.5, "interrupts": -1.5, "interrupted": -1.5, "overwhelmed": -1.5, "stressed": -1.5, "concern": -1, "concerns": -1,
+}
+
+var boosters = map[string]float64{
+	"very": 1.5, "really": 1.5, "extremely": 1.8, "incredibly": 1.8, "super": 1.5, "so": 1.3, "truly": 1.4, "highly": 1.5, "always": 1.3,
+	"slightly": 0.5, "somewhat": 0.6, "bit": 0.6, "kinda": 0.6, "barely": 0.4, "occasionally": 0.7, "sometimes": 0.8,
+}
+
+var negations = map[string]bool{
+	"not": true, "no": true, "never": true, "none": true, "nobody": true, "nothing": true, "neither": true, "nor": true,
+	"cannot": true, "without": true, "hardly": true, "dont": true, "doesnt": true, "didnt": true, "isnt": true,
+	"wasnt": true, "arent": true, "werent": true, "wont": true, "cant": true, "couldnt": true, "shouldnt": true, "wouldnt": true,
+}
+
+type Result struct {
+	Score float64
+	Label string
+}
+
+func Analyze(text string) Result {
+	words := tokenize(text)
+
+	sum := 0.0
+	for i, word := range words {
+		valence, ok := lexicon[word]
+		if !ok {
+			continue
+		}
+
+		for j := i - 1; j >= 0; j-- {
+			factor, ok := boosters[words[j]]
+			if !ok {
+				break
+			}
+			valence *= factor
+		}
+		for j := i - 1; j >= 0 && j >= i-negationScope; j-- {
+			if negations[words[j]] {
+				valence *= -0.75
+				break
+			}
+		}
+		sum += valence
+	}
+
+	score := sum / math.Sqrt(sum*sum+normalization)
+	score = math.Round(score*1000) / 1000
+
+	return Result{Score: score, Label: Label(score)}
+}
+
+func Label(score float64) string {
+	switch {
+	case score >= threshold:
+		return Positive
+	case score <= -threshold:
+		return Negative
+	default:
+		return Neutral
+	}
+}
+
+func IsLabel(value string) bool {
+	return value == Positive || value == Neutral || value == Negative
+}
+
+func tokenize(text string) []string {
+	text = strings.ToLower(strings.ReplaceAll(text, "’", "'"))
+	text = strings.ReplaceAll(text, "n't", "nt")
+
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+}
@@ -0,0 +1,59 @@
+package sentiment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyze(t *testing.T) {
+	t.Run("should score praise as positive", func(t *testing.T) {
+		result := Analyze("Great work on the project, your help was excellent!")
+
+		assert.Equal(t, Positive, result.Label)
+		assert.Greater(t, result.Score, 0.5)
+	})
+
+	t.Run("should score criticism as negative", func(t *testing.T) {
+		result := Analyze("The release was late and the handover was sloppy.")
+
+		assert.Equal(t, Negative, result.Label)
+		assert.Less(t, result.Score, -0.5)
+	})
+
+	t.Run("should score text without opinion as neutral", func(t *testing.T) {
+		result := Analyze("We discussed the roadmap for next quarter.")
+
+		assert.Equal(t, Neutral, result.Label)
+		assert.Equal(t, 0.0, result.Score)
+	})
+
+	t.Run("should flip negated words", func(t *testing.T) {
+		assert.Equal(t, Negative, Analyze("The presentation was not good").Label)
+		assert.Equal(t, Negative, Analyze("Your notes weren't helpful").Label)
+		assert.Equal(t, Positive, Analyze("No problems at all this sprint").Label)
+	})
+
+	t.Run("should weigh boosters and dampeners", func(t *testing.T) {
+		plain := Analyze("The demo was good").Score
+		boosted := Analyze("The demo was very good").Score
+		dampened := Analyze("The demo was slightly good").Score
+
+		assert.Greater(t, boosted, plain)
+		assert.Less(t, dampened, plain)
+	})
+
+	t.Run("should keep score within bounds", func(t *testing.T) {
+		result := Analyze("amazing amazing amazing amazing amazing amazing amazing amazing")
+
+		assert.LessOrEqual(t, result.Score, 1.0)
+		assert.Equal(t, Positive, result.Label)
+	})
+}
+
+func TestLabel(t *testing.T) {
+	assert.Equal(t, Positive, Label(0.05))
+	assert.Equal(t, Neutral, Label(0.01))
+	assert.Equal(t, Neutral, Label(-0.04))
+	assert.Equal(t, Negative, Label(-0.05))
+}
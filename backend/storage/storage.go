@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"coaching-backend/config"
+)
+
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+)
+
+var (
+	ErrNotFound   = errors.New("blob not found")
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// Store keeps blobs under slash-separated keys such as persons/1/ab12/64.jpg.
+type Store interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) (Blob, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type Blob struct {
+	Data        []byte
+	ContentType string
+}
+
+var Default Store = NewLocal("uploads")
+
+func Open(cfg *config.Config) (Store, error) {
+	switch cfg.StorageBackend {
+	case BackendLocal:
+		return NewLocal(cfg.StorageDir), nil
+	case BackendS3:
+		if cfg.S3Endpoint == "" || cfg.S3Bucket == "" {
+			return nil, errors.New("S3_ENDPOINT and S3_BUCKET are required for the s3 storage backend")
+		}
+		return NewS3(cfg.S3Endpoint, cfg.S3Bucket, cfg.S3Region, cfg.S3AccessKeyID, cfg.S3SecretAccessKey), nil
+	}
+	return nil, fmt.Errorf("unknown storage backend %q, expected local or s3", cfg.StorageBackend)
+}
+
+// CleanKey rejects keys that are empty, absolute or that leave the store.
+func CleanKey(key string) (string, error) {
+	if key == "" || path.IsAbs(key) || strings.Contains(key, "\\") || path.Clean(key) != key {
+		return "", ErrInvalidKey
+	}
+	if key == "." || key == ".." || strings.HasPrefix(key, "../") {
+		return "", ErrInvalidKey
+	}
+	return key, nil
+}
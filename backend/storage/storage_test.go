@@ -0,0 +1,172 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"coaching-backend/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeS3 is a local stand-in for an S3-compatible service. It checks the
+// signature of every request and keeps objects in memory.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]Blob
+	secret  string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	if !f.verify(r, body) {
+		http.Error(w, "<Error><Code>SignatureDoesNotMatch</Code></Error>", http.StatusForbidden)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		f.objects[r.URL.EscapedPath()] = Blob{Data: body, ContentType: r.Header.Get("Content-Type")}
+	case http.MethodGet:
+		blob, ok := f.objects[r.URL.EscapedPath()]
+		if !ok {
+			http.Error(w, "<Error><Code>NoSuchKey</Code></Error>", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", blob.ContentType)
+		w.Write(blob.Data)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.EscapedPath())
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (f *fakeS3) verify(r *http.Request, body []byte) bool {
+	hash := sha256.Sum256(body)
+	if r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(hash[:]) {
+		return false
+	}
+	signedAt, err := time.Parse("20060102T150405Z", r.Header.Get("X-Amz-Date"))
+	if err != nil {
+		return false
+	}
+
+	auth := r.Header.Get("Authorization")
+	_, signed, _ := strings.Cut(auth, "SignedHeaders=")
+	signed, _, _ = strings.Cut(signed, ",")
+
+	check, _ := http.NewRequest(r.Method, "http://"+r.Host+r.URL.RequestURI(), nil)
+	for _, name := range strings.Split(signed, ";") {
+		if name != "host" && name != "x-amz-date" {
+			check.Header.Set(name, r.Header.Get(name))
+		}
+	}
+	signV4(check, r.Header.Get("X-Amz-Content-Sha256"), "access", f.secret, "eu-west-1", "s3", signedAt)
+	return check.Header.Get("Authorization") == auth
+}
+
+func testStores(t *testing.T) map[string]Store {
+	server := httptest.NewServer(&fakeS3{objects: map[string]Blob{}, secret: "s3-secret"})
+	t.Cleanup(server.Close)
+
+	return map[string]Store{
+		"local": NewLocal(t.TempDir()),
+		"s3":    NewS3(server.URL, "media", "eu-west-1", "access", "s3-secret"),
+	}
+}
+
+func TestStores(t *testing.T) {
+	ctx := context.Background()
+
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			key := "persons/1/ab12 cd/64.png"
+
+			_, err := store.Get(ctx, key)
+			assert.ErrorIs(t, err, ErrNotFound)
+
+			assert.NoError(t, store.Put(ctx, key, []byte("first"), "image/png"))
+			assert.NoError(t, store.Put(ctx, key, []byte("png data"), "image/png"))
+
+			blob, err := store.Get(ctx, key)
+			assert.NoError(t, err)
+			assert.Equal(t, []byte("png data"), blob.Data)
+			assert.Equal(t, "image/png", blob.ContentType)
+
+			assert.NoError(t, store.Delete(ctx, key))
+			assert.NoError(t, store.Delete(ctx, key))
+			_, err = store.Get(ctx, key)
+			assert.ErrorIs(t, err, ErrNotFound)
+
+			for _, invalid := range []string{"", "/etc/passwd", "../secret", "persons/../../secret", "a//b", "a\\b"} {
+				assert.ErrorIs(t, store.Put(ctx, invalid, []byte("x"), "text/plain"), ErrInvalidKey, invalid)
+			}
+		})
+	}
+}
+
+func TestLocalWritesInsideDir(t *testing.T) {
+	dir := t.TempDir()
+	store := NewLocal(dir)
+
+	assert.NoError(t, store.Put(context.Background(), "teams/2/logo.jpg", []byte("jpeg"), "image/jpeg"))
+
+	data, err := os.ReadFile(filepath.Join(dir, "teams", "2", "logo.jpg"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("jpeg"), data)
+
+	entries, _ := os.ReadDir(filepath.Join(dir, "teams", "2"))
+	assert.Len(t, entries, 1)
+}
+
+func TestS3RejectsBadCredentials(t *testing.T) {
+	server := httptest.NewServer(&fakeS3{objects: map[string]Blob{}, secret: "s3-secret"})
+	defer server.Close()
+
+	store := NewS3(server.URL, "media", "eu-west-1", "access", "wrong")
+	err := store.Put(context.Background(), "a.png", []byte("x"), "image/png")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "403")
+}
+
+func TestSignV4(t *testing.T) {
+	// The example request from the AWS Signature Version 4 documentation.
+	req, _ := http.NewRequest("GET", "https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08", nil)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	emptyHash := sha256.Sum256(nil)
+
+	signV4(req, hex.EncodeToString(emptyHash[:]), "AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "us-east-1", "iam",
+		time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	assert.Equal(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+	assert.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request, "+
+		"SignedHeaders=content-type;host;x-amz-date, "+
+		"Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7", req.Header.Get("Authorization"))
+}
+
+func TestOpen(t *testing.T) {
+	store, err := Open(&config.Config{StorageBackend: BackendLocal, StorageDir: "media"})
+	assert.NoError(t, err)
+	assert.Equal(t, "media", store.(*Local).Dir)
+
+	store, err = Open(&config.Config{StorageBackend: BackendS3, S3Endpoint: "http://minio:9000/", S3Bucket: "media"})
+	assert.NoError(t, err)
+	assert.Equal(t, "http://minio:9000", store.(*S3).Endpoint)
+	assert.Equal(t, "us-east-1", store.(*S3).Region)
+
+	_, err = Open(&config.Config{StorageBackend: BackendS3})
+	assert.Error(t, err)
+	_, err = Open(&config.Config{StorageBackend: "ftp"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,170 @@
+package stream
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"coaching-backend/database"
+	"coaching-backend/events"
+	"coaching-backend/models"
+)
+
+var Default = NewHub()
+
+type Filter struct {
+	Types      []string
+	TargetType string
+	TargetID   uint
+	TeamID     uint
+}
+
+type subject struct {
+	ID             uint   `json:"id"`
+	TeamID         *uint  `json:"team_id"`
+	PreviousTeamID *uint  `json:"previous_team_id"`
+	TargetType     string `json:"target_type"`
+	TargetID       uint   `json:"target_id"`
+	Person         *struct {
+		ID     uint  `json:"id"`
+		TeamID *uint `json:"team_id"`
+	} `json:"person"`
+}
+
+type Hub struct {
+	mu      sync.Mutex
+	clients map[chan events.Event]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{clients: map[chan events.Event]struct{}{}}
+}
+
+func (h *Hub) Subscribe(buffer int) (<-chan events.Event, func()) {
+	ch := make(chan events.Event, buffer)
+
+	h.mu.Lock()
+	h.clients[ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.clients[ch]; ok {
+			delete(h.clients, ch)
+			close(ch)
+		}
+	}
+}
+
+func (h *Hub) Handle(event events.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.clients {
+		select {
+		case ch <- event:
+		default:
+			// A client that cannot keep up is disconnected; it resumes with Last-Event-ID.
+			delete(h.clients, ch)
+			close(ch)
+		}
+	}
+}
+
+func (h *Hub) Clients() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.clients)
+}
+
+func (f Filter) Matches(event events.Event) bool {
+	if len(f.Types) > 0 && !contains(f.Types, event.Type) {
+		return false
+	}
+	if f.TargetType == "" && f.TeamID == 0 {
+		return true
+	}
+
+	kind, _, found := strings.Cut(event.Type, ".")
+	s, ok := decodeSubject(event)
+	if !found || !ok {
+		return false
+	}
+
+	if f.TargetType != "" && !f.matchesTarget(kind, s) {
+		return false
+	}
+	if f.TeamID != 0 && !f.matchesTeam(kind, s) {
+		return false
+	}
+	return true
+}
+
+func (f Filter) matchesTarget(kind string, s subject) bool {
+	switch kind {
+	case "feedback":
+		return s.TargetType == f.TargetType && s.TargetID == f.TargetID
+	case "person":
+		return f.TargetType == "person" && personID(s) == f.TargetID
+	case "team":
+		return f.TargetType == "team" && s.ID == f.TargetID
+	}
+	return false
+}
+
+func (f Filter) matchesTeam(kind string, s subject) bool {
+	switch kind {
+	case "feedback":
+		if s.TargetType == "team" {
+			return s.TargetID == f.TeamID
+		}
+		var person models.Person
+		if err := database.GetDB().Select("team_id").First(&person, s.TargetID).Error; err != nil {
+			return false
+		}
+		return person.TeamID != nil && *person.TeamID == f.TeamID
+	case "person":
+		if s.TeamID != nil && *s.TeamID == f.TeamID {
+			return true
+		}
+		if s.PreviousTeamID != nil && *s.PreviousTeamID == f.TeamID {
+			return true
+		}
+		return s.Person != nil && s.Person.TeamID != nil && *s.Person.TeamID == f.TeamID
+	case "team":
+		return s.ID == f.TeamID
+	}
+	return false
+}
+
+func decodeSubject(event events.Event) (subject, bool) {
+	var s subject
+
+	raw, ok := event.Data.(json.RawMessage)
+	if !ok {
+		var err error
+		if raw, err = json.Marshal(event.Data); err != nil {
+			return s, false
+		}
+	}
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return s, false
+	}
+	return s, true
+}
+
+func personID(s subject) uint {
+	if s.Person != nil {
+		return s.Person.ID
+	}
+	return s.ID
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
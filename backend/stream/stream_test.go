@@ -0,0 +1,102 @@
+package stream
+
+import (
+	"encoding/json"
+	"testing"
+
+	"coaching-backend/database"
+	"coaching-backend/events"
+	"coaching-backend/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTestDB(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	database.DB = db
+}
+
+func event(eventType, data string) events.Event {
+	return events.Event{Type: eventType, Data: json.RawMessage(data)}
+}
+
+func TestFilterMatches(t *testing.T) {
+	setupTestDB(t)
+	team := models.Team{Name: "Filter Team"}
+	database.GetDB().Create(&team)
+	member := models.Person{Name: "Member", Email: "filter.member@example.com", TeamID: &team.ID}
+	database.GetDB().Create(&member)
+
+	teamFeedback := event(events.FeedbackCreated, `{"id":1,"target_type":"team","target_id":`+jsonID(team.ID)+`}`)
+	memberFeedback := event(events.FeedbackCreated, `{"id":2,"target_type":"person","target_id":`+jsonID(member.ID)+`}`)
+	otherFeedback := event(events.FeedbackDeleted, `{"id":3,"target_type":"person","target_id":999}`)
+	assigned := event(events.PersonAssignedToTeam, `{"id":`+jsonID(member.ID)+`,"team_id":`+jsonID(team.ID)+`}`)
+	removed := event(events.PersonRemovedFromTeam, `{"person":{"id":`+jsonID(member.ID)+`,"team_id":null},"team_id":`+jsonID(team.ID)+`}`)
+	teamUpdated := event(events.TeamUpdated, `{"id":`+jsonID(team.ID)+`}`)
+
+	t.Run("should match everything without a filter", func(t *testing.T) {
+		assert.True(t, Filter{}.Matches(otherFeedback))
+	})
+
+	t.Run("should match by type", func(t *testing.T) {
+		filter := Filter{Types: []string{events.FeedbackCreated}}
+		assert.True(t, filter.Matches(teamFeedback))
+		assert.False(t, filter.Matches(otherFeedback))
+	})
+
+	t.Run("should match by target", func(t *testing.T) {
+		filter := Filter{TargetType: "person", TargetID: member.ID}
+		assert.True(t, filter.Matches(memberFeedback))
+		assert.True(t, filter.Matches(assigned))
+		assert.True(t, filter.Matches(removed))
+		assert.False(t, filter.Matches(teamFeedback))
+		assert.False(t, filter.Matches(teamUpdated))
+	})
+
+	t.Run("should match by team", func(t *testing.T) {
+		filter := Filter{TeamID: team.ID}
+		assert.True(t, filter.Matches(teamFeedback))
+		assert.True(t, filter.Matches(memberFeedback))
+		assert.True(t, filter.Matches(assigned))
+		assert.True(t, filter.Matches(removed))
+		assert.True(t, filter.Matches(teamUpdated))
+		assert.False(t, filter.Matches(otherFeedback))
+	})
+
+	t.Run("should match moves away from the team", func(t *testing.T) {
+		moved := event(events.PersonAssignedToTeam, `{"id":`+jsonID(member.ID)+`,"team_id":999,"previous_team_id":`+jsonID(team.ID)+`}`)
+		assert.True(t, Filter{TeamID: team.ID}.Matches(moved))
+		assert.False(t, Filter{TeamID: 998}.Matches(moved))
+	})
+}
+
+func TestHub(t *testing.T) {
+	hub := NewHub()
+	fast, unsubscribeFast := hub.Subscribe(4)
+	defer unsubscribeFast()
+	slow, unsubscribeSlow := hub.Subscribe(1)
+	defer unsubscribeSlow()
+
+	hub.Handle(events.Event{Type: events.TeamCreated, Sequence: 1})
+	hub.Handle(events.Event{Type: events.TeamCreated, Sequence: 2})
+
+	assert.Equal(t, uint(1), (<-fast).Sequence)
+	assert.Equal(t, uint(2), (<-fast).Sequence)
+
+	assert.Equal(t, uint(1), (<-slow).Sequence)
+	_, open := <-slow
+	assert.False(t, open, "slow clients are disconnected")
+	assert.Equal(t, 1, hub.Clients())
+}
+
+func jsonID(id uint) string {
+	b, _ := json.Marshal(id)
+	return string(b)
+}
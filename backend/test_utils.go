@@ -23,7 +23,7 @@ func setupTestDB() *gorm.DB {
 		panic("Failed to connect to test database")
 	}
 
-	err = db.AutoMigrate(&models.Person{}, &models.Team{}, &models.Feedback{})
+	err = database.Migrate(db)
 	if err != nil {
 		panic("Failed to migrate test database")
 	}
@@ -0,0 +1,146 @@
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+const (
+	MaxBytes     = 5 << 20
+	MaxDimension = 6000
+	jpegQuality  = 85
+)
+
+// Sizes are the square thumbnail edges generated for every upload, smallest
+// first.
+var Sizes = []int{64, 128, 256}
+
+var (
+	ErrUnsupportedType = errors.New("Image must be a JPEG, PNG or GIF")
+	ErrInvalidImage    = errors.New("Image could not be decoded")
+	ErrTooLarge        = errors.New("Image is too large")
+)
+
+var decoders = map[string]func([]byte) (image.Image, error){
+	"image/jpeg": func(data []byte) (image.Image, error) { return jpeg.Decode(bytes.NewReader(data)) },
+	"image/png":  func(data []byte) (image.Image, error) { return png.Decode(bytes.NewReader(data)) },
+	"image/gif":  func(data []byte) (image.Image, error) { return gif.Decode(bytes.NewReader(data)) },
+}
+
+type Thumbnail struct {
+	Size        int
+	Data        []byte
+	ContentType string
+	Ext         string
+}
+
+// Generate crops an upload to a centered square and scales it to every size in
+// Sizes. The type is sniffed from the data, not taken from the client. JPEG
+// stays JPEG; PNG and GIF become PNG so transparency survives.
+func Generate(data []byte) ([]Thumbnail, error) {
+	if len(data) > MaxBytes {
+		return nil, ErrTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	decode, ok := decoders[contentType]
+	if !ok {
+		return nil, ErrUnsupportedType
+	}
+
+	// Check the dimensions before decoding, so a small file cannot claim a
+	// huge canvas.
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if config.Width > MaxDimension || config.Height > MaxDimension {
+		return nil, ErrTooLarge
+	}
+	img, err := decode(data)
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	square := crop(img)
+	thumbnails := make([]Thumbnail, 0, len(Sizes))
+	for _, size := range Sizes {
+		var buf bytes.Buffer
+		t := Thumbnail{Size: size}
+		if contentType == "image/jpeg" {
+			t.ContentType, t.Ext = "image/jpeg", ".jpg"
+			err = jpeg.Encode(&buf, Scale(square, size), &jpeg.Options{Quality: jpegQuality})
+		} else {
+			t.ContentType, t.Ext = "image/png", ".png"
+			err = png.Encode(&buf, Scale(square, size))
+		}
+		if err != nil {
+			return nil, err
+		}
+		t.Data = buf.Bytes()
+		thumbnails = append(thumbnails, t)
+	}
+	return thumbnails, nil
+}
+
+func crop(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	side := b.Dx()
+	if b.Dy() < side {
+		side = b.Dy()
+	}
+	offset := image.Pt(b.Min.X+(b.Dx()-side)/2, b.Min.Y+(b.Dy()-side)/2)
+
+	square := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(square, square.Bounds(), img, offset, draw.Src)
+	return square
+}
+
+// Scale resizes a square image to size×size. Each target pixel averages the
+// source pixels it covers, which keeps downscaled photos smooth; upscaling
+// repeats pixels.
+func Scale(src *image.RGBA, size int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	side := src.Bounds().Dx()
+	if side == 0 {
+		return dst
+	}
+
+	for y := 0; y < size; y++ {
+		y0, y1 := span(y, size, side)
+		for x := 0; x < size; x++ {
+			x0, x1 := span(x, size, side)
+
+			var sum [4]int
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					for c := 0; c < 4; c++ {
+						sum[c] += int(row[sx*4+c])
+					}
+				}
+			}
+			n := (y1 - y0) * (x1 - x0)
+			i := dst.PixOffset(x, y)
+			for c := 0; c < 4; c++ {
+				dst.Pix[i+c] = uint8((sum[c] + n/2) / n)
+			}
+		}
+	}
+	return dst
+}
+
+// span returns the source pixels covered by target pixel i, at least one.
+func span(i, size, side int) (int, int) {
+	start := i * side / size
+	end := (i + 1) * side / size
+	if end <= start {
+		end = start + 1
+	}
+	return start, end
+}
@@ -0,0 +1,116 @@
+package thumbnail
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func encodePNG(img image.Image) []byte {
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	return buf.Bytes()
+}
+
+// halves is red on the left and transparent blue on the right.
+func halves(width, height int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if x < width/2 {
+				img.Set(x, y, color.NRGBA{R: 255, A: 255})
+			} else {
+				img.Set(x, y, color.NRGBA{B: 255, A: 0})
+			}
+		}
+	}
+	return img
+}
+
+func TestGenerate(t *testing.T) {
+	t.Run("should crop to a centered square in every size", func(t *testing.T) {
+		thumbnails, err := Generate(encodePNG(halves(600, 300)))
+		assert.NoError(t, err)
+		assert.Len(t, thumbnails, len(Sizes))
+
+		for i, thumbnail := range thumbnails {
+			assert.Equal(t, Sizes[i], thumbnail.Size)
+			assert.Equal(t, "image/png", thumbnail.ContentType)
+			assert.Equal(t, ".png", thumbnail.Ext)
+
+			img, err := png.Decode(bytes.NewReader(thumbnail.Data))
+			assert.NoError(t, err)
+			assert.Equal(t, image.Rect(0, 0, thumbnail.Size, thumbnail.Size), img.Bounds())
+
+			_, _, _, leftAlpha := img.At(1, thumbnail.Size/2).RGBA()
+			_, _, _, rightAlpha := img.At(thumbnail.Size-2, thumbnail.Size/2).RGBA()
+			assert.Equal(t, uint32(0xffff), leftAlpha)
+			assert.Zero(t, rightAlpha)
+		}
+	})
+
+	t.Run("should keep JPEG uploads as JPEG", func(t *testing.T) {
+		var buf bytes.Buffer
+		jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 300, 400)), nil)
+
+		thumbnails, err := Generate(buf.Bytes())
+		assert.NoError(t, err)
+		assert.Equal(t, "image/jpeg", thumbnails[0].ContentType)
+		assert.Equal(t, ".jpg", thumbnails[0].Ext)
+
+		config, err := jpeg.DecodeConfig(bytes.NewReader(thumbnails[2].Data))
+		assert.NoError(t, err)
+		assert.Equal(t, 256, config.Width)
+	})
+
+	t.Run("should convert GIF uploads to PNG", func(t *testing.T) {
+		var buf bytes.Buffer
+		gif.Encode(&buf, image.NewPaletted(image.Rect(0, 0, 10, 10), color.Palette{color.Black, color.White}), nil)
+
+		thumbnails, err := Generate(buf.Bytes())
+		assert.NoError(t, err)
+		assert.Equal(t, "image/png", thumbnails[0].ContentType)
+	})
+
+	t.Run("should reject other files", func(t *testing.T) {
+		_, err := Generate([]byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"))
+		assert.ErrorIs(t, err, ErrUnsupportedType)
+
+		_, err = Generate([]byte("%PDF-1.4"))
+		assert.ErrorIs(t, err, ErrUnsupportedType)
+
+		data := encodePNG(halves(20, 20))
+		_, err = Generate(data[:len(data)/2])
+		assert.ErrorIs(t, err, ErrInvalidImage)
+	})
+
+	t.Run("should reject oversized images", func(t *testing.T) {
+		_, err := Generate(encodePNG(image.NewGray(image.Rect(0, 0, MaxDimension+1, 1))))
+		assert.ErrorIs(t, err, ErrTooLarge)
+
+		_, err = Generate(make([]byte, MaxBytes+1))
+		assert.ErrorIs(t, err, ErrTooLarge)
+	})
+}
+
+func TestScale(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for i := range src.Pix {
+		src.Pix[i] = 255
+	}
+	src.SetRGBA(0, 0, color.RGBA{A: 255})
+
+	small := Scale(src, 2)
+	assert.Equal(t, color.RGBA{R: 191, G: 191, B: 191, A: 255}, small.RGBAAt(0, 0))
+	assert.Equal(t, color.RGBA{R: 255, G: 255, B: 255, A: 255}, small.RGBAAt(1, 1))
+
+	large := Scale(src, 8)
+	assert.Equal(t, color.RGBA{A: 255}, large.RGBAAt(1, 1))
+	assert.Equal(t, color.RGBA{R: 255, G: 255, B: 255, A: 255}, large.RGBAAt(2, 2))
+}
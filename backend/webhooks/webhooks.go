@@ -0,0 +1,270 @@
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+	"coaching-backend/database"
+	"coaching-backend/events"
+	"coaching-backend/models"
+	"coaching-backend/outbox"
+	"gorm.io/gorm"
+)
+
+const (
+	TestEvent = "webhook.test"
+
+	SignatureHeader = "X-Webhook-Signature"
+	TimestampHeader = "X-Webhook-Timestamp"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Id"
+
+	PollInterval = 5 * time.Second
+
+	maxErrorBody = 1024
+)
+
+var DefaultBackoff = []time.Duration{5 * time.Second, 30 * time.Second, 2 * time.Minute, 10 * time.Minute}
+
+var Default = NewDispatcher()
+
+type Dispatcher struct {
+	Client  *http.Client
+	Backoff []time.Duration
+	mu      sync.Mutex
+	wg      sync.WaitGroup
+}
+
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{
+		Client:  &http.Client{Timeout: 10 * time.Second},
+		Backoff: DefaultBackoff,
+	}
+}
+
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func Matches(sub models.WebhookSubscription, eventType string) bool {
+	if len(sub.Events) == 0 {
+		return true
+	}
+	for _, e := range sub.Events {
+		if e == "*" || e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle queues the event for every matching subscription. Deliveries and their
+// retries are stored, so they survive a restart, and Process makes them.
+func (d *Dispatcher) Handle(event events.Event) {
+	var subs []models.WebhookSubscription
+	if err := database.GetDB().Where("active = ?", true).Find(&subs).Error; err != nil {
+		log.Printf("webhooks: failed to load subscriptions for %s: %v", event.Type, err)
+		return
+	}
+
+	queued := 0
+	for _, sub := range subs {
+		if !Matches(sub, event.Type) || queuedOrDelivered(sub.ID, event.ID) {
+			continue
+		}
+		now := time.Now()
+		delivery := models.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			EventSequence:  event.Sequence,
+			Attempt:        1,
+			NextAttemptAt:  &now,
+		}
+		if err := database.GetDB().Create(&delivery).Error; err != nil {
+			log.Printf("webhooks: failed to queue %s for subscription %d: %v", event.ID, sub.ID, err)
+			continue
+		}
+		queued++
+	}
+
+	if queued > 0 {
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			if _, err := d.Process(); err != nil {
+				log.Printf("webhooks: failed to process deliveries: %v", err)
+			}
+		}()
+	}
+}
+
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+func (d *Dispatcher) Run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.Process(); err != nil {
+			log.Printf("webhooks: failed to process deliveries: %v", err)
+		}
+		<-ticker.C
+	}
+}
+
+// Process makes every queued delivery that is due and returns how many it
+// attempted. Each subscription gets its events one at a time in the order they
+// occurred, so an event waiting for a retry holds back the ones after it.
+func (d *Dispatcher) Process() (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var subscriptionIDs []uint
+	if err := database.GetDB().Model(&models.WebhookDelivery{}).Where("next_attempt_at IS NOT NULL").
+		Distinct().Pluck("subscription_id", &subscriptionIDs).Error; err != nil {
+		return 0, err
+	}
+
+	attempted := 0
+	for _, id := range subscriptionIDs {
+		var sub models.WebhookSubscription
+		if err := database.GetDB().First(&sub, id).Error; err != nil {
+			return attempted, err
+		}
+
+		for {
+			var next models.WebhookDelivery
+			result := database.GetDB().Where("subscription_id = ? AND next_attempt_at IS NOT NULL", sub.ID).
+				Order("event_sequence, id").Limit(1).Find(&next)
+			if result.Error != nil {
+				return attempted, result.Error
+			}
+			if result.RowsAffected == 0 || next.NextAttemptAt.After(time.Now()) {
+				break
+			}
+			if err := d.deliver(sub, next); err != nil {
+				return attempted, err
+			}
+			attempted++
+		}
+	}
+	return attempted, nil
+}
+
+// deliver makes a queued attempt and, if it failed with a retryable error,
+// queues the next one after the backoff.
+func (d *Dispatcher) deliver(sub models.WebhookSubscription, delivery models.WebhookDelivery) error {
+	retry := false
+	event, err := loadEvent(delivery.EventID)
+	if err != nil {
+		delivery.Error = fmt.Sprintf("event is no longer available: %v", err)
+	} else if body, err := json.Marshal(event); err != nil {
+		delivery.Error = fmt.Sprintf("failed to encode payload: %v", err)
+	} else {
+		retry = d.send(sub, event, body, &delivery)
+	}
+	delivery.NextAttemptAt = nil
+
+	return database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&delivery).Error; err != nil {
+			return err
+		}
+		if delivery.Success || !retry || delivery.Attempt > len(d.Backoff) {
+			return nil
+		}
+		next := time.Now().Add(d.Backoff[delivery.Attempt-1])
+		return tx.Create(&models.WebhookDelivery{
+			SubscriptionID: delivery.SubscriptionID,
+			EventID:        delivery.EventID,
+			EventType:      delivery.EventType,
+			EventSequence:  delivery.EventSequence,
+			Attempt:        delivery.Attempt + 1,
+			NextAttemptAt:  &next,
+		}).Error
+	})
+}
+
+// Attempt sends a single delivery right away and records it.
+func (d *Dispatcher) Attempt(sub models.WebhookSubscription, event events.Event, body []byte, attempt int) (models.WebhookDelivery, bool) {
+	delivery := models.WebhookDelivery{
+		SubscriptionID: sub.ID,
+		EventID:        event.ID,
+		EventType:      event.Type,
+		Attempt:        attempt,
+	}
+	retry := d.send(sub, event, body, &delivery)
+	if err := database.GetDB().Create(&delivery).Error; err != nil {
+		log.Printf("webhooks: failed to record delivery of %s to subscription %d: %v", delivery.EventID, delivery.SubscriptionID, err)
+	}
+	return delivery, retry
+}
+
+// send posts the body and fills in the outcome. It reports whether a failure is
+// worth retrying.
+func (d *Dispatcher) send(sub models.WebhookSubscription, event events.Event, body []byte, delivery *models.WebhookDelivery) bool {
+	req, err := http.NewRequest(http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		delivery.Error = err.Error()
+		return false
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "coaching-backend-webhooks")
+	req.Header.Set(EventHeader, event.Type)
+	req.Header.Set(DeliveryHeader, event.ID)
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(sub.Secret, timestamp, body))
+
+	start := time.Now()
+	resp, err := d.Client.Do(req)
+	delivery.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		delivery.Error = err.Error()
+		return true
+	}
+	defer resp.Body.Close()
+
+	delivery.StatusCode = resp.StatusCode
+	delivery.Success = resp.StatusCode >= 200 && resp.StatusCode < 300
+	if !delivery.Success {
+		excerpt, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		delivery.Error = fmt.Sprintf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(excerpt))
+	}
+
+	return resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusRequestTimeout
+}
+
+func loadEvent(eventID string) (events.Event, error) {
+	var row models.OutboxEvent
+	if err := database.GetDB().Where("event_id = ?", eventID).First(&row).Error; err != nil {
+		return events.Event{}, err
+	}
+	return outbox.ToEvent(row), nil
+}
+
+// queuedOrDelivered reports whether the event is already on its way to the
+// subscription, so an event published twice is delivered once.
+func queuedOrDelivered(subscriptionID uint, eventID string) bool {
+	var count int64
+	database.GetDB().Model(&models.WebhookDelivery{}).
+		Where("subscription_id = ? AND event_id = ? AND (success = ? OR next_attempt_at IS NOT NULL)", subscriptionID, eventID, true).
+		Count(&count)
+	return count > 0
+}
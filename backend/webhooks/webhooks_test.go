@@ -0,0 +1,319 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"coaching-backend/database"
+	"coaching-backend/events"
+	"coaching-backend/models"
+	"coaching-backend/outbox"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTestDB(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("Failed to access test database: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	database.DB = db
+}
+
+func createTestSubscription(t *testing.T, url string, eventTypes ...string) models.WebhookSubscription {
+	sub := models.WebhookSubscription{URL: url, Secret: "a-very-secret-value", Events: eventTypes, Active: true}
+	if err := database.GetDB().Create(&sub).Error; err != nil {
+		t.Fatalf("Failed to create subscription: %v", err)
+	}
+	return sub
+}
+
+func testDispatcher() *Dispatcher {
+	d := NewDispatcher()
+	d.Backoff = []time.Duration{time.Millisecond, time.Millisecond}
+	return d
+}
+
+// publishTestEvent stores an event in the outbox, where deliveries load it from.
+func publishTestEvent(t *testing.T, eventType string, data interface{}) events.Event {
+	if err := outbox.Add(database.GetDB(), eventType, data); err != nil {
+		t.Fatalf("Failed to add event: %v", err)
+	}
+	var row models.OutboxEvent
+	database.GetDB().Order("id desc").First(&row)
+	return outbox.ToEvent(row)
+}
+
+// drain processes deliveries until none are queued.
+func drain(t *testing.T, d *Dispatcher) {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		d.Wait()
+		if _, err := d.Process(); err != nil {
+			t.Fatalf("Failed to process deliveries: %v", err)
+		}
+
+		var queued int64
+		database.GetDB().Model(&models.WebhookDelivery{}).Where("next_attempt_at IS NOT NULL").Count(&queued)
+		if queued == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d deliveries still queued", queued)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func lastDelivery() models.WebhookDelivery {
+	var delivery models.WebhookDelivery
+	database.GetDB().Order("id desc").First(&delivery)
+	return delivery
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"type":"team.created"}`)
+
+	signature := Sign("secret", 1700000000, body)
+
+	assert.Equal(t, Sign("secret", 1700000000, body), signature)
+	assert.Regexp(t, `^sha256=[0-9a-f]{64}$`, signature)
+	assert.NotEqual(t, signature, Sign("other", 1700000000, body))
+	assert.NotEqual(t, signature, Sign("secret", 1700000001, body))
+}
+
+func TestMatches(t *testing.T) {
+	assert.True(t, Matches(models.WebhookSubscription{}, events.TeamCreated))
+	assert.True(t, Matches(models.WebhookSubscription{Events: []string{"*"}}, events.TeamCreated))
+	assert.True(t, Matches(models.WebhookSubscription{Events: []string{events.TeamCreated}}, events.TeamCreated))
+	assert.False(t, Matches(models.WebhookSubscription{Events: []string{events.FeedbackCreated}}, events.TeamCreated))
+}
+
+func TestDeliver(t *testing.T) {
+	t.Run("should sign the payload", func(t *testing.T) {
+		setupTestDB(t)
+
+		var received *http.Request
+		var body []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r
+			body, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		sub := createTestSubscription(t, server.URL)
+		event := publishTestEvent(t, events.TeamCreated, map[string]interface{}{"id": 7, "name": "Platform"})
+
+		d := testDispatcher()
+		d.Handle(event)
+		drain(t, d)
+
+		delivery := lastDelivery()
+		assert.True(t, delivery.Success)
+		assert.Equal(t, http.StatusNoContent, delivery.StatusCode)
+		assert.Equal(t, events.TeamCreated, received.Header.Get(EventHeader))
+		assert.Equal(t, event.ID, received.Header.Get(DeliveryHeader))
+
+		timestamp, err := strconv.ParseInt(received.Header.Get(TimestampHeader), 10, 64)
+		assert.NoError(t, err)
+		assert.Equal(t, Sign(sub.Secret, timestamp, body), received.Header.Get(SignatureHeader))
+
+		var payload events.Event
+		assert.NoError(t, json.Unmarshal(body, &payload))
+		assert.Equal(t, event.ID, payload.ID)
+		assert.Equal(t, event.Sequence, payload.Sequence)
+		assert.Equal(t, events.TeamCreated, payload.Type)
+	})
+
+	t.Run("should retry server errors with backoff", func(t *testing.T) {
+		setupTestDB(t)
+
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				http.Error(w, "try again", http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		createTestSubscription(t, server.URL)
+
+		d := testDispatcher()
+		d.Handle(publishTestEvent(t, events.PersonCreated, nil))
+		drain(t, d)
+
+		var log []models.WebhookDelivery
+		database.GetDB().Order("attempt").Find(&log)
+		assert.Len(t, log, 3)
+		assert.False(t, log[0].Success)
+		assert.Equal(t, http.StatusServiceUnavailable, log[0].StatusCode)
+		assert.Contains(t, log[0].Error, "try again")
+		assert.Nil(t, log[0].NextAttemptAt)
+		assert.True(t, log[2].Success)
+		assert.Equal(t, 3, log[2].Attempt)
+	})
+
+	t.Run("should give up after the last backoff", func(t *testing.T) {
+		setupTestDB(t)
+
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		createTestSubscription(t, server.URL)
+
+		d := testDispatcher()
+		d.Handle(publishTestEvent(t, events.PersonCreated, nil))
+		drain(t, d)
+
+		assert.False(t, lastDelivery().Success)
+		assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("should not retry client errors", func(t *testing.T) {
+		setupTestDB(t)
+
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusGone)
+		}))
+		defer server.Close()
+
+		createTestSubscription(t, server.URL)
+
+		d := testDispatcher()
+		d.Handle(publishTestEvent(t, events.PersonCreated, nil))
+		drain(t, d)
+
+		assert.False(t, lastDelivery().Success)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("should keep events in order while retrying", func(t *testing.T) {
+		setupTestDB(t)
+
+		var mu sync.Mutex
+		var received []string
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			mu.Lock()
+			received = append(received, r.Header.Get(EventHeader))
+			mu.Unlock()
+		}))
+		defer server.Close()
+
+		createTestSubscription(t, server.URL)
+
+		d := testDispatcher()
+		d.Backoff = []time.Duration{50 * time.Millisecond}
+		d.Handle(publishTestEvent(t, events.TeamCreated, nil))
+		d.Wait()
+		d.Handle(publishTestEvent(t, events.TeamUpdated, nil))
+		d.Handle(publishTestEvent(t, events.TeamDeleted, nil))
+		drain(t, d)
+
+		assert.Equal(t, []string{events.TeamCreated, events.TeamUpdated, events.TeamDeleted}, received)
+	})
+
+	t.Run("should pick up queued deliveries after a restart", func(t *testing.T) {
+		setupTestDB(t)
+
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+		}))
+		defer server.Close()
+
+		sub := createTestSubscription(t, server.URL)
+		event := publishTestEvent(t, events.PersonCreated, nil)
+		due := time.Now().Add(-time.Minute)
+		database.GetDB().Create(&models.WebhookDelivery{
+			SubscriptionID: sub.ID, EventID: event.ID, EventType: event.Type, EventSequence: event.Sequence, Attempt: 2, NextAttemptAt: &due,
+		})
+
+		attempted, err := testDispatcher().Process()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, attempted)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+		assert.True(t, lastDelivery().Success)
+	})
+}
+
+func TestHandle(t *testing.T) {
+	setupTestDB(t)
+
+	var teamEvents, allEvents int32
+	teamServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&teamEvents, 1)
+	}))
+	defer teamServer.Close()
+	allServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&allEvents, 1)
+	}))
+	defer allServer.Close()
+
+	createTestSubscription(t, teamServer.URL, events.TeamCreated, events.TeamDeleted)
+	createTestSubscription(t, allServer.URL)
+	inactive := createTestSubscription(t, allServer.URL)
+	database.GetDB().Model(&inactive).Update("active", false)
+
+	d := testDispatcher()
+	d.Handle(publishTestEvent(t, events.TeamCreated, nil))
+	d.Handle(publishTestEvent(t, events.PersonCreated, nil))
+	d.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&teamEvents))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&allEvents))
+
+	var delivered int64
+	database.GetDB().Model(&models.WebhookDelivery{}).Where("success = ?", true).Count(&delivered)
+	assert.Equal(t, int64(3), delivered)
+}
+
+func TestHandleSkipsDeliveredEvents(t *testing.T) {
+	setupTestDB(t)
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer server.Close()
+
+	createTestSubscription(t, server.URL)
+	event := publishTestEvent(t, events.TeamUpdated, nil)
+
+	d := testDispatcher()
+	d.Handle(event)
+	d.Wait()
+	d.Handle(event)
+	d.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}